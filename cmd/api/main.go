@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"shift-scheduling-v2/config"
 	"shift-scheduling-v2/internal/handler"
+	"shift-scheduling-v2/internal/realtime"
 	"shift-scheduling-v2/internal/repository"
 	"shift-scheduling-v2/internal/router"
 	"shift-scheduling-v2/internal/service"
 	"shift-scheduling-v2/pkg/cache"
 	"shift-scheduling-v2/pkg/jwt"
 	"shift-scheduling-v2/pkg/logger"
+	"shift-scheduling-v2/pkg/notify"
 
 	"log"
 	"os"
@@ -44,6 +46,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Canlı olayların sunucular arası dağıtımı
+	eventsCtx, stopEvents := context.WithCancel(context.Background())
+	defer stopEvents()
+	realtime.Start(eventsCtx)
+
 	// JWT yapılandırmasını başlat
 	jwt.Init(&cfg.JWT)
 
@@ -64,21 +71,70 @@ func main() {
 	authRepo := repository.NewAuthRepository(db)
 	doctorRepo := repository.NewDoctorRepository(db)
 	shiftRepo := repository.NewShiftRepository(db)
+	swapRepo := repository.NewShiftSwapRepository(db)
+	offerRepo := repository.NewShiftOfferRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	leaveRepo := repository.NewLeaveRepository(db)
+	calendarRepo := repository.NewHolidayCalendarRepository(db)
+	locationRepo := repository.NewLocationRepository(db)
+	scheduleRepo := repository.NewScheduleRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	feedRepo := repository.NewCalendarFeedRepository(db)
+	importRepo := repository.NewImportRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
+
+	// Bildirim kanalları
+	channels := []notify.Channel{service.NewInAppChannel(notificationRepo)}
+	if smtpCfg := cfg.Notify.SMTP; smtpCfg.Host != "" {
+		channels = append(channels, notify.NewSMTPChannel(smtpCfg.Host, smtpCfg.Port, smtpCfg.Username, smtpCfg.Password, smtpCfg.From))
+	}
+	if webhookCfg := cfg.Notify.Webhook; webhookCfg.URL != "" {
+		channels = append(channels, notify.NewWebhookChannel(webhookCfg.URL, webhookCfg.Secret, time.Duration(webhookCfg.TimeoutSec)*time.Second))
+	}
+	dispatcher := notify.NewDispatcher(cfg.Notify.Retries, time.Duration(cfg.Notify.BackoffMs)*time.Millisecond, channels...)
+	logger.Info("Bildirim kanalları: %v", dispatcher.Channels())
 
 	// Service'ler
-	authService := service.NewAuthService(authRepo, userRepo)
-	userService := service.NewUserService(userRepo)
-	doctorService := service.NewDoctorService(doctorRepo, userRepo)
-	shiftService := service.NewShiftService(shiftRepo)
+	auditService := service.NewAuditService(auditRepo, cfg.Audit.RetentionDays)
+	auditService.StartRetention(eventsCtx)
+	authService := service.NewAuthService(authRepo, userRepo, auditService)
+	userService := service.NewUserService(userRepo, auditService)
+	doctorService := service.NewDoctorService(doctorRepo, userRepo, auditService)
+	notificationService := service.NewNotificationService(notificationRepo, doctorRepo, dispatcher)
+	shiftService := service.NewShiftService(shiftRepo, doctorRepo, locationRepo, calendarRepo, scheduleRepo, notificationService, auditService)
+	swapService := service.NewShiftSwapService(swapRepo, shiftRepo, doctorRepo, shiftService, notificationService)
+	streamService := service.NewStreamService(doctorRepo)
+	leaveService := service.NewLeaveService(leaveRepo, shiftRepo, doctorRepo, shiftService, notificationService, auditService)
+	offerService := service.NewShiftOfferService(offerRepo, swapRepo, shiftRepo, doctorRepo, shiftService, notificationService)
+	calendarService := service.NewHolidayCalendarService(calendarRepo, auditService)
+	locationService := service.NewLocationService(locationRepo, doctorRepo)
+	scheduleService := service.NewScheduleService(shiftRepo, scheduleRepo, doctorRepo, shiftService, notificationService)
+	portalService := service.NewDoctorPortalService(shiftRepo, doctorRepo, leaveRepo, swapRepo, shiftService)
+	feedService := service.NewCalendarFeedService(feedRepo, shiftRepo, doctorRepo, locationRepo, shiftService, cfg.App.PublicURL)
+	importService := service.NewImportService(importRepo, userRepo, doctorRepo, locationRepo, auditService)
+	analyticsService := service.NewAnalyticsService(analyticsRepo, doctorRepo, locationRepo, shiftService)
 
 	// Handler'lar
 	authHandler := handler.NewAuthHandler(authService)
 	userHandler := handler.NewUserHandler(userService)
 	doctorHandler := handler.NewDoctorHandler(doctorService)
 	shiftHandler := handler.NewShiftHandler(shiftService, doctorService)
+	swapHandler := handler.NewShiftSwapHandler(swapService)
+	offerHandler := handler.NewShiftOfferHandler(offerService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	streamHandler := handler.NewStreamHandler(streamService)
+	leaveHandler := handler.NewLeaveHandler(leaveService)
+	calendarHandler := handler.NewHolidayCalendarHandler(calendarService)
+	locationHandler := handler.NewLocationHandler(locationService)
+	scheduleHandler := handler.NewScheduleHandler(scheduleService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	portalHandler := handler.NewDoctorPortalHandler(portalService)
+	feedHandler := handler.NewCalendarFeedHandler(feedService)
+	importHandler := handler.NewImportHandler(importService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
 
 	// Router'ı oluştur ve yapılandır
-	r := router.NewRouter(authHandler, userHandler, doctorHandler, shiftHandler)
+	r := router.NewRouter(authHandler, userHandler, doctorHandler, shiftHandler, swapHandler, offerHandler, notificationHandler, streamHandler, leaveHandler, calendarHandler, locationHandler, scheduleHandler, auditHandler, portalHandler, feedHandler, importHandler, analyticsHandler)
 	r.SetupRoutes()
 
 	// Graceful shutdown için kanal oluştur
@@ -104,6 +160,10 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.App.ShutdownTimeout)*time.Second)
 	defer cancel()
 
+	// Açık canlı akışları kapat, aksi halde sunucu bağlantıların bitmesini bekler
+	stopEvents()
+	realtime.Stop()
+
 	// Sunucuyu durdur
 	if err = r.GetApp().ShutdownWithContext(ctx); err != nil {
 		logger.Error("Sunucu kapatma hatası: %v", err)
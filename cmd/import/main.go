@@ -0,0 +1,102 @@
+// Doktor, lokasyon üyeliği ve izin CSV dosyalarını API ile aynı doğrulamadan geçirerek içe aktarır.
+//
+//	go run ./cmd/import -doctors doktorlar.csv -memberships uyelikler.csv -holidays izinler.csv [-skip-invalid]
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"shift-scheduling-v2/config"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/logger"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+func main() {
+	doctorsPath := flag.String("doctors", "", "doktor ve kullanıcı CSV dosyası")
+	membershipsPath := flag.String("memberships", "", "lokasyon üyeliği CSV dosyası")
+	holidaysPath := flag.String("holidays", "", "izin CSV dosyası")
+	skipInvalid := flag.Bool("skip-invalid", false, "hatalı satırları atla, geçerli satırları ekle")
+	flag.Parse()
+
+	if err := run(*doctorsPath, *membershipsPath, *holidaysPath, *skipInvalid); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(doctorsPath string, membershipsPath string, holidaysPath string, skipInvalid bool) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("config yükleme hatası: %v", err)
+	}
+	if err = logger.Init(cfg.App.LogDir); err != nil {
+		return fmt.Errorf("logger başlatma hatası: %v", err)
+	}
+
+	input := service.ImportInput{SkipInvalid: skipInvalid}
+	for _, f := range []struct {
+		path   string
+		target *io.Reader
+	}{{doctorsPath, &input.Doctors}, {membershipsPath, &input.Memberships}, {holidaysPath, &input.Holidays}} {
+		if f.path == "" {
+			continue
+		}
+		file, err := os.Open(f.path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		*f.target = file
+	}
+
+	sqldb := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(cfg.Database.GetDSN())))
+	db := bun.NewDB(sqldb, pgdialect.New())
+	defer db.Close()
+	if err = db.Ping(); err != nil {
+		return fmt.Errorf("veritabanı bağlantı hatası: %v", err)
+	}
+
+	auditService := service.NewAuditService(repository.NewAuditRepository(db), cfg.Audit.RetentionDays)
+	importService := service.NewImportService(
+		repository.NewImportRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewDoctorRepository(db),
+		repository.NewLocationRepository(db),
+		auditService,
+	)
+
+	report, err := importService.Import(context.Background(), input)
+	if err != nil {
+		var e *errorx.Error
+		if errors.As(err, &e) && len(e.Details) > 0 {
+			for _, d := range e.Details {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", d.Field, d.Message)
+			}
+			return fmt.Errorf("içe aktarma uygulanmadı: %s", e.Message)
+		}
+		return err
+	}
+
+	for _, file := range report.Files {
+		fmt.Printf("%s: %d satır, %d eklendi, %d atlandı\n", file.File, file.Rows, file.Imported, file.Rows-file.Imported)
+		for _, skipped := range file.Skipped {
+			column := ""
+			if skipped.Column != "" {
+				column = " " + skipped.Column
+			}
+			fmt.Printf("  satır %d%s: %s\n", skipped.Row, column, skipped.Message)
+		}
+	}
+	return nil
+}
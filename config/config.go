@@ -12,6 +12,8 @@ type Config struct {
 	Database DatabaseConfig
 	Redis    RedisConfig
 	JWT      JWTConfig
+	Notify   NotifyConfig `mapstructure:"notification"`
+	Audit    AuditConfig
 }
 
 type AppConfig struct {
@@ -21,6 +23,7 @@ type AppConfig struct {
 	Env             string
 	ShutdownTimeout int    `mapstructure:"shutdown_timeout"`
 	LogDir          string `mapstructure:"log_dir"`
+	PublicURL       string `mapstructure:"public_url"` // takvim abonelik adresleri için, boşsa göreli yol döner
 }
 
 type DatabaseConfig struct {
@@ -50,6 +53,33 @@ type JWTConfig struct {
 	RefreshExpiration int    `mapstructure:"jwt_refresh_expiration"` // Saat cinsinden
 }
 
+// Bildirim kanalları. Host ya da URL boş bırakılan kanal kullanılmaz, uygulama içi kanal her zaman açıktır.
+type NotifyConfig struct {
+	Retries   int `mapstructure:"retries"`    // kanal başına toplam deneme
+	BackoffMs int `mapstructure:"backoff_ms"` // ilk bekleme, her denemede ikiye katlanır
+	SMTP      SMTPConfig
+	Webhook   WebhookConfig
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type WebhookConfig struct {
+	URL        string
+	Secret     string
+	TimeoutSec int `mapstructure:"timeout_sec"`
+}
+
+// Denetim kayıtları. RetentionDays 0 ise kayıtlar hiç silinmez.
+type AuditConfig struct {
+	RetentionDays int `mapstructure:"retention_days"`
+}
+
 func LoadConfig() (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// İstek bağlamında tutulan değerlerin anahtarları. Fiber Locals değerleri
+// c.Context() üzerinden context.Context.Value ile okunabilir.
+const (
+	ActorKey    = "userID"
+	ClientIPKey = "clientIP"
+)
+
+// İşlemi yapan kullanıcı. Arka plan işlerinde 0 döner.
+func Actor(ctx context.Context) int64 {
+	if ctx == nil {
+		return 0
+	}
+	id, _ := ctx.Value(ActorKey).(int64)
+	return id
+}
+
+// İsteğin geldiği adres
+func ClientIP(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	ip, _ := ctx.Value(ClientIPKey).(string)
+	return ip
+}
+
+// Kaydın önceki ya da sonraki halini JSON olarak döner, boş değerler için nil
+func Encode(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "null" {
+		return nil, nil
+	}
+	return data, nil
+}
+
+// Saklama süresi dolan kayıtların sınırı. days 0 ya da negatifse kayıtlar silinmez ve false döner.
+func Cutoff(now time.Time, days int) (time.Time, bool) {
+	if days <= 0 {
+		return time.Time{}, false
+	}
+	return now.AddDate(0, 0, -days), true
+}
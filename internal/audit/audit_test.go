@@ -0,0 +1,62 @@
+package audit_test
+
+import (
+	"net/http/httptest"
+	"shift-scheduling-v2/internal/audit"
+	"shift-scheduling-v2/internal/middleware"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEncodeSkipsEmptyValues(t *testing.T) {
+	raw, err := audit.Encode(nil)
+	require.NoError(t, err)
+	assert.Nil(t, raw)
+
+	var shift *struct{ ID int64 }
+	raw, err = audit.Encode(shift)
+	require.NoError(t, err)
+	assert.Nil(t, raw)
+
+	raw, err = audit.Encode(map[string]any{"id": 7})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":7}`, string(raw))
+}
+
+func TestAuditCutoff(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	_, ok := audit.Cutoff(now, 0)
+	assert.False(t, ok)
+
+	cutoff, ok := audit.Cutoff(now, 30)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 2, 8, 12, 0, 0, 0, time.UTC), cutoff)
+}
+
+func TestAuditActorAndClientIPFromRequestContext(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.ClientIP())
+
+	var actor int64
+	var ip string
+	app.Get("/", func(c *fiber.Ctx) error {
+		c.Locals(audit.ActorKey, int64(42))
+		actor = audit.Actor(c.Context())
+		ip = audit.ClientIP(c.Context())
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, int64(42), actor)
+	assert.NotEmpty(t, ip)
+
+	assert.Zero(t, audit.Actor(nil))
+	assert.Empty(t, audit.ClientIP(nil))
+}
@@ -0,0 +1,55 @@
+package dto
+
+// Lokasyonun bir tarih aralığındaki nöbet yükü ve adalet göstergeleri
+type LocationWorkloadDTO struct {
+	LocationID int64               `json:"location_id"`
+	Location   string              `json:"location"`
+	From       string              `json:"from"` // 2006-01-02, dahil
+	To         string              `json:"to"`   // 2006-01-02, dahil
+	Summary    WorkloadSummaryDTO  `json:"summary"`
+	Doctors    []DoctorWorkloadDTO `json:"doctors"`
+}
+
+// Toplamlar tüm doktorları, en yüklü ve en az yüklü doktor arasındaki farklar
+// yalnızca aralıkta lokasyona üye olan doktorları kapsar
+type WorkloadSummaryDTO struct {
+	Doctors             int     `json:"doctors"`
+	Shifts              int     `json:"shifts"`
+	Hours               float64 `json:"hours"`
+	WeekendShifts       int     `json:"weekend_shifts"`
+	HolidayShifts       int     `json:"holiday_shifts"`
+	OvernightShifts     int     `json:"overnight_shifts"`
+	LeaveDays           int     `json:"leave_days"`
+	SwapsRequested      int     `json:"swaps_requested"`
+	SwapsCompleted      int     `json:"swaps_completed"`
+	AverageShifts       float64 `json:"average_shifts"`
+	MaxShifts           int     `json:"max_shifts"`
+	MinShifts           int     `json:"min_shifts"`
+	ShiftGap            int     `json:"shift_gap"`
+	HourGap             float64 `json:"hour_gap"`
+	MostLoadedDoctorID  int64   `json:"most_loaded_doctor_id,omitempty"`
+	LeastLoadedDoctorID int64   `json:"least_loaded_doctor_id,omitempty"`
+}
+
+type DoctorWorkloadDTO struct {
+	DoctorID        int64         `json:"doctor_id"`
+	Name            string        `json:"name"`
+	Member          bool          `json:"member"` // aralıkta lokasyona üye, değilse sadece kayıtları vardır
+	Shifts          int           `json:"shifts"`
+	Hours           float64       `json:"hours"`
+	WeekendShifts   int           `json:"weekend_shifts"`
+	HolidayShifts   int           `json:"holiday_shifts"`
+	OvernightShifts int           `json:"overnight_shifts"`
+	ShiftLimit      int           `json:"shift_limit"`        // aylık geçerli limit, 0 limitsiz
+	Capacity        float64       `json:"capacity,omitempty"` // limitin aralığa düşen kısmı
+	Utilisation     *float64      `json:"utilisation"`        // nöbetlerin kapasiteye yüzdesel oranı, limitsizse null
+	LeaveDays       int           `json:"leave_days"`
+	Swaps           SwapCountsDTO `json:"swaps"`
+}
+
+// Doktorun açtığı, karşı taraf olduğu ve onaylanıp uygulanan değişim talepleri
+type SwapCountsDTO struct {
+	Requested int `json:"requested"`
+	Received  int `json:"received"`
+	Completed int `json:"completed"`
+}
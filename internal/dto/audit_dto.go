@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"encoding/json"
+	"shift-scheduling-v2/internal/model"
+	"time"
+)
+
+type AuditLogDTO struct {
+	ID        int64           `json:"id"`
+	ActorID   int64           `json:"actor_id,omitempty"`
+	Action    string          `json:"action"`
+	Entity    string          `json:"entity"`
+	EntityID  int64           `json:"entity_id,omitempty"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	IP        string          `json:"ip,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func (vm AuditLogDTO) ToResponseModel(m model.AuditLog) AuditLogDTO {
+	vm.ID = m.ID
+	vm.ActorID = m.ActorID
+	vm.Action = m.Action
+	vm.Entity = m.Entity
+	vm.EntityID = m.EntityID
+	vm.Before = m.Before
+	vm.After = m.After
+	vm.IP = m.IP
+	vm.CreatedAt = m.CreatedAt
+
+	return vm
+}
+
+type AuditListDTO struct {
+	Items      []AuditLogDTO  `json:"items"`
+	Pagination map[string]any `json:"pagination"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"shift-scheduling-v2/internal/model"
+	"time"
+)
+
+type CalendarFeedRequest struct {
+	Name string `json:"name" validate:"max=100"`
+}
+
+// Takvim aboneliği. Token ve URL sadece oluşturulduğunda döner, sonradan tekrar gösterilemez.
+type CalendarFeedDTO struct {
+	ID         int64      `json:"id"`
+	Kind       string     `json:"kind"`
+	DoctorID   int64      `json:"doctor_id,omitempty"`
+	LocationID int64      `json:"location_id,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Token      string     `json:"token,omitempty"`
+	URL        string     `json:"url,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (vm CalendarFeedDTO) ToResponseModel(m model.CalendarFeedToken) CalendarFeedDTO {
+	vm.ID = m.ID
+	vm.Kind = m.Kind
+	vm.DoctorID = m.DoctorID
+	vm.LocationID = m.LocationID
+	vm.Name = m.Name
+	vm.CreatedAt = m.CreatedAt
+	vm.LastUsedAt = m.LastUsedAt
+	vm.RevokedAt = m.RevokedAt
+
+	return vm
+}
@@ -48,6 +48,7 @@ type DoctorHolidayDTO struct {
 	LocationName  string    `json:"location"`
 	DoctorName    string    `json:"doctor_name"`
 	DoctorSurname string    `json:"doctor_surname"`
+	LeaveType     string    `json:"leave_type,omitempty"`
 }
 
 func (vm DoctorHolidayDTO) ToResponseModel(m model.Holiday) DoctorHolidayDTO {
@@ -58,6 +59,47 @@ func (vm DoctorHolidayDTO) ToResponseModel(m model.Holiday) DoctorHolidayDTO {
 	vm.LocationName = m.Location.Name
 	vm.DoctorName = m.Doctor.User.Name
 	vm.DoctorSurname = m.Doctor.User.Surname
+	vm.LeaveType = m.LeaveType
 
 	return vm
 }
+
+type DoctorPreferenceRequest struct {
+	PreferredWeekdays  []int       `json:"preferred_weekdays"` // 0: Pazar
+	AvoidDates         []time.Time `json:"avoid_dates"`
+	PreferredTemplates []int64     `json:"preferred_templates"`
+	Weight             float64     `json:"weight"` // 0 ise 1 kabul edilir
+}
+
+func (vm DoctorPreferenceRequest) ToDBModel(m model.DoctorPreference) model.DoctorPreference {
+	m.PreferredWeekdays = vm.PreferredWeekdays
+	m.AvoidDates = vm.AvoidDates
+	m.PreferredTemplates = vm.PreferredTemplates
+	m.Weight = vm.Weight
+
+	return m
+}
+
+type DoctorPreferenceDTO struct {
+	DoctorID           int64       `json:"doctor_id"`
+	Year               int         `json:"year"`
+	Month              int         `json:"month"`
+	PreferredWeekdays  []int       `json:"preferred_weekdays"`
+	AvoidDates         []time.Time `json:"avoid_dates"`
+	PreferredTemplates []int64     `json:"preferred_templates"`
+	Weight             float64     `json:"weight"`
+	Deadline           time.Time   `json:"deadline"`
+	Editable           bool        `json:"editable"`
+}
+
+func (vm DoctorPreferenceDTO) ToResponseModel(m model.DoctorPreference) *DoctorPreferenceDTO {
+	vm.DoctorID = m.DoctorID
+	vm.Year = m.Year
+	vm.Month = m.Month
+	vm.PreferredWeekdays = m.PreferredWeekdays
+	vm.AvoidDates = m.AvoidDates
+	vm.PreferredTemplates = m.PreferredTemplates
+	vm.Weight = m.Weight
+
+	return &vm
+}
@@ -0,0 +1,28 @@
+package dto
+
+// Doktorun gelen (karşı taraf olduğu) ve giden (kendi açtığı) değişim talepleri
+type SwapBoxDTO struct {
+	Inbox  []SwapResponseDTO `json:"inbox"`
+	Outbox []SwapResponseDTO `json:"outbox"`
+}
+
+// Doktorun yayınlanmış bir aydaki nöbet istatistikleri
+type DoctorMonthStatsDTO struct {
+	DoctorID        int64                    `json:"doctor_id"`
+	Year            int                      `json:"year"`
+	Month           int                      `json:"month"`
+	Shifts          int                      `json:"shifts"`
+	Hours           float64                  `json:"hours"`
+	WeekendShifts   int                      `json:"weekend_shifts"`
+	HolidayShifts   int                      `json:"holiday_shifts"`
+	OvernightShifts int                      `json:"overnight_shifts"`
+	LeaveDays       int                      `json:"leave_days"`
+	Locations       []DoctorLocationStatsDTO `json:"locations"`
+}
+
+type DoctorLocationStatsDTO struct {
+	LocationID int64   `json:"location_id"`
+	Location   string  `json:"location"`
+	Shifts     int     `json:"shifts"`
+	Hours      float64 `json:"hours"`
+}
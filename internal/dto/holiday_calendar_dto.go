@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"shift-scheduling-v2/internal/model"
+	"time"
+)
+
+type HolidayCalendarRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+func (vm HolidayCalendarRequest) ToDBModel(m model.HolidayCalendar) model.HolidayCalendar {
+	m.Name = vm.Name
+	m.Description = vm.Description
+
+	return m
+}
+
+type PublicHolidayRequest struct {
+	Name        string    `json:"name" validate:"required"`
+	HolidayDate time.Time `json:"holiday_date" validate:"required"`
+	Recurring   bool      `json:"recurring"`
+}
+
+func (vm PublicHolidayRequest) ToDBModel(m model.PublicHoliday) model.PublicHoliday {
+	m.Name = vm.Name
+	m.HolidayDate = vm.HolidayDate
+	m.Recurring = vm.Recurring
+
+	return m
+}
+
+type PublicHolidayDTO struct {
+	ID          int64     `json:"id"`
+	UID         string    `json:"uid,omitempty"`
+	Name        string    `json:"name"`
+	HolidayDate time.Time `json:"holiday_date"`
+	Recurring   bool      `json:"recurring"`
+}
+
+type HolidayCalendarDTO struct {
+	ID          int64              `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Entries     []PublicHolidayDTO `json:"entries,omitempty"`
+}
+
+func (vm HolidayCalendarDTO) ToResponseModel(m model.HolidayCalendar) HolidayCalendarDTO {
+	vm.ID = m.ID
+	vm.Name = m.Name
+	vm.Description = m.Description
+	for _, e := range m.Entries {
+		vm.Entries = append(vm.Entries, PublicHolidayDTO{
+			ID:          e.ID,
+			UID:         e.UID,
+			Name:        e.Name,
+			HolidayDate: e.HolidayDate,
+			Recurring:   e.Recurring,
+		})
+	}
+
+	return vm
+}
+
+type HolidayImportResultDTO struct {
+	CalendarID int64 `json:"calendar_id"`
+	Events     int   `json:"events"` // dosyadaki etkinlik sayısı
+	Days       int   `json:"days"`   // eklenen ya da güncellenen tatil günü
+}
+
+type LocationHolidayCalendarRequest struct {
+	DoctorsRequired *int    `json:"doctors_required"` // boşsa haftalık gereksinim geçerlidir
+	Weight          float64 `json:"weight"`           // 0 ise varsayılan resmi tatil ağırlığı
+}
+
+func (vm LocationHolidayCalendarRequest) ToDBModel(m model.LocationHolidayCalendar) model.LocationHolidayCalendar {
+	m.DoctorsRequired = vm.DoctorsRequired
+	m.Weight = vm.Weight
+
+	return m
+}
+
+type LocationHolidayCalendarDTO struct {
+	LocationID      int64   `json:"location_id"`
+	CalendarID      int64   `json:"calendar_id"`
+	CalendarName    string  `json:"calendar_name"`
+	DoctorsRequired *int    `json:"doctors_required"`
+	Weight          float64 `json:"weight"`
+}
+
+func (vm LocationHolidayCalendarDTO) ToResponseModel(m model.LocationHolidayCalendar) LocationHolidayCalendarDTO {
+	vm.LocationID = m.LocationID
+	vm.CalendarID = m.CalendarID
+	vm.CalendarName = m.Calendar.Name
+	vm.DoctorsRequired = m.DoctorsRequired
+	vm.Weight = m.Weight
+
+	return vm
+}
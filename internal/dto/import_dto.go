@@ -0,0 +1,20 @@
+package dto
+
+// Uygulanan içe aktarmanın dosya bazında özeti
+type ImportReportDTO struct {
+	Files []ImportFileReportDTO `json:"files"`
+}
+
+type ImportFileReportDTO struct {
+	File     string              `json:"file"`     // doctors, memberships ya da holidays
+	Rows     int                 `json:"rows"`     // boş olmayan veri satırı
+	Imported int                 `json:"imported"` // eklenen satır
+	Skipped  []ImportRowErrorDTO `json:"skipped,omitempty"`
+}
+
+// Satır numarası dosyadaki satırdır, başlık 1. satırdır
+type ImportRowErrorDTO struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,135 @@
+package dto
+
+import (
+	"shift-scheduling-v2/internal/model"
+	"time"
+)
+
+type LeaveCreateRequest struct {
+	LeaveType  string    `json:"leave_type" validate:"required,oneof=annual sick conference compensatory"`
+	StartDate  time.Time `json:"start_date" validate:"required"`
+	EndDate    time.Time `json:"end_date"` // boşsa tek günlük izin
+	LocationID int64     `json:"location_id"`
+	Reason     string    `json:"reason"`
+}
+
+func (vm LeaveCreateRequest) ToDBModel(m model.LeaveRequest) model.LeaveRequest {
+	m.LeaveType = vm.LeaveType
+	m.StartDate = vm.StartDate
+	m.EndDate = vm.EndDate
+	m.LocationID = vm.LocationID
+	m.Reason = vm.Reason
+
+	return m
+}
+
+type LeaveDecisionRequest struct {
+	Comment string `json:"comment"`
+}
+
+type LeaveResponseDTO struct {
+	ID                  int64      `json:"id"`
+	DoctorID            int64      `json:"doctor_id"`
+	DoctorName          string     `json:"doctor_name"`
+	LocationID          int64      `json:"location_id"`
+	LocationName        string     `json:"location"`
+	LeaveType           string     `json:"leave_type"`
+	StartDate           time.Time  `json:"start_date"`
+	EndDate             time.Time  `json:"end_date"`
+	Days                int        `json:"days"`
+	Reason              string     `json:"reason,omitempty"`
+	Status              string     `json:"status"`
+	ReviewedBy          int64      `json:"reviewed_by,omitempty"`
+	ReviewedAt          *time.Time `json:"reviewed_at,omitempty"`
+	ReviewComment       string     `json:"review_comment,omitempty"`
+	ConflictingShiftIDs []int64    `json:"conflicting_shift_ids"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+func (vm LeaveResponseDTO) ToResponseModel(m model.LeaveRequest) LeaveResponseDTO {
+	vm.ID = m.ID
+	vm.DoctorID = m.DoctorID
+	vm.DoctorName = fullName(m.Doctor.User)
+	vm.LocationID = m.LocationID
+	vm.LocationName = m.Location.Name
+	vm.LeaveType = m.LeaveType
+	vm.StartDate = m.StartDate
+	vm.EndDate = m.EndDate
+	vm.Days = int(m.EndDate.Sub(m.StartDate).Hours()/24) + 1
+	vm.Reason = m.Reason
+	vm.Status = m.Status
+	vm.ReviewedBy = m.ReviewedBy
+	vm.ReviewedAt = m.ReviewedAt
+	vm.ReviewComment = m.ReviewComment
+	vm.ConflictingShiftIDs = m.ConflictingShiftIDs
+	if vm.ConflictingShiftIDs == nil {
+		vm.ConflictingShiftIDs = []int64{}
+	}
+	vm.CreatedAt = m.CreatedAt
+
+	return vm
+}
+
+type LeaveEntitlementRequest struct {
+	LeaveType    string  `json:"leave_type" validate:"required,oneof=annual sick conference compensatory"`
+	AnnualDays   float64 `json:"annual_days"`
+	Accrual      string  `json:"accrual"` // boşsa upfront
+	CarryOverMax float64 `json:"carry_over_max"`
+	StartYear    int     `json:"start_year"` // boşsa içinde bulunulan yıl
+}
+
+func (vm LeaveEntitlementRequest) ToDBModel(m model.LeaveEntitlement) model.LeaveEntitlement {
+	m.LeaveType = vm.LeaveType
+	m.AnnualDays = vm.AnnualDays
+	m.Accrual = vm.Accrual
+	m.CarryOverMax = vm.CarryOverMax
+	m.StartYear = vm.StartYear
+
+	return m
+}
+
+type LeaveEntitlementDTO struct {
+	LeaveType    string  `json:"leave_type"`
+	AnnualDays   float64 `json:"annual_days"`
+	Accrual      string  `json:"accrual"`
+	CarryOverMax float64 `json:"carry_over_max"`
+	StartYear    int     `json:"start_year"`
+}
+
+func (vm LeaveEntitlementDTO) ToResponseModel(m model.LeaveEntitlement) LeaveEntitlementDTO {
+	vm.LeaveType = m.LeaveType
+	vm.AnnualDays = m.AnnualDays
+	vm.Accrual = m.Accrual
+	vm.CarryOverMax = m.CarryOverMax
+	vm.StartYear = m.StartYear
+
+	return vm
+}
+
+// Bir izin türünün yıl içindeki bakiyesi
+type LeaveBalanceDTO struct {
+	LeaveEntitlementDTO
+	Year        int     `json:"year"`
+	Accrued     float64 `json:"accrued"`
+	CarriedOver float64 `json:"carried_over"`
+	Used        float64 `json:"used"`
+	Pending     float64 `json:"pending"`
+	Available   float64 `json:"available"`
+}
+
+type DoctorLeaveBalanceDTO struct {
+	DoctorID   int64             `json:"doctor_id"`
+	DoctorName string            `json:"doctor_name"`
+	Year       int               `json:"year"`
+	Balances   []LeaveBalanceDTO `json:"balances"`
+}
+
+func (vm DoctorLeaveBalanceDTO) ToResponseModel(m model.Doctor) DoctorLeaveBalanceDTO {
+	vm.DoctorID = m.ID
+	vm.DoctorName = fullName(m.User)
+	if vm.Balances == nil {
+		vm.Balances = []LeaveBalanceDTO{}
+	}
+
+	return vm
+}
@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/policy"
+	"time"
+)
+
+type LocationRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+func (vm LocationRequest) ToDBModel(m model.ShiftLocation) model.ShiftLocation {
+	m.Name = vm.Name
+	m.Description = vm.Description
+
+	return m
+}
+
+type LocationDTO struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+}
+
+func (vm LocationDTO) ToResponseModel(m model.ShiftLocation) LocationDTO {
+	vm.ID = m.ID
+	vm.Name = m.Name
+	vm.Description = m.Description
+	vm.ArchivedAt = m.ArchivedAt
+
+	return vm
+}
+
+type MembershipRequest struct {
+	DoctorID      int64      `json:"doctor_id"` // yalnızca eklerken
+	EffectiveFrom *time.Time `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+}
+
+func (vm MembershipRequest) ToDBModel(m model.DoctorShiftLocation) model.DoctorShiftLocation {
+	m.EffectiveFrom = vm.EffectiveFrom
+	m.EffectiveTo = vm.EffectiveTo
+
+	return m
+}
+
+type MembershipDTO struct {
+	ID            int64      `json:"id"`
+	LocationID    int64      `json:"location_id"`
+	DoctorID      int64      `json:"doctor_id"`
+	DoctorName    string     `json:"doctor_name"`
+	EffectiveFrom *time.Time `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+}
+
+func (vm MembershipDTO) ToResponseModel(m model.DoctorShiftLocation) MembershipDTO {
+	vm.ID = m.ID
+	vm.LocationID = m.LocationID
+	vm.DoctorID = m.DoctorID
+	vm.DoctorName = fullName(m.Doctor.User)
+	vm.EffectiveFrom = m.EffectiveFrom
+	vm.EffectiveTo = m.EffectiveTo
+
+	return vm
+}
+
+// Politika belgesinin tamamı, kısmi güncelleme yapılmaz
+type LocationPolicyRequest policy.Document
+
+func (vm LocationPolicyRequest) ToDBModel(m model.LocationPolicy) model.LocationPolicy {
+	m.Document = policy.Document(vm)
+
+	return m
+}
+
+// Version 0 ise lokasyon için politika kaydedilmemiştir, varsayılan belge döner
+type LocationPolicyDTO struct {
+	LocationID int64           `json:"location_id"`
+	Version    int             `json:"version"`
+	Document   policy.Document `json:"document"`
+	CreatedBy  int64           `json:"created_by,omitempty"`
+	CreatedAt  *time.Time      `json:"created_at,omitempty"`
+}
+
+func (vm LocationPolicyDTO) ToResponseModel(m model.LocationPolicy) *LocationPolicyDTO {
+	vm.LocationID = m.LocationID
+	vm.Version = m.Version
+	vm.Document = m.Document
+	vm.CreatedBy = m.CreatedBy
+	if !m.CreatedAt.IsZero() {
+		vm.CreatedAt = &m.CreatedAt
+	}
+
+	return &vm
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"shift-scheduling-v2/internal/model"
+	"time"
+)
+
+type NotificationDTO struct {
+	ID        int64          `json:"id"`
+	Event     string         `json:"event"`
+	Title     string         `json:"title"`
+	Message   string         `json:"message"`
+	Data      map[string]any `json:"data,omitempty"`
+	IsRead    bool           `json:"is_read"`
+	ReadAt    *time.Time     `json:"read_at,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+func (vm NotificationDTO) ToResponseModel(m model.Notification) NotificationDTO {
+	vm.ID = m.ID
+	vm.Event = m.Event
+	vm.Title = m.Title
+	vm.Message = m.Message
+	vm.Data = m.Data
+	vm.IsRead = m.IsRead
+	vm.ReadAt = m.ReadAt
+	vm.CreatedAt = m.CreatedAt
+
+	return vm
+}
+
+type NotificationListDTO struct {
+	Items       []NotificationDTO `json:"items"`
+	UnreadCount int               `json:"unread_count"`
+	Pagination  map[string]any    `json:"pagination"`
+}
@@ -0,0 +1,114 @@
+package dto
+
+import (
+	"shift-scheduling-v2/internal/model"
+	"time"
+)
+
+// Ayın yayın durumu ve güncel nöbetleri
+type ScheduleDTO struct {
+	LocationID  int64           `json:"location_id"`
+	Year        int             `json:"year"`
+	Month       int             `json:"month"`
+	State       string          `json:"state"`
+	Version     int             `json:"version"`
+	PublishedAt *time.Time      `json:"published_at,omitempty"`
+	LockedAt    *time.Time      `json:"locked_at,omitempty"`
+	Shifts      []ShiftResponse `json:"shifts"`
+}
+
+func (vm ScheduleDTO) ToResponseModel(status model.ShiftsStatus, shifts []model.Shift) *ScheduleDTO {
+	vm.LocationID = status.LocationID
+	vm.Year = status.Year
+	vm.Month = status.Month
+	vm.State = status.State
+	vm.Version = status.Version
+	vm.PublishedAt = status.PublishedAt
+	vm.LockedAt = status.LockedAt
+	vm.Shifts = make([]ShiftResponse, len(shifts))
+	for i, shift := range shifts {
+		vm.Shifts[i] = ShiftResponse{}.ToResponseModel(shift)
+	}
+
+	return &vm
+}
+
+type ScheduleTransitionRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+type ScheduleRollbackRequest struct {
+	Version int    `json:"version" validate:"required,min=1"`
+	Reason  string `json:"reason" validate:"max=500"`
+}
+
+type ScheduleSnapshotDTO struct {
+	LocationID int64           `json:"location_id"`
+	Year       int             `json:"year"`
+	Month      int             `json:"month"`
+	Version    int             `json:"version"`
+	CreatedBy  int64           `json:"created_by,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Shifts     []ShiftResponse `json:"shifts,omitempty"`
+}
+
+func (vm ScheduleSnapshotDTO) ToResponseModel(m model.ScheduleSnapshot) ScheduleSnapshotDTO {
+	vm.LocationID = m.LocationID
+	vm.Year = m.Year
+	vm.Month = m.Month
+	vm.Version = m.Version
+	vm.CreatedBy = m.CreatedBy
+	vm.CreatedAt = m.CreatedAt
+	for _, ds := range m.Shifts {
+		vm.Shifts = append(vm.Shifts, ShiftResponse{}.ToResponseModel(ds.ToShift(m.LocationID)))
+	}
+
+	return vm
+}
+
+type ScheduleTransitionDTO struct {
+	ID            int64     `json:"id"`
+	Action        string    `json:"action"`
+	FromState     string    `json:"from_state"`
+	ToState       string    `json:"to_state"`
+	Version       int       `json:"version"`
+	SourceVersion int       `json:"source_version,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+	CreatedBy     int64     `json:"created_by,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (vm ScheduleTransitionDTO) ToResponseModel(m model.ScheduleTransition) ScheduleTransitionDTO {
+	vm.ID = m.ID
+	vm.Action = m.Action
+	vm.FromState = m.FromState
+	vm.ToState = m.ToState
+	vm.Version = m.Version
+	vm.SourceVersion = m.SourceVersion
+	vm.Reason = m.Reason
+	vm.CreatedBy = m.CreatedBy
+	vm.CreatedAt = m.CreatedAt
+
+	return vm
+}
+
+// İki sürüm arasındaki farklar. To 0 ise güncel nöbetlerle karşılaştırılmıştır.
+type ScheduleDiffDTO struct {
+	LocationID  int64               `json:"location_id"`
+	Year        int                 `json:"year"`
+	Month       int                 `json:"month"`
+	FromVersion int                 `json:"from_version"`
+	ToVersion   int                 `json:"to_version"`
+	Changes     []ScheduleChangeDTO `json:"changes"`
+}
+
+// Slot bazında tek bir değişiklik: added, removed ya da reassigned
+type ScheduleChangeDTO struct {
+	Type         string    `json:"type"`
+	Date         time.Time `json:"date"`
+	StartTime    string    `json:"start_time"`
+	EndTime      string    `json:"end_time"`
+	TemplateID   int64     `json:"template_id,omitempty"`
+	FromDoctorID int64     `json:"from_doctor_id,omitempty"`
+	ToDoctorID   int64     `json:"to_doctor_id,omitempty"`
+}
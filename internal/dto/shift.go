@@ -1,14 +1,19 @@
 package dto
 
 import (
+	"encoding/json"
 	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/policy"
+	"shift-scheduling-v2/pkg/errorx"
 	"time"
 )
 
 type AutoAssignShiftDTO struct {
-	LocationID int `json:"location_id" validate:"required"`
-	Year       int `json:"year" validate:"required"`
-	Month      int `json:"month" validate:"required"`
+	LocationID int  `json:"location_id" validate:"required"`
+	Year       int  `json:"year" validate:"required"`
+	Month      int  `json:"month" validate:"required"`
+	DryRun     bool `json:"dry_run"`    // true ise hiçbir şey yazılmaz, önizleme döner
+	SaveDraft  bool `json:"save_draft"` // dry_run ile birlikte önizlemeyi taslak olarak saklar
 }
 
 func (vm AutoAssignShiftDTO) ToDBModel(m model.ShiftsStatus) model.ShiftsStatus {
@@ -19,18 +24,175 @@ func (vm AutoAssignShiftDTO) ToDBModel(m model.ShiftsStatus) model.ShiftsStatus
 	return m
 }
 
+type AutoAssignResultDTO struct {
+	LocationID    int64                 `json:"location_id"`
+	Year          int                   `json:"year"`
+	Month         int                   `json:"month"`
+	AssignedCount int                   `json:"assigned_count"`
+	RequiredCount int                   `json:"required_count"`
+	Coverage      float64               `json:"coverage"` // atanan nöbetlerin gerekenlere yüzdesel oranı
+	Score         float64               `json:"score"`
+	MeanShifts    float64               `json:"mean_shifts"`
+	Doctors       []DoctorShiftTallyDTO `json:"doctors"`
+	Redistributed []RedistributedDayDTO `json:"redistributed"`
+	DryRun        bool                  `json:"dry_run"`
+	DraftID       int64                 `json:"draft_id,omitempty"`
+	Shifts        []ShiftResponse       `json:"shifts,omitempty"`
+	Warnings      []errorx.Detail       `json:"warnings,omitempty"`
+}
+
+// İkinci turda kısıtlar gevşetilerek doldurulan gün
+type RedistributedDayDTO struct {
+	Date        time.Time `json:"date"`
+	DoctorID    int64     `json:"doctor_id"`
+	Step        int       `json:"step"`
+	Relaxations []string  `json:"relaxations"`
+}
+
+type DoctorShiftTallyDTO struct {
+	DoctorID          int64   `json:"doctor_id"`
+	Shifts            int     `json:"shifts"`
+	WeekendShifts     int     `json:"weekend_shifts"`
+	HolidayShifts     int     `json:"public_holiday_shifts"`
+	WeightedLoad      float64 `json:"weighted_load"`
+	Hours             float64 `json:"hours"`
+	HistoricalShifts  int     `json:"historical_shifts"`
+	HistoricalLoad    float64 `json:"historical_load"`
+	Deviation         float64 `json:"deviation"`
+	WeightedDeviation float64 `json:"weighted_deviation"`
+
+	PreferencesRequested int `json:"preferences_requested"`
+	PreferencesSatisfied int `json:"preferences_satisfied"`
+}
+
+type ScheduleDraftDTO struct {
+	ID          int64           `json:"id"`
+	LocationID  int64           `json:"location_id"`
+	Year        int             `json:"year"`
+	Month       int             `json:"month"`
+	Status      string          `json:"status"`
+	Complete    bool            `json:"complete"`
+	CreatedBy   int64           `json:"created_by"`
+	CreatedAt   time.Time       `json:"created_at"`
+	CommittedAt *time.Time      `json:"committed_at,omitempty"`
+	Preview     json.RawMessage `json:"preview"`
+}
+
+func (vm ScheduleDraftDTO) ToResponseModel(m model.ScheduleDraft) *ScheduleDraftDTO {
+	vm.ID = m.ID
+	vm.LocationID = m.LocationID
+	vm.Year = m.Year
+	vm.Month = m.Month
+	vm.Status = m.Status
+	vm.Complete = m.Complete
+	vm.CreatedBy = m.CreatedBy
+	vm.CreatedAt = m.CreatedAt
+	vm.CommittedAt = m.CommittedAt
+	vm.Preview = m.Report
+
+	return &vm
+}
+
+type RestRulesDTO struct {
+	LocationID         int64 `json:"location_id"`
+	MinRestHours       int   `json:"min_rest_hours" validate:"min=0"`
+	MaxConsecutiveDays int   `json:"max_consecutive_days" validate:"min=0"`
+	MaxShiftsPerWeek   int   `json:"max_shifts_per_week" validate:"min=0"`
+}
+
+func (vm RestRulesDTO) ToDBModel(m policy.RestRules) policy.RestRules {
+	m.MinRestHours = vm.MinRestHours
+	m.MaxConsecutiveDays = vm.MaxConsecutiveDays
+	m.MaxShiftsPerWeek = vm.MaxShiftsPerWeek
+
+	return m
+}
+
+func (vm RestRulesDTO) ToResponseModel(locationID int64, m policy.RestRules) *RestRulesDTO {
+	vm.LocationID = locationID
+	vm.MinRestHours = m.MinRestHours
+	vm.MaxConsecutiveDays = m.MaxConsecutiveDays
+	vm.MaxShiftsPerWeek = m.MaxShiftsPerWeek
+
+	return &vm
+}
+
+type WeekdayCoverageDTO struct {
+	Weekday         int `json:"weekday" validate:"min=0,max=6"` // 0: Pazar
+	DoctorsRequired int `json:"doctors_required" validate:"min=0"`
+}
+
+func (vm WeekdayCoverageDTO) ToDBModel(m policy.WeekdayCoverage) policy.WeekdayCoverage {
+	m.Weekday = vm.Weekday
+	m.DoctorsRequired = vm.DoctorsRequired
+
+	return m
+}
+
+type CoverageOverrideDTO struct {
+	Date            time.Time `json:"date" validate:"required"`
+	DoctorsRequired int       `json:"doctors_required" validate:"min=0"`
+}
+
+func (vm CoverageOverrideDTO) ToDBModel(m model.CoverageOverride) model.CoverageOverride {
+	m.OverrideDate = vm.Date
+	m.DoctorsRequired = vm.DoctorsRequired
+
+	return m
+}
+
+func (vm CoverageOverrideDTO) ToResponseModel(m model.CoverageOverride) CoverageOverrideDTO {
+	vm.Date = m.OverrideDate
+	vm.DoctorsRequired = m.DoctorsRequired
+
+	return vm
+}
+
+// Default verilmezse politikadaki varsayılan korunur
+type CoverageUpdateRequest struct {
+	Default  *int                 `json:"default" validate:"omitempty,min=0"`
+	Weekdays []WeekdayCoverageDTO `json:"weekdays"`
+}
+
+type CoverageDTO struct {
+	LocationID int64                 `json:"location_id"`
+	Default    int                   `json:"default"`
+	Weekdays   []WeekdayCoverageDTO  `json:"weekdays"`
+	Overrides  []CoverageOverrideDTO `json:"overrides"`
+}
+
+func (vm CoverageDTO) ToResponseModel(locationID int64, coverage policy.Coverage, overrides []model.CoverageOverride) *CoverageDTO {
+	vm.LocationID = locationID
+	vm.Default = coverage.Default
+
+	vm.Weekdays = make([]WeekdayCoverageDTO, len(coverage.Weekdays))
+	for i, r := range coverage.Weekdays {
+		vm.Weekdays[i] = WeekdayCoverageDTO{Weekday: r.Weekday, DoctorsRequired: r.DoctorsRequired}
+	}
+
+	vm.Overrides = make([]CoverageOverrideDTO, len(overrides))
+	for i, o := range overrides {
+		vm.Overrides[i] = CoverageOverrideDTO{}.ToResponseModel(o)
+	}
+
+	return &vm
+}
+
+// TemplateID verilirse saatler şablondan alınır
 type ShiftCreateRequest struct {
 	DoctorID   int64     `json:"doctor_id" validate:"required"`
 	LocationID int64     `json:"location_id" validate:"required"`
 	ShiftDate  time.Time `json:"shift_date" validate:"required"`
-	StartTime  string    `json:"start_time" validate:"required"`
-	EndTime    string    `json:"end_time" validate:"required"`
+	TemplateID int64     `json:"template_id"`
+	StartTime  string    `json:"start_time" validate:"required_without=TemplateID"`
+	EndTime    string    `json:"end_time" validate:"required_without=TemplateID"`
 }
 
 func (vm ShiftCreateRequest) ToDBModel(m model.Shift) model.Shift {
 	m.DoctorID = vm.DoctorID
 	m.LocationID = vm.LocationID
 	m.ShiftDate = vm.ShiftDate
+	m.TemplateID = vm.TemplateID
 	m.StartTime = vm.StartTime
 	m.EndTime = vm.EndTime
 
@@ -42,8 +204,9 @@ type ShiftUpdateRequest struct {
 	DoctorID   int64     `json:"doctor_id" validate:"required"`
 	LocationID int64     `json:"location_id" validate:"required"`
 	ShiftDate  time.Time `json:"shift_date" validate:"required"`
-	StartTime  string    `json:"start_time" validate:"required"`
-	EndTime    string    `json:"end_time" validate:"required"`
+	TemplateID int64     `json:"template_id"`
+	StartTime  string    `json:"start_time" validate:"required_without=TemplateID"`
+	EndTime    string    `json:"end_time" validate:"required_without=TemplateID"`
 }
 
 func (vm ShiftUpdateRequest) ToDBModel(m model.Shift) model.Shift {
@@ -51,6 +214,7 @@ func (vm ShiftUpdateRequest) ToDBModel(m model.Shift) model.Shift {
 	m.DoctorID = vm.DoctorID
 	m.LocationID = vm.LocationID
 	m.ShiftDate = vm.ShiftDate
+	m.TemplateID = vm.TemplateID
 	m.StartTime = vm.StartTime
 	m.EndTime = vm.EndTime
 
@@ -64,6 +228,7 @@ type ShiftResponse struct {
 	ShiftDate  time.Time `json:"shift_date"`
 	StartTime  string    `json:"start_time"`
 	EndTime    string    `json:"end_time"`
+	TemplateID int64     `json:"template_id,omitempty"`
 }
 
 func (vm ShiftResponse) ToResponseModel(m model.Shift) ShiftResponse {
@@ -73,6 +238,7 @@ func (vm ShiftResponse) ToResponseModel(m model.Shift) ShiftResponse {
 	vm.ShiftDate = m.ShiftDate
 	vm.StartTime = m.StartTime
 	vm.EndTime = m.EndTime
+	vm.TemplateID = m.TemplateID
 
 	return vm
 }
@@ -84,6 +250,8 @@ type ShiftListWithDetailsDTO struct {
 	ShiftDate     time.Time `json:"shift_date"`
 	StartTime     string    `json:"start_time"`
 	EndTime       string    `json:"end_time"`
+	TemplateID    int64     `json:"template_id,omitempty"`
+	Template      string    `json:"template,omitempty"`
 	DoctorName    string    `json:"doctor_name"`
 	DoctorSurname string    `json:"doctor_surname"`
 	Location      string    `json:"location"`
@@ -96,6 +264,10 @@ func (vm ShiftListWithDetailsDTO) ToResponseModel(m model.Shift) ShiftListWithDe
 	vm.ShiftDate = m.ShiftDate
 	vm.StartTime = m.StartTime
 	vm.EndTime = m.EndTime
+	vm.TemplateID = m.TemplateID
+	if m.Template != nil {
+		vm.Template = m.Template.Name
+	}
 	vm.DoctorName = m.Doctor.User.Name
 	vm.DoctorSurname = m.Doctor.User.Surname
 	vm.Location = m.Location.Name
@@ -140,3 +312,65 @@ func (vm ShiftLocationDTO) ToResponseModel(m model.ShiftLocation) ShiftLocationD
 
 	return vm
 }
+
+type ShiftTemplateRequest struct {
+	Name      string `json:"name" validate:"required"`
+	StartTime string `json:"start_time" validate:"required"`
+	EndTime   string `json:"end_time" validate:"required"`
+}
+
+func (vm ShiftTemplateRequest) ToDBModel(m model.ShiftTemplate) model.ShiftTemplate {
+	m.Name = vm.Name
+	m.StartTime = vm.StartTime
+	m.EndTime = vm.EndTime
+
+	return m
+}
+
+type ShiftTemplateDTO struct {
+	ID              int64   `json:"id"`
+	LocationID      int64   `json:"location_id"`
+	Name            string  `json:"name"`
+	StartTime       string  `json:"start_time"`
+	EndTime         string  `json:"end_time"`
+	DurationMinutes int     `json:"duration_minutes"`
+	Overnight       bool    `json:"overnight"`
+	Hours           float64 `json:"hours"`
+}
+
+func (vm ShiftTemplateDTO) ToResponseModel(m model.ShiftTemplate) ShiftTemplateDTO {
+	vm.ID = m.ID
+	vm.LocationID = m.LocationID
+	vm.Name = m.Name
+	vm.StartTime = m.StartTime
+	vm.EndTime = m.EndTime
+	vm.DurationMinutes = m.DurationMinutes
+	vm.Overnight = m.EndTime <= m.StartTime
+	vm.Hours = float64(m.DurationMinutes) / 60
+
+	return vm
+}
+
+// Doktorun verilen dönemde fiilen çalıştığı saatler
+type DoctorHoursDTO struct {
+	DoctorID      int64   `json:"doctor_id"`
+	DoctorName    string  `json:"doctor_name"`
+	DoctorSurname string  `json:"doctor_surname"`
+	Shifts        int     `json:"shifts"`
+	Hours         float64 `json:"hours"`
+}
+
+type WorkedHoursDTO struct {
+	LocationID int64            `json:"location_id"`
+	Year       int              `json:"year"`
+	Month      int              `json:"month"`
+	TotalHours float64          `json:"total_hours"`
+	Doctors    []DoctorHoursDTO `json:"doctors"`
+}
+
+// İndirilebilir dosya olarak üretilen çıktı
+type ExportFile struct {
+	Filename    string
+	ContentType string
+	Body        []byte
+}
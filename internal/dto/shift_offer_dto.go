@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"shift-scheduling-v2/internal/model"
+	"time"
+)
+
+type OfferCreateRequest struct {
+	ShiftID int64  `json:"shift_id" validate:"required"`
+	Comment string `json:"comment"`
+}
+
+func (vm OfferCreateRequest) ToDBModel(m model.ShiftOffer) model.ShiftOffer {
+	m.ShiftID = vm.ShiftID
+	m.Comment = vm.Comment
+
+	return m
+}
+
+type OfferResponseDTO struct {
+	ID            int64      `json:"id"`
+	ShiftID       int64      `json:"shift_id"`
+	LocationID    int64      `json:"location_id"`
+	ShiftDate     time.Time  `json:"shift_date"`
+	StartTime     string     `json:"start_time"`
+	EndTime       string     `json:"end_time"`
+	Status        string     `json:"status"`
+	OfferedBy     int64      `json:"offered_by"`
+	OfferedByName string     `json:"offered_by_name"`
+	ClaimedBy     int64      `json:"claimed_by,omitempty"`
+	ClaimedByName string     `json:"claimed_by_name,omitempty"`
+	ClaimedAt     *time.Time `json:"claimed_at,omitempty"`
+	Comment       string     `json:"comment,omitempty"`
+	ReviewedBy    int64      `json:"reviewed_by,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (vm OfferResponseDTO) ToResponseModel(m model.ShiftOffer) OfferResponseDTO {
+	vm.ID = m.ID
+	vm.ShiftID = m.ShiftID
+	vm.LocationID = m.LocationID
+	vm.ShiftDate = m.ShiftDate
+	vm.StartTime = m.Shift.StartTime
+	vm.EndTime = m.Shift.EndTime
+	vm.Status = m.Status
+	vm.OfferedBy = m.OfferedBy
+	vm.OfferedByName = fullName(m.Offerer.User)
+	vm.ClaimedBy = m.ClaimedBy
+	if m.Claimer != nil {
+		vm.ClaimedByName = fullName(m.Claimer.User)
+	}
+	vm.ClaimedAt = m.ClaimedAt
+	vm.Comment = m.Comment
+	vm.ReviewedBy = m.ReviewedBy
+	vm.ReviewedAt = m.ReviewedAt
+	vm.ResolvedAt = m.ResolvedAt
+	vm.CreatedAt = m.CreatedAt
+
+	return vm
+}
+
+// Doktorun panoda gördüğü ilan; Reasons alınamıyorsa ihlal edilen kuralları taşır
+type OfferBoardDTO struct {
+	OfferResponseDTO
+	Eligible bool     `json:"eligible"`
+	Reasons  []string `json:"reasons,omitempty"`
+}
+
+// İlandaki nöbeti alabilecek doktor adayı
+type OfferCandidateDTO struct {
+	DoctorID int64    `json:"doctor_id"`
+	Name     string   `json:"name"`
+	Eligible bool     `json:"eligible"`
+	Reasons  []string `json:"reasons,omitempty"`
+}
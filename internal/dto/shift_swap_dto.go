@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"shift-scheduling-v2/internal/model"
+	"time"
+)
+
+type SwapCreateRequest struct {
+	OfferedShiftID   int64  `json:"offered_shift_id" validate:"required"`   // talep edenin kendi nöbeti
+	RequestedShiftID int64  `json:"requested_shift_id" validate:"required"` // karşılığında istenen nöbet
+	Comment          string `json:"comment"`
+}
+
+func (vm SwapCreateRequest) ToDBModel(m model.ShiftSwapRequest) model.ShiftSwapRequest {
+	m.OfferedShiftID = vm.OfferedShiftID
+	m.RequestedShiftID = vm.RequestedShiftID
+	m.RequesterComment = vm.Comment
+
+	return m
+}
+
+type SwapDecisionRequest struct {
+	Comment string `json:"comment"`
+}
+
+type SwapResponseDTO struct {
+	ID                 int64      `json:"id"`
+	LocationID         int64      `json:"location_id"`
+	Status             string     `json:"status"`
+	RequesterID        int64      `json:"requester_id"`
+	RequesterName      string     `json:"requester_name"`
+	OfferedShiftID     int64      `json:"offered_shift_id"`
+	OfferedShiftDate   time.Time  `json:"offered_shift_date"`
+	AcceptorID         int64      `json:"acceptor_id"`
+	AcceptorName       string     `json:"acceptor_name"`
+	RequestedShiftID   int64      `json:"requested_shift_id"`
+	RequestedShiftDate time.Time  `json:"requested_shift_date"`
+	MutualAgreement    bool       `json:"mutual_agreement"`
+	RequesterComment   string     `json:"requester_comment,omitempty"`
+	AcceptorComment    string     `json:"acceptor_comment,omitempty"`
+	ReviewedBy         int64      `json:"reviewed_by,omitempty"`
+	ReviewedAt         *time.Time `json:"reviewed_at,omitempty"`
+	ResolvedAt         *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+func (vm SwapResponseDTO) ToResponseModel(m model.ShiftSwapRequest) SwapResponseDTO {
+	vm.ID = m.ID
+	vm.LocationID = m.LocationID
+	vm.Status = m.Status
+	vm.RequesterID = m.RequesterID
+	vm.RequesterName = fullName(m.Requester.User)
+	vm.OfferedShiftID = m.OfferedShiftID
+	vm.OfferedShiftDate = m.OfferedShiftDate
+	vm.AcceptorID = m.AcceptorID
+	vm.AcceptorName = fullName(m.Acceptor.User)
+	vm.RequestedShiftID = m.RequestedShiftID
+	vm.RequestedShiftDate = m.RequestShiftDate
+	vm.MutualAgreement = m.MutualAgreement
+	vm.RequesterComment = m.RequesterComment
+	vm.AcceptorComment = m.AcceptorComment
+	vm.ReviewedBy = m.ReviewedBy
+	vm.ReviewedAt = m.ReviewedAt
+	vm.ResolvedAt = m.ResolvedAt
+	vm.CreatedAt = m.CreatedAt
+
+	return vm
+}
+
+func fullName(u model.User) string {
+	if u.Surname == "" {
+		return u.Name
+	}
+	return u.Name + " " + u.Surname
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/response"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AnalyticsHandler struct {
+	service *service.AnalyticsService
+}
+
+func NewAnalyticsHandler(s *service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{service: s}
+}
+
+// ?from=YYYY-MM-DD&to=YYYY-MM-DD, iki uç da dahildir. Verilmezse içinde bulunulan ay kullanılır.
+func (h *AnalyticsHandler) LocationWorkload(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	var from, to time.Time
+	if v := c.Query("from"); v != "" {
+		if from, err = time.Parse("2006-01-02", v); err != nil {
+			return errorx.WithDetails(errorx.ErrInvalidRequest, "Geçersiz from değeri")
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = time.Parse("2006-01-02", v); err != nil {
+			return errorx.WithDetails(errorx.ErrInvalidRequest, "Geçersiz to değeri")
+		}
+	}
+
+	resp, err := h.service.LocationWorkload(c.Context(), id, from, to)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
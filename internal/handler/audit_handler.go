@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/query"
+	"shift-scheduling-v2/pkg/response"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditHandler struct {
+	service *service.AuditService
+}
+
+func NewAuditHandler(s *service.AuditService) *AuditHandler {
+	return &AuditHandler{service: s}
+}
+
+// ?entity=&entity_id=&actor_id= ile filtreleme, from ve to ile zaman aralığı
+// (YYYY-MM-DD ya da RFC3339, gün olarak verilen to dahildir), page ve page_size ile sayfalama
+func (h *AuditHandler) List(c *fiber.Ctx) error {
+	params, err := query.ParseFromContext(c)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	filter := repository.AuditFilter{Entity: c.Query("entity")}
+	if v := c.Query("entity_id"); v != "" {
+		if filter.EntityID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return errorx.ErrInvalidRequest
+		}
+	}
+	if v := c.Query("actor_id"); v != "" {
+		if filter.ActorID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return errorx.ErrInvalidRequest
+		}
+	}
+	if filter.From, err = auditTime(c.Query("from"), false); err != nil {
+		return errorx.WithDetails(errorx.ErrInvalidRequest, "Geçersiz from değeri")
+	}
+	if filter.To, err = auditTime(c.Query("to"), true); err != nil {
+		return errorx.WithDetails(errorx.ErrInvalidRequest, "Geçersiz to değeri")
+	}
+
+	resp, err := h.service.List(c.Context(), filter, params.Pagination)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+// Gün olarak verilen bitiş, günün sonuna kadar olan kayıtları kapsar
+func auditTime(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if day, err := time.Parse("2006-01-02", value); err == nil {
+		if end {
+			day = day.AddDate(0, 0, 1)
+		}
+		return day, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
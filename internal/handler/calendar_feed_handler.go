@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CalendarFeedHandler struct {
+	service *service.CalendarFeedService
+}
+
+func NewCalendarFeedHandler(s *service.CalendarFeedService) *CalendarFeedHandler {
+	return &CalendarFeedHandler{service: s}
+}
+
+// /calendar/:kind/:token.ics, JWT gerektirmez; erişim anahtarla sağlanır
+func (h *CalendarFeedHandler) Feed(c *fiber.Ctx) error {
+	body, err := h.service.Feed(c.Context(), c.Params("kind"), c.Params("token"))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	return c.Send(body)
+}
+
+func (h *CalendarFeedHandler) CreateMine(c *fiber.Ctx) error {
+	req, err := calendarFeedRequest(c)
+	if err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.CreateForDoctor(c.Context(), userID, req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Takvim aboneliği oluşturuldu")
+}
+
+func (h *CalendarFeedHandler) ListMine(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.ListForDoctor(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *CalendarFeedHandler) RevokeMine(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	if err = h.service.RevokeForDoctor(c.Context(), userID, id); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Takvim aboneliği iptal edildi")
+}
+
+func (h *CalendarFeedHandler) CreateForLocation(c *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+	req, err := calendarFeedRequest(c)
+	if err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.CreateForLocation(c.Context(), locationID, userID, req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Takvim aboneliği oluşturuldu")
+}
+
+func (h *CalendarFeedHandler) ListForLocation(c *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.ListForLocation(c.Context(), locationID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *CalendarFeedHandler) Revoke(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	if err = h.service.Revoke(c.Context(), id); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Takvim aboneliği iptal edildi")
+}
+
+// Gövde isteğe bağlıdır
+func calendarFeedRequest(c *fiber.Ctx) (dto.CalendarFeedRequest, error) {
+	var req dto.CalendarFeedRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return req, errorx.ErrInvalidRequest
+		}
+	}
+	if len([]rune(req.Name)) > 100 {
+		return req, errorx.WithDetails(errorx.ErrValidation, "name en fazla 100 karakter olabilir")
+	}
+	return req, nil
+}
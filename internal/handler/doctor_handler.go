@@ -150,3 +150,67 @@ func (h *DoctorHandler) Delete(c *fiber.Ctx) error {
 
 	return response.Success(c, nil, "Doktor başarıyla silindi")
 }
+
+// URL'deki yıl ve ay parametrelerini okur
+func yearMonthParams(c *fiber.Ctx) (int, int, error) {
+	year, err := strconv.Atoi(c.Params("year"))
+	if err != nil {
+		return 0, 0, errorx.ErrInvalidRequest
+	}
+
+	month, err := strconv.Atoi(c.Params("month"))
+	if err != nil {
+		return 0, 0, errorx.ErrInvalidRequest
+	}
+
+	return year, month, nil
+}
+
+func (h *DoctorHandler) GetMyPreference(c *fiber.Ctx) error {
+	year, month, err := yearMonthParams(c)
+	if err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.GetMyPreference(c.Context(), userID, year, month)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *DoctorHandler) UpdateMyPreference(c *fiber.Ctx) error {
+	year, month, err := yearMonthParams(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.DoctorPreferenceRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.UpdateMyPreference(c.Context(), userID, year, month, req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Tercihler kaydedildi")
+}
+
+func (h *DoctorHandler) DeleteMyPreference(c *fiber.Ctx) error {
+	year, month, err := yearMonthParams(c)
+	if err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	if err = h.service.DeleteMyPreference(c.Context(), userID, year, month); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Tercihler silindi")
+}
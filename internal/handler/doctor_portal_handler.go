@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Gelecek nöbetler için varsayılan ve en fazla gün sayısı
+const (
+	defaultUpcomingDays = 60
+	maxUpcomingDays     = 366
+)
+
+type DoctorPortalHandler struct {
+	service *service.DoctorPortalService
+}
+
+func NewDoctorPortalHandler(s *service.DoctorPortalService) *DoctorPortalHandler {
+	return &DoctorPortalHandler{service: s}
+}
+
+// ?days= ile bugünden itibaren kaç günün nöbetlerinin döneceği (varsayılan 60)
+func (h *DoctorPortalHandler) UpcomingShifts(c *fiber.Ctx) error {
+	days := c.QueryInt("days", defaultUpcomingDays)
+	if days < 1 || days > maxUpcomingDays {
+		return errorx.WithDetails(errorx.ErrValidation, "days 1 ile 366 arasında olmalıdır")
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.UpcomingShifts(c.Context(), userID, days)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *DoctorPortalHandler) Holidays(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Holidays(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *DoctorPortalHandler) LeaveRequests(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.LeaveRequests(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *DoctorPortalHandler) ColleaguesToday(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.ColleaguesToday(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *DoctorPortalHandler) MonthStats(c *fiber.Ctx) error {
+	year, err := strconv.Atoi(c.Params("year"))
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+	month, err := strconv.Atoi(c.Params("month"))
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.MonthStats(c.Context(), userID, year, month)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *DoctorPortalHandler) Swaps(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Swaps(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HolidayCalendarHandler struct {
+	service *service.HolidayCalendarService
+}
+
+func NewHolidayCalendarHandler(s *service.HolidayCalendarService) *HolidayCalendarHandler {
+	return &HolidayCalendarHandler{service: s}
+}
+
+func (h *HolidayCalendarHandler) List(c *fiber.Ctx) error {
+	resp, err := h.service.List(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *HolidayCalendarHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.GetByID(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *HolidayCalendarHandler) Create(c *fiber.Ctx) error {
+	var req dto.HolidayCalendarRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.Create(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Takvim oluşturuldu")
+}
+
+func (h *HolidayCalendarHandler) Update(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	var req dto.HolidayCalendarRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.Update(c.Context(), id, req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Takvim güncellendi")
+}
+
+func (h *HolidayCalendarHandler) Delete(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	if err = h.service.Delete(c.Context(), id); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Takvim silindi")
+}
+
+func (h *HolidayCalendarHandler) AddEntry(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	var req dto.PublicHolidayRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.AddEntry(c.Context(), id, req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Tatil günü kaydedildi")
+}
+
+func (h *HolidayCalendarHandler) DeleteEntry(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+	entryID, err := strconv.ParseInt(c.Params("entry_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	if err = h.service.DeleteEntry(c.Context(), id, entryID); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Tatil günü silindi")
+}
+
+// ICS dosyası "file" alanıyla multipart olarak ya da doğrudan istek gövdesinde gönderilebilir
+func (h *HolidayCalendarHandler) Import(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	var body io.Reader = bytes.NewReader(c.Body())
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			return errorx.ErrInvalidRequest
+		}
+		defer f.Close()
+		body = f
+	}
+
+	resp, err := h.service.Import(c.Context(), id, body)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Takvim içe aktarıldı")
+}
+
+func (h *HolidayCalendarHandler) GetLocationCalendars(c *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(c.Params("location_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.GetLocationCalendars(c.Context(), locationID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *HolidayCalendarHandler) SetLocationCalendar(c *fiber.Ctx) error {
+	locationID, calendarID, err := locationCalendarParams(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.LocationHolidayCalendarRequest
+	if len(c.Body()) > 0 {
+		if err = c.BodyParser(&req); err != nil {
+			return errorx.ErrInvalidRequest
+		}
+	}
+
+	resp, err := h.service.SetLocationCalendar(c.Context(), locationID, calendarID, req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Lokasyon takvimi kaydedildi")
+}
+
+func (h *HolidayCalendarHandler) DeleteLocationCalendar(c *fiber.Ctx) error {
+	locationID, calendarID, err := locationCalendarParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.service.DeleteLocationCalendar(c.Context(), locationID, calendarID); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Lokasyon takvimi kaldırıldı")
+}
+
+func locationCalendarParams(c *fiber.Ctx) (int64, int64, error) {
+	locationID, err := strconv.ParseInt(c.Params("location_id"), 10, 64)
+	if err != nil {
+		return 0, 0, errorx.ErrInvalidRequest
+	}
+	calendarID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, errorx.ErrInvalidRequest
+	}
+	return locationID, calendarID, nil
+}
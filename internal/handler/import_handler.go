@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"io"
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ImportHandler struct {
+	service *service.ImportService
+}
+
+func NewImportHandler(s *service.ImportService) *ImportHandler {
+	return &ImportHandler{service: s}
+}
+
+// CSV dosyaları multipart olarak doctors, memberships ve holidays alanlarıyla gönderilir, en az biri zorunludur.
+// ?skip_invalid=true ile hatalı satırlar atlanır ve geçerli satırlar eklenir.
+func (h *ImportHandler) Import(c *fiber.Ctx) error {
+	input := service.ImportInput{SkipInvalid: c.QueryBool("skip_invalid")}
+
+	fields := []struct {
+		name   string
+		target *io.Reader
+	}{
+		{service.ImportFileDoctors, &input.Doctors},
+		{service.ImportFileMemberships, &input.Memberships},
+		{service.ImportFileHolidays, &input.Holidays},
+	}
+	for _, field := range fields {
+		header, err := c.FormFile(field.name)
+		if err != nil {
+			continue
+		}
+		file, err := header.Open()
+		if err != nil {
+			return errorx.ErrInvalidRequest
+		}
+		defer file.Close()
+		*field.target = file
+	}
+
+	resp, err := h.service.Import(c.Context(), input)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "İçe aktarma tamamlandı")
+}
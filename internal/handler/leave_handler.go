@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LeaveHandler struct {
+	service *service.LeaveService
+}
+
+func NewLeaveHandler(s *service.LeaveService) *LeaveHandler {
+	return &LeaveHandler{service: s}
+}
+
+func (h *LeaveHandler) Create(c *fiber.Ctx) error {
+	var req dto.LeaveCreateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Create(c.Context(), userID, req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "İzin talebi oluşturuldu")
+}
+
+func (h *LeaveHandler) ListMine(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.ListMine(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *LeaveHandler) Cancel(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Cancel(c.Context(), userID, id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "İzin talebi iptal edildi")
+}
+
+func (h *LeaveHandler) List(c *fiber.Ctx) error {
+	var locationID int64
+	if v := c.Query("location_id"); v != "" {
+		var err error
+		if locationID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return errorx.ErrInvalidRequest
+		}
+	}
+
+	resp, err := h.service.List(c.Context(), c.Query("status"), locationID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *LeaveHandler) Approve(c *fiber.Ctx) error {
+	id, req, err := leaveDecisionParams(c)
+	if err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Approve(c.Context(), userID, id, req.Comment)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "İzin talebi onaylandı")
+}
+
+func (h *LeaveHandler) Reject(c *fiber.Ctx) error {
+	id, req, err := leaveDecisionParams(c)
+	if err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Reject(c.Context(), userID, id, req.Comment)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "İzin talebi reddedildi")
+}
+
+func (h *LeaveHandler) MyBalance(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.MyBalance(c.Context(), userID, c.QueryInt("year"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *LeaveHandler) LocationBalances(c *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(c.Params("location_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.LocationBalances(c.Context(), locationID, c.QueryInt("year"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *LeaveHandler) GetEntitlements(c *fiber.Ctx) error {
+	doctorID, err := strconv.ParseInt(c.Params("doctor_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.GetEntitlements(c.Context(), doctorID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *LeaveHandler) SetEntitlement(c *fiber.Ctx) error {
+	doctorID, err := strconv.ParseInt(c.Params("doctor_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	var req dto.LeaveEntitlementRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.SetEntitlement(c.Context(), doctorID, req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "İzin hakkı kaydedildi")
+}
+
+func (h *LeaveHandler) DeleteEntitlement(c *fiber.Ctx) error {
+	doctorID, err := strconv.ParseInt(c.Params("doctor_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	if err = h.service.DeleteEntitlement(c.Context(), doctorID, c.Params("leave_type")); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "İzin hakkı silindi")
+}
+
+func leaveDecisionParams(c *fiber.Ctx) (int64, dto.LeaveDecisionRequest, error) {
+	var req dto.LeaveDecisionRequest
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, req, errorx.ErrInvalidRequest
+	}
+
+	if len(c.Body()) > 0 {
+		if err = c.BodyParser(&req); err != nil {
+			return 0, req, errorx.ErrInvalidRequest
+		}
+	}
+	return id, req, nil
+}
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/response"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LocationHandler struct {
+	service *service.LocationService
+}
+
+func NewLocationHandler(s *service.LocationService) *LocationHandler {
+	return &LocationHandler{service: s}
+}
+
+func (h *LocationHandler) List(c *fiber.Ctx) error {
+	resp, err := h.service.List(c.Context(), c.QueryBool("include_archived"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *LocationHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.GetByID(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *LocationHandler) Create(c *fiber.Ctx) error {
+	var req dto.LocationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.Create(c.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Lokasyon oluşturuldu")
+}
+
+func (h *LocationHandler) Update(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	var req dto.LocationRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.Update(c.Context(), id, req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Lokasyon güncellendi")
+}
+
+func (h *LocationHandler) Archive(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.Archive(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Lokasyon arşivlendi")
+}
+
+func (h *LocationHandler) Restore(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.Restore(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Lokasyon arşivden çıkarıldı")
+}
+
+// ?date=YYYY-MM-DD verilirse yalnızca o gün geçerli üyelikler döner
+func (h *LocationHandler) ListMembers(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	day, err := optionalDate(c.Query("date"))
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.ListMembers(c.Context(), id, day)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *LocationHandler) AddMember(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	var req dto.MembershipRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.AddMember(c.Context(), id, req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Doktor lokasyona eklendi")
+}
+
+func (h *LocationHandler) UpdateMember(c *fiber.Ctx) error {
+	id, memberID, err := memberParams(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.MembershipRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.UpdateMember(c.Context(), id, memberID, req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Üyelik güncellendi")
+}
+
+// ?effective_to=YYYY-MM-DD verilmezse üyelik bugün sona erer
+func (h *LocationHandler) RemoveMember(c *fiber.Ctx) error {
+	id, memberID, err := memberParams(c)
+	if err != nil {
+		return err
+	}
+
+	effectiveTo, err := optionalDate(c.Query("effective_to"))
+	if err != nil {
+		return err
+	}
+
+	if err = h.service.RemoveMember(c.Context(), id, memberID, effectiveTo); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Doktor lokasyondan çıkarıldı")
+}
+
+func (h *LocationHandler) GetPolicy(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.GetPolicy(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+// Gövde politika belgesinin tamamıdır, kayıt yeni bir sürüm oluşturur
+func (h *LocationHandler) UpdatePolicy(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	var req dto.LocationPolicyRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.UpdatePolicy(c.Context(), id, req, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Lokasyon politikası güncellendi")
+}
+
+func (h *LocationHandler) ListPolicyVersions(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.ListPolicyVersions(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *LocationHandler) GetPolicyVersion(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.GetPolicyVersion(c.Context(), id, version)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func memberParams(c *fiber.Ctx) (int64, int64, error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, errorx.ErrInvalidRequest
+	}
+	memberID, err := strconv.ParseInt(c.Params("member_id"), 10, 64)
+	if err != nil {
+		return 0, 0, errorx.ErrInvalidRequest
+	}
+	return id, memberID, nil
+}
+
+func optionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, errorx.WithDetails(errorx.ErrInvalidRequest, "Tarih YYYY-MM-DD formatında olmalıdır")
+	}
+	return &day, nil
+}
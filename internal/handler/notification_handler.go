@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/query"
+	"shift-scheduling-v2/pkg/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type NotificationHandler struct {
+	service *service.NotificationService
+}
+
+func NewNotificationHandler(s *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: s}
+}
+
+// ?unread=true ile sadece okunmamışlar, page ve page_size ile sayfalama
+func (h *NotificationHandler) GetMine(c *fiber.Ctx) error {
+	params, err := query.ParseFromContext(c)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.GetMine(c.Context(), userID, c.QueryBool("unread"), params.Pagination)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *NotificationHandler) MarkRead(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	if err = h.service.MarkRead(c.Context(), userID, id); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Bildirim okundu olarak işaretlendi")
+}
+
+func (h *NotificationHandler) MarkAllRead(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(int64)
+	count, err := h.service.MarkAllRead(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, fiber.Map{"updated": count}, "Tüm bildirimler okundu olarak işaretlendi")
+}
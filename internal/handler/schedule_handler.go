@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ScheduleHandler struct {
+	service *service.ScheduleService
+}
+
+func NewScheduleHandler(s *service.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{service: s}
+}
+
+func (h *ScheduleHandler) Get(c *fiber.Ctx) error {
+	locationID, year, month, err := scheduleParams(c)
+	if err != nil {
+		return err
+	}
+	userID, _ := c.Locals("userID").(int64)
+	role, _ := c.Locals("role").(model.Role)
+
+	resp, err := h.service.Get(c.Context(), locationID, year, month, userID, role)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+// publish, unpublish, lock ve unlock işlemleri
+func (h *ScheduleHandler) Transition(c *fiber.Ctx) error {
+	locationID, year, month, err := scheduleParams(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.ScheduleTransitionRequest
+	if len(c.Body()) > 0 {
+		if err = c.BodyParser(&req); err != nil {
+			return errorx.ErrInvalidRequest
+		}
+	}
+	userID, _ := c.Locals("userID").(int64)
+
+	resp, err := h.service.Transition(c.Context(), locationID, year, month, c.Params("action"), req, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Schedule updated successfully")
+}
+
+func (h *ScheduleHandler) Rollback(c *fiber.Ctx) error {
+	locationID, year, month, err := scheduleParams(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.ScheduleRollbackRequest
+	if err = c.BodyParser(&req); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+	userID, _ := c.Locals("userID").(int64)
+
+	resp, err := h.service.Rollback(c.Context(), locationID, year, month, req, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Schedule rolled back successfully")
+}
+
+func (h *ScheduleHandler) ListVersions(c *fiber.Ctx) error {
+	locationID, year, month, err := scheduleParams(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.ListVersions(c.Context(), locationID, year, month)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *ScheduleHandler) GetVersion(c *fiber.Ctx) error {
+	locationID, year, month, err := scheduleParams(c)
+	if err != nil {
+		return err
+	}
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.GetVersion(c.Context(), locationID, year, month, version)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+// from sürümünü to sürümüyle, to verilmezse güncel nöbetlerle karşılaştırır
+func (h *ScheduleHandler) Diff(c *fiber.Ctx) error {
+	locationID, year, month, err := scheduleParams(c)
+	if err != nil {
+		return err
+	}
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.Diff(c.Context(), locationID, year, month, from, c.QueryInt("to"))
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *ScheduleHandler) ListTransitions(c *fiber.Ctx) error {
+	locationID, year, month, err := scheduleParams(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.ListTransitions(c.Context(), locationID, year, month)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func scheduleParams(c *fiber.Ctx) (int64, int, int, error) {
+	locationID, err := strconv.ParseInt(c.Params("location_id"), 10, 64)
+	if err != nil {
+		return 0, 0, 0, errorx.ErrInvalidRequest
+	}
+	year, err := strconv.Atoi(c.Params("year"))
+	if err != nil {
+		return 0, 0, 0, errorx.ErrInvalidRequest
+	}
+	month, err := strconv.Atoi(c.Params("month"))
+	if err != nil {
+		return 0, 0, 0, errorx.ErrInvalidRequest
+	}
+	return locationID, year, month, nil
+}
@@ -1,15 +1,13 @@
 package handler
 
 import (
-	"errors"
+	"fmt"
 	"shift-scheduling-v2/internal/dto"
 	"shift-scheduling-v2/internal/model"
 	"shift-scheduling-v2/internal/service"
 	"shift-scheduling-v2/pkg/errorx"
 	"shift-scheduling-v2/pkg/response"
-	"strings"
 
-	"fmt"
 	"strconv"
 	"time"
 
@@ -33,7 +31,8 @@ func (h ShiftHandler) Create(c *fiber.Ctx) error {
 
 	shift := vm.ToDBModel(model.Shift{})
 
-	err := h.shiftService.CreateShift(c.Context(), shift)
+	userID, _ := c.Locals("userID").(int64)
+	err := h.shiftService.CreateShift(c.Context(), shift, userID, c.Query("override_reason"))
 	if err != nil {
 		return err
 	}
@@ -41,88 +40,6 @@ func (h ShiftHandler) Create(c *fiber.Ctx) error {
 	return response.Success(c, nil, "Shift created successfully")
 }
 
-func (h ShiftHandler) AssignShiftsForMonth(c *fiber.Ctx, doctors []model.Doctor, locationID int, startOfMonth time.Time, endOfMonth time.Time) error {
-	// 1. Tüm doktorların tatil günlerini al
-	holidayMap := make(map[int64][]dto.DoctorHolidayDTO)
-	for _, doctor := range doctors {
-		holidays, err := h.doctorService.GetDoctorHolidays(c.Context(), doctor.ID)
-		if err != nil {
-			return err
-		}
-		holidayMap[doctor.ID] = holidays
-	}
-
-	// 2. Nöbet günlerini dağıt
-	shiftAssignments := make(map[int64]int) // Her doktorun aldığı nöbet sayısı
-	var unassignedDays []time.Time          // Atanamayan günlerin listesi
-
-	for shiftDate := startOfMonth; shiftDate.Before(endOfMonth); shiftDate = shiftDate.AddDate(0, 0, 1) {
-		var selectedDoctorID int64
-		for _, doctor := range doctors {
-			// Doktorun tatilde olup olmadığını kontrol et
-			isHoliday := false
-			for _, holiday := range holidayMap[doctor.ID] {
-				if holiday.HolidayDate.Equal(shiftDate) {
-					isHoliday = true
-					break
-				}
-			}
-			if isHoliday {
-				continue
-			}
-
-			// Doktorun nöbet limitine ulaşıp ulaşmadığını kontrol et
-			if shiftAssignments[doctor.ID] >= doctor.ShiftLimit {
-				continue
-			}
-
-			// Doktorun zaten o tarihte atanmış bir nöbeti olup olmadığını kontrol et
-			isAssigned, err := h.shiftService.IsDoctorAssignedToShift(c.Context(), doctor.ID, shiftDate)
-			if err != nil {
-				return err
-			}
-			if isAssigned {
-				continue
-			}
-
-			// Doktor uygun, nöbet atamasını yap
-			selectedDoctorID = doctor.ID
-			shiftAssignments[doctor.ID]++
-			break
-		}
-
-		// Eğer uygun doktor bulunmadıysa, o gün boş geçilecek
-		if selectedDoctorID == 0 {
-			unassignedDays = append(unassignedDays, shiftDate)
-			continue
-		}
-
-		// Nöbeti oluştur
-		shift := model.Shift{
-			DoctorID:   selectedDoctorID,
-			LocationID: int64(locationID),
-			ShiftDate:  shiftDate,
-		}
-		err := h.shiftService.CreateShift(c.Context(), shift)
-		if err != nil {
-			return err
-		}
-	}
-
-	// todo -> 3. Atanamayan günleri, eksik nöbeti olan doktorlara dağıt
-
-	// İşlem tamamlandığında, eğer boş kalan günler varsa uyarı ver
-	if len(unassignedDays) > 0 {
-		var unassignedDaysStr []string
-		for _, day := range unassignedDays {
-			unassignedDaysStr = append(unassignedDaysStr, day.Format("2006-01-02"))
-		}
-		return errors.New("Aşağıdaki günlerde doktor atanamadı: " + strings.Join(unassignedDaysStr, ", "))
-	}
-
-	return nil
-}
-
 func (h ShiftHandler) AutoAssignShifts(c *fiber.Ctx) error {
 	var vm dto.AutoAssignShiftDTO
 	if err := c.BodyParser(&vm); err != nil {
@@ -130,41 +47,50 @@ func (h ShiftHandler) AutoAssignShifts(c *fiber.Ctx) error {
 	}
 
 	shift := vm.ToDBModel(model.ShiftsStatus{})
-	year := shift.Year
-	month := shift.Month
-	locationID := shift.LocationID
 
-	doctors, err := h.shiftService.GetDoctorsByLocation(c.Context(), locationID)
-	if err != nil {
-		return err
-	}
-	if len(doctors) == 0 {
-		return errorx.ErrNotFound
+	if vm.DryRun {
+		userID, _ := c.Locals("userID").(int64)
+		result, err := h.shiftService.PreviewShifts(c.Context(), shift.Year, shift.Month, shift.LocationID, vm.SaveDraft, userID)
+		if err != nil {
+			return err
+		}
+
+		return response.Success(c, result, "Shift assignment preview generated successfully")
 	}
 
-	shiftStatus, err := h.shiftService.GetShiftStatus(c.Context(), year, month, int(locationID))
+	result, err := h.shiftService.AutoAssignShifts(c.Context(), shift.Year, shift.Month, shift.LocationID)
 	if err != nil {
 		return err
 	}
 
-	if shiftStatus.Done {
+	return response.Success(c, result, "Shifts assigned successfully")
+}
+
+func (h ShiftHandler) GetDraft(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
 		return errorx.ErrInvalidRequest
 	}
 
-	startOfMonth := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	endOfMonth := startOfMonth.AddDate(0, 1, 0)
-
-	err = h.shiftService.AssignShiftsForMonth(c.Context(), doctors, int(locationID), startOfMonth, endOfMonth)
+	draft, err := h.shiftService.GetDraft(c.Context(), id)
 	if err != nil {
 		return err
 	}
 
-	err = h.shiftService.MarkShiftStatusAsDone(c.Context(), year, month, int(locationID))
+	return response.Success(c, draft, "Draft retrieved successfully")
+}
+
+func (h ShiftHandler) CommitDraft(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	if err = h.shiftService.CommitDraft(c.Context(), id); err != nil {
 		return err
 	}
 
-	return response.Success(c, nil, "Shifts assigned successfully")
+	return response.Success(c, nil, "Draft committed successfully")
 }
 
 func (h ShiftHandler) ResetShifts(c *fiber.Ctx) error {
@@ -204,13 +130,7 @@ func (h ShiftHandler) GetShiftByDate(c *fiber.Ctx) error {
 }
 
 func (h ShiftHandler) GetTodayShifts(c *fiber.Ctx) error {
-	loc, _ := time.LoadLocation("Europe/Istanbul")
-	now := time.Now().In(loc)
-
-	todayStr := fmt.Sprintf("%04d-%02d-%02d", now.Year(), now.Month(), now.Day())
-	today, _ := time.ParseInLocation("2006-01-02", todayStr, loc)
-
-	shifts, err := h.shiftService.GetTodayShifts(c.Context(), today)
+	shifts, err := h.shiftService.GetTodayShifts(c.Context())
 	if err != nil {
 		return err
 	}
@@ -310,9 +230,10 @@ func (h ShiftHandler) DeleteShift(c *fiber.Ctx) error {
 		return errorx.ErrInvalidRequest
 	}
 
-	err = h.shiftService.DeleteShift(c.Context(), id)
+	userID, _ := c.Locals("userID").(int64)
+	err = h.shiftService.DeleteShift(c.Context(), id, userID, c.Query("override_reason"))
 	if err != nil {
-		return errorx.ErrInternal
+		return err
 	}
 
 	return response.Success(c, nil, "Shift deleted successfully")
@@ -336,7 +257,8 @@ func (h ShiftHandler) UpdateShift(c *fiber.Ctx) error {
 	}
 
 	updatedShift := vm.ToDBModel(*m)
-	err = h.shiftService.UpdateShift(c.Context(), updatedShift)
+	userID, _ := c.Locals("userID").(int64)
+	err = h.shiftService.UpdateShift(c.Context(), updatedShift, userID, c.Query("override_reason"))
 	if err != nil {
 		return err
 	}
@@ -366,3 +288,226 @@ func (h ShiftHandler) GetShiftLocations(c *fiber.Ctx) error {
 
 	return response.Success(c, shiftLocationsVM, "Shift locations retrieved successfully")
 }
+
+func (h ShiftHandler) GetRestRules(c *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(c.Params("location_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	rules, err := h.shiftService.GetRestRules(c.Context(), locationID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, rules, "Rest rules retrieved successfully")
+}
+
+func (h ShiftHandler) UpdateRestRules(c *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(c.Params("location_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	var vm dto.RestRulesDTO
+	if err = c.BodyParser(&vm); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	rules, err := h.shiftService.UpdateRestRules(c.Context(), locationID, vm, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, rules, "Rest rules updated successfully")
+}
+
+func (h ShiftHandler) GetCoverage(c *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(c.Params("location_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	coverage, err := h.shiftService.GetCoverage(c.Context(), locationID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, coverage, "Coverage requirements retrieved successfully")
+}
+
+func (h ShiftHandler) UpdateCoverage(c *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(c.Params("location_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	var vm dto.CoverageUpdateRequest
+	if err = c.BodyParser(&vm); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	coverage, err := h.shiftService.UpdateCoverage(c.Context(), locationID, vm, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, coverage, "Coverage requirements updated successfully")
+}
+
+func (h ShiftHandler) UpsertCoverageOverride(c *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(c.Params("location_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	var vm dto.CoverageOverrideDTO
+	if err = c.BodyParser(&vm); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	override, err := h.shiftService.UpsertCoverageOverride(c.Context(), locationID, vm)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, override, "Coverage override saved successfully")
+}
+
+func (h ShiftHandler) DeleteCoverageOverride(c *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(c.Params("location_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	date, err := time.Parse("2006-01-02", c.Params("date"))
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	if err = h.shiftService.DeleteCoverageOverride(c.Context(), locationID, date); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Coverage override deleted successfully")
+}
+
+func (h ShiftHandler) GetTemplates(c *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(c.Params("location_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	templates, err := h.shiftService.GetTemplates(c.Context(), locationID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, templates, "Shift templates retrieved successfully")
+}
+
+func (h ShiftHandler) CreateTemplate(c *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(c.Params("location_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	var vm dto.ShiftTemplateRequest
+	if err = c.BodyParser(&vm); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	template, err := h.shiftService.CreateTemplate(c.Context(), locationID, vm)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, template, "Shift template created successfully")
+}
+
+func (h ShiftHandler) UpdateTemplate(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	var vm dto.ShiftTemplateRequest
+	if err = c.BodyParser(&vm); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	template, err := h.shiftService.UpdateTemplate(c.Context(), id, vm)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, template, "Shift template updated successfully")
+}
+
+func (h ShiftHandler) DeleteTemplate(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	if err = h.shiftService.DeleteTemplate(c.Context(), id); err != nil {
+		return err
+	}
+
+	return response.Success(c, nil, "Shift template deleted successfully")
+}
+
+func (h ShiftHandler) GetWorkedHours(c *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(c.Params("location_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	month, err := strconv.Atoi(c.Query("month"))
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	year, err := strconv.Atoi(c.Query("year"))
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	hours, err := h.shiftService.GetWorkedHours(c.Context(), locationID, year, month)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, hours, "Worked hours retrieved successfully")
+}
+
+// ?format=csv (varsayılan), xlsx ya da pdf
+func (h ShiftHandler) ExportMonth(c *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(c.Params("location_id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+	year, err := strconv.Atoi(c.Params("year"))
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+	month, err := strconv.Atoi(c.Params("month"))
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	file, err := h.shiftService.ExportMonth(c.Context(), locationID, year, month, c.Query("format", service.ExportFormatCSV))
+	if err != nil {
+		return err
+	}
+
+	return sendFile(c, file)
+}
+
+func sendFile(c *fiber.Ctx, file *dto.ExportFile) error {
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, file.Filename))
+	return c.Send(file.Body)
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ShiftOfferHandler struct {
+	service *service.ShiftOfferService
+}
+
+func NewShiftOfferHandler(s *service.ShiftOfferService) *ShiftOfferHandler {
+	return &ShiftOfferHandler{service: s}
+}
+
+func (h *ShiftOfferHandler) Create(c *fiber.Ctx) error {
+	var req dto.OfferCreateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+	if req.ShiftID == 0 {
+		return errorx.WithDetails(errorx.ErrValidation, "shift_id zorunludur")
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Create(c.Context(), userID, req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Nöbet ilana çıkarıldı")
+}
+
+func (h *ShiftOfferHandler) ListMine(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.ListMine(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *ShiftOfferHandler) Board(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Board(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *ShiftOfferHandler) Claim(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Claim(c.Context(), userID, id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "İlan alındı")
+}
+
+func (h *ShiftOfferHandler) Cancel(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Cancel(c.Context(), userID, id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "İlan geri çekildi")
+}
+
+func (h *ShiftOfferHandler) List(c *fiber.Ctx) error {
+	var locationID int64
+	if v := c.Query("location_id"); v != "" {
+		var err error
+		if locationID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return errorx.ErrInvalidRequest
+		}
+	}
+
+	resp, err := h.service.List(c.Context(), c.Query("status"), locationID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *ShiftOfferHandler) Candidates(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	resp, err := h.service.Candidates(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *ShiftOfferHandler) Approve(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Approve(c.Context(), userID, id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Nöbet devri onaylandı")
+}
+
+func (h *ShiftOfferHandler) Deny(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Deny(c.Context(), userID, id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Nöbet devri reddedildi, ilan tekrar açıldı")
+}
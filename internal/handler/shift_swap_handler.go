@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ShiftSwapHandler struct {
+	service *service.ShiftSwapService
+}
+
+func NewShiftSwapHandler(s *service.ShiftSwapService) *ShiftSwapHandler {
+	return &ShiftSwapHandler{service: s}
+}
+
+func (h *ShiftSwapHandler) Create(c *fiber.Ctx) error {
+	var req dto.SwapCreateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errorx.ErrInvalidRequest
+	}
+	if req.OfferedShiftID == 0 || req.RequestedShiftID == 0 {
+		return errorx.WithDetails(errorx.ErrValidation, "offered_shift_id ve requested_shift_id zorunludur")
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Create(c.Context(), userID, req)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Değişim talebi oluşturuldu")
+}
+
+func (h *ShiftSwapHandler) ListMine(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.ListMine(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *ShiftSwapHandler) Accept(c *fiber.Ctx) error {
+	id, req, err := swapDecisionParams(c)
+	if err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Accept(c.Context(), userID, id, req.Comment)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Değişim talebi kabul edildi")
+}
+
+func (h *ShiftSwapHandler) Reject(c *fiber.Ctx) error {
+	id, req, err := swapDecisionParams(c)
+	if err != nil {
+		return err
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Reject(c.Context(), userID, id, req.Comment)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Değişim talebi reddedildi")
+}
+
+func (h *ShiftSwapHandler) Cancel(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Cancel(c.Context(), userID, id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Değişim talebi iptal edildi")
+}
+
+func (h *ShiftSwapHandler) List(c *fiber.Ctx) error {
+	var locationID int64
+	if v := c.Query("location_id"); v != "" {
+		var err error
+		if locationID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return errorx.ErrInvalidRequest
+		}
+	}
+
+	resp, err := h.service.List(c.Context(), c.Query("status"), locationID)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp)
+}
+
+func (h *ShiftSwapHandler) Approve(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Approve(c.Context(), userID, id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Değişim onaylandı")
+}
+
+func (h *ShiftSwapHandler) Deny(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	resp, err := h.service.Deny(c.Context(), userID, id)
+	if err != nil {
+		return err
+	}
+
+	return response.Success(c, resp, "Değişim reddedildi")
+}
+
+// İstek gövdesi boş olabilir, sadece yorum taşır
+func swapDecisionParams(c *fiber.Ctx) (int64, dto.SwapDecisionRequest, error) {
+	var req dto.SwapDecisionRequest
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, req, errorx.ErrInvalidRequest
+	}
+
+	if len(c.Body()) > 0 {
+		if err = c.BodyParser(&req); err != nil {
+			return 0, req, errorx.ErrInvalidRequest
+		}
+	}
+	return id, req, nil
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Proxy'lerin boştaki bağlantıyı kapatmaması için gönderilen yorum satırı aralığı
+const streamHeartbeat = 20 * time.Second
+
+type StreamHandler struct {
+	service *service.StreamService
+}
+
+func NewStreamHandler(s *service.StreamService) *StreamHandler {
+	return &StreamHandler{service: s}
+}
+
+// Server-Sent Events akışı. ?location_id ile tek lokasyon izlenebilir.
+func (h *StreamHandler) Stream(c *fiber.Ctx) error {
+	locationID := int64(c.QueryInt("location_id"))
+	if locationID < 0 {
+		return errorx.ErrInvalidRequest
+	}
+
+	userID, _ := c.Locals("userID").(int64)
+	role, _ := c.Locals("role").(model.Role)
+	sub, err := h.service.Subscribe(c.Context(), userID, role, locationID)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+
+		ticker := time.NewTicker(streamHeartbeat)
+		defer ticker.Stop()
+
+		fmt.Fprint(w, ": connected\n\n")
+		if w.Flush() != nil {
+			return
+		}
+
+		for {
+			select {
+			case ev, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(fiber.Map{
+					"type":        ev.Type,
+					"location_id": ev.LocationID,
+					"data":        ev.Data,
+					"time":        ev.Time,
+				})
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
+			case <-ticker.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+
+			// İstemci bağlantıyı kapattıysa yazma hatası alınır
+			if w.Flush() != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package leave
+
+import (
+	"math"
+	"time"
+)
+
+// Hak ediş yöntemleri
+const (
+	// Yıllık hak yılın başında tamamen tanımlanır
+	AccrualUpfront = "upfront"
+	// Yıllık hakkın 1/12'si her ayın başında eklenir
+	AccrualMonthly = "monthly"
+)
+
+// Doktorun bir izin türü için yıllık hakkı
+type Entitlement struct {
+	AnnualDays   float64
+	Accrual      string
+	CarryOverMax float64 // sonraki yıla devredebilecek en fazla gün
+	StartYear    int     // hakkın başladığı yıl, önceki yıllardan devir yapılmaz
+}
+
+// Bir yıl için izin bakiyesi
+type Balance struct {
+	Year        int     `json:"year"`
+	Accrued     float64 `json:"accrued"`
+	CarriedOver float64 `json:"carried_over"`
+	Used        float64 `json:"used"`
+	Pending     float64 `json:"pending"`
+	Available   float64 `json:"available"`
+}
+
+// Yıl için asOf tarihine kadar hak edilen gün sayısı
+func (e Entitlement) Accrued(year int, asOf time.Time) float64 {
+	if year < e.StartYear {
+		return 0
+	}
+	if e.Accrual != AccrualMonthly || year < asOf.Year() {
+		return e.AnnualDays
+	}
+
+	// Gelecek yıllar için ilk ayın hakkı kullanılabilir
+	months := 1
+	if year == asOf.Year() {
+		months = int(asOf.Month())
+	}
+	return e.AnnualDays * float64(months) / 12
+}
+
+// Yılın bakiyesini hesaplar. used yıllara göre onaylı (tatil olarak işlenmiş) izin günleri,
+// pending ise yıl için bekleyen taleplerdeki gün sayısıdır.
+func (e Entitlement) Balance(year int, asOf time.Time, used map[int]float64, pending float64) Balance {
+	carried := 0.0
+	for y := e.StartYear; y < year; y++ {
+		// Önceki yıl sonu kalan, devir sınırıyla kırpılır
+		remaining := e.AnnualDays + carried - used[y]
+		carried = math.Max(0, math.Min(e.CarryOverMax, remaining))
+	}
+
+	accrued := e.Accrued(year, asOf)
+	return Balance{
+		Year:        year,
+		Accrued:     round(accrued),
+		CarriedOver: round(carried),
+		Used:        used[year],
+		Pending:     pending,
+		Available:   round(accrued + carried - used[year] - pending),
+	}
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// Tarih aralığındaki (iki uç dahil) günleri yıllara göre sayar
+func DaysByYear(start, end time.Time) map[int]float64 {
+	days := make(map[int]float64)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		days[day.Year()]++
+	}
+	return days
+}
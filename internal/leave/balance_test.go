@@ -0,0 +1,58 @@
+package leave_test
+
+import (
+	"shift-scheduling-v2/internal/leave"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaveBalanceUpfront(t *testing.T) {
+	rule := leave.Entitlement{AnnualDays: 14, Accrual: leave.AccrualUpfront, StartYear: 2025}
+	asOf := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+
+	balance := rule.Balance(2025, asOf, map[int]float64{2025: 5}, 3)
+	assert.Equal(t, 14.0, balance.Accrued)
+	assert.Equal(t, 6.0, balance.Available)
+}
+
+func TestLeaveBalanceMonthlyAccrual(t *testing.T) {
+	rule := leave.Entitlement{AnnualDays: 12, Accrual: leave.AccrualMonthly, StartYear: 2025}
+	asOf := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 4.0, rule.Balance(2025, asOf, nil, 0).Available)
+	// Geçmiş yıllar tam, gelecek yıl yalnızca ilk ay
+	assert.Equal(t, 12.0, rule.Accrued(2025, asOf.AddDate(1, 0, 0)))
+	assert.Equal(t, 1.0, rule.Accrued(2026, asOf))
+}
+
+func TestLeaveBalanceCarryOverLimit(t *testing.T) {
+	rule := leave.Entitlement{AnnualDays: 14, Accrual: leave.AccrualUpfront, CarryOverMax: 5, StartYear: 2024}
+	asOf := time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+	// 2024: 4 gün kullanıldı, 10 kaldı ama 5 devreder
+	// 2025: 14 + 5 - 17 = 2 devreder
+	balance := rule.Balance(2026, asOf, map[int]float64{2024: 4, 2025: 17}, 0)
+	assert.Equal(t, 2.0, balance.CarriedOver)
+	assert.Equal(t, 16.0, balance.Available)
+
+	// Aşım sonraki yıla eksi olarak devretmez
+	balance = rule.Balance(2025, asOf, map[int]float64{2024: 20}, 0)
+	assert.Equal(t, 0.0, balance.CarriedOver)
+}
+
+func TestLeaveBalanceBeforeStartYear(t *testing.T) {
+	rule := leave.Entitlement{AnnualDays: 14, Accrual: leave.AccrualUpfront, CarryOverMax: 5, StartYear: 2025}
+	asOf := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 0.0, rule.Balance(2024, asOf, nil, 0).Available)
+	assert.Equal(t, 0.0, rule.Balance(2025, asOf, nil, 0).CarriedOver)
+}
+
+func TestLeaveDaysByYear(t *testing.T) {
+	start := time.Date(2025, time.December, 29, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, map[int]float64{2025: 3, 2026: 2}, leave.DaysByYear(start, end))
+}
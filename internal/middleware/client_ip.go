@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"shift-scheduling-v2/internal/audit"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// İstemci adresini servislerin okuyabilmesi için isteğe ekler
+func ClientIP() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(audit.ClientIPKey, c.IP())
+		return c.Next()
+	}
+}
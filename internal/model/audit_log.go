@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Denetim kaydındaki varlık türleri
+const (
+	AuditEntityShift           = "shift"
+	AuditEntitySchedule        = "schedule"
+	AuditEntityLeave           = "leave"
+	AuditEntityHolidayCalendar = "holiday_calendar"
+	AuditEntityUser            = "user"
+	AuditEntityDoctor          = "doctor"
+)
+
+// Denetim kaydındaki işlemler
+const (
+	AuditActionCreate   = "create"
+	AuditActionUpdate   = "update"
+	AuditActionDelete   = "delete"
+	AuditActionAssign   = "assign"
+	AuditActionReset    = "reset"
+	AuditActionSwap     = "swap"
+	AuditActionTransfer = "transfer"
+	AuditActionApprove  = "approve"
+	AuditActionReject   = "reject"
+	AuditActionCancel   = "cancel"
+	AuditActionImport   = "import"
+)
+
+// Değiştirilemez denetim kaydı. Tablo sadece ekleme kabul eder, eski kayıtlar saklama süresine göre silinir.
+type AuditLog struct {
+	ID        int64           `json:"id" bun:",pk,autoincrement"`
+	ActorID   int64           `json:"actor_id,omitempty" bun:",nullzero"`
+	Action    string          `json:"action" bun:",notnull"`
+	Entity    string          `json:"entity" bun:",notnull"`
+	EntityID  int64           `json:"entity_id,omitempty" bun:",nullzero"`
+	Before    json.RawMessage `json:"before,omitempty" bun:",type:jsonb,nullzero"`
+	After     json.RawMessage `json:"after,omitempty" bun:",type:jsonb,nullzero"`
+	IP        string          `json:"ip,omitempty" bun:"ip,nullzero"`
+	CreatedAt time.Time       `json:"created_at" bun:",nullzero,notnull,default:current_timestamp"`
+
+	tableName struct{} `bun:"audit_logs"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// Takvim aboneliğinin kapsamı
+const (
+	CalendarFeedDoctor   = "doctor"
+	CalendarFeedLocation = "location"
+)
+
+// ICS aboneliği için iptal edilebilir erişim anahtarı. JWT'den bağımsızdır,
+// anahtarın kendisi saklanmaz, sadece SHA-256 özeti tutulur.
+type CalendarFeedToken struct {
+	ID         int64      `json:"id" bun:",pk,autoincrement"`
+	Kind       string     `json:"kind" bun:",notnull"`
+	DoctorID   int64      `json:"doctor_id,omitempty" bun:",nullzero"`
+	LocationID int64      `json:"location_id,omitempty" bun:",nullzero"`
+	TokenHash  string     `json:"-" bun:",notnull"`
+	Name       string     `json:"name,omitempty" bun:",nullzero"`
+	CreatedBy  int64      `json:"created_by,omitempty" bun:",nullzero"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" bun:",nullzero"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" bun:",nullzero"`
+	CreatedAt  time.Time  `json:"created_at" bun:",nullzero,notnull,default:current_timestamp"`
+
+	tableName struct{} `bun:"calendar_feed_tokens"`
+}
+
+// Silinen ya da başka doktora veya lokasyona geçen nöbetin eski hali.
+// Kayıtlar veritabanı tetikleyicisiyle oluşur; takvimlerde iptal edilmiş etkinlik olarak yayınlanır.
+type ShiftRemoval struct {
+	ID         int64     `json:"id" bun:",pk,autoincrement"`
+	ShiftID    int64     `json:"shift_id" bun:",notnull"`
+	DoctorID   int64     `json:"doctor_id" bun:",notnull"`
+	LocationID int64     `json:"location_id" bun:",notnull"`
+	ShiftDate  time.Time `json:"shift_date" bun:",notnull"`
+	StartTime  string    `json:"start_time" bun:",notnull"`
+	EndTime    string    `json:"end_time" bun:",notnull"`
+	RemovedAt  time.Time `json:"removed_at" bun:",nullzero,notnull,default:current_timestamp"`
+
+	tableName struct{} `bun:"shift_removals"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type Doctor struct {
 	BaseModel
 	UserID         int64  `json:"user_id" bun:",notnull"`
@@ -16,15 +18,36 @@ type ShiftLocation struct {
 	Name        string `json:"name" bun:",notnull"`
 	Description string `json:"description,omitempty"`
 
+	// Arşivlenen lokasyon listelerde görünmez ve planlanamaz, geçmiş nöbetleri korunur
+	ArchivedAt *time.Time `json:"archived_at,omitempty" bun:",nullzero"`
+
 	tableName struct{} `bun:"shift_locations"`
 }
 
+// Doktorun lokasyon üyeliği. Boş olan tarih sınırı açıktır, iki uç da dahildir.
 type DoctorShiftLocation struct {
 	BaseModel
-	DoctorID   int64         `json:"doctor_id" bun:",notnull"`
-	LocationID int64         `json:"location_id" bun:",notnull"`
-	Doctor     Doctor        `json:"-" bun:"rel:belongs-to,join:doctor_id=id"`
-	Location   ShiftLocation `json:"-" bun:"rel:belongs-to,join:location_id=id"`
+	DoctorID      int64         `json:"doctor_id" bun:",notnull"`
+	LocationID    int64         `json:"location_id" bun:",notnull"`
+	EffectiveFrom *time.Time    `json:"effective_from,omitempty" bun:",nullzero"`
+	EffectiveTo   *time.Time    `json:"effective_to,omitempty" bun:",nullzero"`
+	Doctor        Doctor        `json:"-" bun:"rel:belongs-to,join:doctor_id=id"`
+	Location      ShiftLocation `json:"-" bun:"rel:belongs-to,join:location_id=id"`
 
 	tableName struct{} `bun:"doctor_shift_locations"`
 }
+
+// Doktorun bir ay için nöbet tercihleri. Planlamada kesin kural değil, ağırlıklı istek olarak kullanılır.
+type DoctorPreference struct {
+	BaseModel
+	DoctorID           int64       `json:"doctor_id" bun:",notnull"`
+	Year               int         `json:"year" bun:",notnull"`
+	Month              int         `json:"month" bun:",notnull"`
+	PreferredWeekdays  []int       `json:"preferred_weekdays" bun:",type:jsonb,notnull"` // 0: Pazar
+	AvoidDates         []time.Time `json:"avoid_dates" bun:",type:jsonb,notnull"`
+	PreferredTemplates []int64     `json:"preferred_templates" bun:",type:jsonb,notnull"`
+	Weight             float64     `json:"weight" bun:",notnull,default:1"`
+	Doctor             Doctor      `json:"-" bun:"rel:belongs-to,join:doctor_id=id"`
+
+	tableName struct{} `bun:"doctor_preferences"`
+}
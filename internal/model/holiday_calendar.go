@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// Resmi ve dini tatillerin tutulduğu takvim (ör. Türkiye Resmi Tatilleri)
+type HolidayCalendar struct {
+	BaseModel
+	Name        string          `json:"name" bun:",notnull"`
+	Description string          `json:"description,omitempty"`
+	Entries     []PublicHoliday `json:"entries,omitempty" bun:"rel:has-many,join:id=calendar_id"`
+
+	tableName struct{} `bun:"holiday_calendars"`
+}
+
+// Takvimdeki tek bir tatil günü. Recurring olanlar sonraki her yıl aynı ay ve günde tekrarlanır.
+type PublicHoliday struct {
+	BaseModel
+	CalendarID  int64     `json:"calendar_id" bun:",notnull"`
+	UID         string    `json:"uid,omitempty" bun:"uid"` // ICS'den alınan kayıtlarda etkinlik UID'i
+	Name        string    `json:"name" bun:",notnull"`
+	HolidayDate time.Time `json:"holiday_date" bun:",notnull"`
+	Recurring   bool      `json:"recurring" bun:",notnull,default:false"`
+
+	tableName struct{} `bun:"public_holidays"`
+}
+
+// Lokasyonun kullandığı takvim ve tatil günleri için lokasyona özel ayarlar
+type LocationHolidayCalendar struct {
+	BaseModel
+	LocationID int64 `json:"location_id" bun:",notnull"`
+	CalendarID int64 `json:"calendar_id" bun:",notnull"`
+
+	// Tatil günlerinde gereken doktor sayısı, boşsa haftalık gereksinim geçerlidir
+	DoctorsRequired *int `json:"doctors_required"`
+	// Adalet hesabındaki ağırlık, 0 ise varsayılan resmi tatil ağırlığı kullanılır
+	Weight float64 `json:"weight" bun:",notnull,default:0"`
+
+	Calendar HolidayCalendar `json:"-" bun:"rel:belongs-to,join:calendar_id=id"`
+
+	tableName struct{} `bun:"location_holiday_calendars"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// İzin türleri
+const (
+	LeaveTypeAnnual       = "annual"
+	LeaveTypeSick         = "sick"
+	LeaveTypeConference   = "conference"
+	LeaveTypeCompensatory = "compensatory"
+)
+
+// İzin talebinin durumları.
+// pending -> approved | rejected | cancelled; diğerleri son durumdur.
+const (
+	LeaveStatusPending   = "pending"
+	LeaveStatusApproved  = "approved"
+	LeaveStatusRejected  = "rejected"
+	LeaveStatusCancelled = "cancelled"
+)
+
+// Doktorun tek gün ya da tarih aralığı için izin talebi. Onaylandığında her gün için Holiday kaydı oluşur.
+type LeaveRequest struct {
+	BaseModel
+	DoctorID      int64      `json:"doctor_id" bun:",notnull"`
+	LocationID    int64      `json:"location_id" bun:",notnull"`
+	LeaveType     string     `json:"leave_type" bun:",notnull"`
+	StartDate     time.Time  `json:"start_date" bun:",notnull"`
+	EndDate       time.Time  `json:"end_date" bun:",notnull"` // dahil
+	Reason        string     `json:"reason"`
+	Status        string     `json:"status" bun:",notnull,default:'pending'"`
+	ReviewedBy    int64      `json:"reviewed_by,omitempty" bun:",nullzero"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty" bun:",nullzero"`
+	ReviewComment string     `json:"review_comment,omitempty"`
+
+	// Talep edilen günlerde doktorun yayınlanmış nöbetleri
+	ConflictingShiftIDs []int64 `json:"conflicting_shift_ids" bun:",type:jsonb,notnull"`
+
+	Doctor   Doctor        `json:"-" bun:"rel:belongs-to,join:doctor_id=id"`
+	Location ShiftLocation `json:"-" bun:"rel:belongs-to,join:location_id=id"`
+
+	tableName struct{} `bun:"leave_requests"`
+}
+
+// Doktorun bir izin türü için yıllık hakkı. Tanımı olmayan izin türleri bakiyeyle sınırlanmaz.
+type LeaveEntitlement struct {
+	BaseModel
+	DoctorID     int64   `json:"doctor_id" bun:",notnull"`
+	LeaveType    string  `json:"leave_type" bun:",notnull"`
+	AnnualDays   float64 `json:"annual_days" bun:",notnull"`
+	Accrual      string  `json:"accrual" bun:",notnull,default:'upfront'"` // upfront | monthly
+	CarryOverMax float64 `json:"carry_over_max" bun:",notnull,default:0"`
+	StartYear    int     `json:"start_year" bun:",notnull"`
+
+	tableName struct{} `bun:"leave_entitlements"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"shift-scheduling-v2/internal/policy"
+	"time"
+)
+
+// Lokasyon politikasının bir sürümü. Sürümler değiştirilmez, her kayıt yeni sürüm oluşturur.
+type LocationPolicy struct {
+	ID         int64           `json:"id" bun:",pk,autoincrement"`
+	LocationID int64           `json:"location_id" bun:",notnull"`
+	Version    int             `json:"version" bun:",notnull"`
+	Document   policy.Document `json:"document" bun:",type:jsonb,notnull"`
+	CreatedBy  int64           `json:"created_by,omitempty" bun:",nullzero"`
+	CreatedAt  time.Time       `json:"created_at" bun:",nullzero,notnull,default:current_timestamp"`
+
+	tableName struct{} `bun:"location_policies"`
+}
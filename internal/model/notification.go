@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Bildirim olayları
+const (
+	EventSchedulePublished = "schedule_published"
+	EventShiftChanged      = "shift_changed"
+	EventSwapRequested     = "swap_requested"
+	EventSwapAnswered      = "swap_answered"
+	EventOfferClaimed      = "offer_claimed"
+	EventHolidayApproved   = "holiday_approved"
+)
+
+type Notification struct {
+	BaseModel
+	Event    string         `json:"event" bun:",notnull,default:''"`
+	Title    string         `json:"title" bun:",notnull,default:''"`
+	Message  string         `json:"message" bun:",notnull"`
+	Data     map[string]any `json:"data,omitempty" bun:",type:jsonb"`
+	DoctorID int64          `json:"doctor_id" bun:",notnull"`
+	IsRead   bool           `json:"is_read" bun:",notnull,default:false"`
+	ReadAt   *time.Time     `json:"read_at,omitempty" bun:",nullzero"`
+	Doctor   Doctor         `json:"-" bun:"rel:belongs-to,join:doctor_id=id"`
+
+	tableName struct{} `bun:"notifications"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// Aylık nöbet listesinin yayın durumları
+const (
+	// Sadece adminler görür, serbestçe düzenlenir
+	ScheduleStateDraft = "draft"
+	// Doktorlar görür, değişim ve ilanlar açıktır
+	ScheduleStatePublished = "published"
+	// Kesinleşmiştir, değişiklik gerekçe ister
+	ScheduleStateLocked = "locked"
+)
+
+// Geçiş geçmişine yazılan işlemler
+const (
+	ScheduleActionPublish   = "publish"
+	ScheduleActionUnpublish = "unpublish"
+	ScheduleActionLock      = "lock"
+	ScheduleActionUnlock    = "unlock"
+	ScheduleActionRollback  = "rollback"
+	// Kilitli ayda gerekçeyle yapılan nöbet değişikliği
+	ScheduleActionOverride = "override"
+)
+
+// Yayınlanan ayın değiştirilemez anlık görüntüsü
+type ScheduleSnapshot struct {
+	ID         int64         `json:"id" bun:",pk,autoincrement"`
+	LocationID int64         `json:"location_id" bun:",notnull"`
+	Year       int           `json:"year" bun:",notnull"`
+	Month      int           `json:"month" bun:",notnull"`
+	Version    int           `json:"version" bun:",notnull"`
+	Shifts     []DraftShift  `json:"shifts" bun:",type:jsonb,notnull"`
+	CreatedBy  int64         `json:"created_by,omitempty" bun:",nullzero"`
+	CreatedAt  time.Time     `json:"created_at" bun:",nullzero,notnull,default:current_timestamp"`
+	Location   ShiftLocation `json:"-" bun:"rel:belongs-to,join:location_id=id"`
+
+	tableName struct{} `bun:"schedule_snapshots"`
+}
+
+// Ayın durum geçişi ya da kilitli aydaki değişiklik kaydı
+type ScheduleTransition struct {
+	ID            int64     `json:"id" bun:",pk,autoincrement"`
+	LocationID    int64     `json:"location_id" bun:",notnull"`
+	Year          int       `json:"year" bun:",notnull"`
+	Month         int       `json:"month" bun:",notnull"`
+	Action        string    `json:"action" bun:",notnull"`
+	FromState     string    `json:"from_state" bun:",notnull"`
+	ToState       string    `json:"to_state" bun:",notnull"`
+	Version       int       `json:"version" bun:",notnull,default:0"`
+	SourceVersion int       `json:"source_version,omitempty" bun:",nullzero"` // geri almada dönülen sürüm
+	Reason        string    `json:"reason,omitempty" bun:",nullzero"`
+	CreatedBy     int64     `json:"created_by,omitempty" bun:",nullzero"`
+	CreatedAt     time.Time `json:"created_at" bun:",nullzero,notnull,default:current_timestamp"`
+
+	tableName struct{} `bun:"schedule_transitions"`
+}
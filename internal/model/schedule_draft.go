@@ -0,0 +1,48 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	DraftStatusDraft     = "draft"
+	DraftStatusCommitted = "committed"
+)
+
+// Otomatik atamanın kaydedilmiş önizlemesi. Daha sonra değiştirilmeden kaydedilebilir.
+type ScheduleDraft struct {
+	BaseModel
+	LocationID  int64           `json:"location_id" bun:",notnull"`
+	Year        int             `json:"year" bun:",notnull"`
+	Month       int             `json:"month" bun:",notnull"`
+	Status      string          `json:"status" bun:",notnull,default:'draft'"`
+	Shifts      []DraftShift    `json:"shifts" bun:",type:jsonb,notnull"`
+	Report      json.RawMessage `json:"report" bun:",type:jsonb"`
+	Complete    bool            `json:"complete" bun:",notnull"`
+	Required    map[string]int  `json:"required" bun:",type:jsonb,notnull"` // gün -> taslak oluşturulurken gereken nöbet sayısı
+	CreatedBy   int64           `json:"created_by" bun:",nullzero"`
+	CommittedAt *time.Time      `json:"committed_at,omitempty" bun:",nullzero"`
+	Location    ShiftLocation   `json:"-" bun:"rel:belongs-to,join:location_id=id"`
+
+	tableName struct{} `bun:"schedule_drafts"`
+}
+
+type DraftShift struct {
+	DoctorID   int64     `json:"doctor_id"`
+	ShiftDate  time.Time `json:"shift_date"`
+	StartTime  string    `json:"start_time"`
+	EndTime    string    `json:"end_time"`
+	TemplateID int64     `json:"template_id,omitempty"`
+}
+
+func (d DraftShift) ToShift(locationID int64) Shift {
+	return Shift{
+		DoctorID:   d.DoctorID,
+		LocationID: locationID,
+		ShiftDate:  d.ShiftDate,
+		StartTime:  d.StartTime,
+		EndTime:    d.EndTime,
+		TemplateID: d.TemplateID,
+	}
+}
@@ -4,24 +4,44 @@ import "time"
 
 type Shift struct {
 	BaseModel
-	DoctorID   int64         `json:"doctor_id" bun:",notnull"`
-	LocationID int64         `json:"location_id" bun:",notnull"`
-	ShiftDate  time.Time     `json:"shift_date" bun:",notnull"`
-	StartTime  string        `json:"start_time" bun:",notnull"`
-	EndTime    string        `json:"end_time" bun:",notnull"`
-	Doctor     Doctor        `json:"doctor" bun:"rel:belongs-to,join:doctor_id=id"`
-	Location   ShiftLocation `json:"location" bun:"rel:belongs-to,join:location_id=id"`
+	DoctorID   int64          `json:"doctor_id" bun:",notnull"`
+	LocationID int64          `json:"location_id" bun:",notnull"`
+	ShiftDate  time.Time      `json:"shift_date" bun:",notnull"`
+	StartTime  string         `json:"start_time" bun:",notnull"`
+	EndTime    string         `json:"end_time" bun:",notnull"`
+	TemplateID int64          `json:"template_id,omitempty" bun:",nullzero"`
+	Doctor     Doctor         `json:"doctor" bun:"rel:belongs-to,join:doctor_id=id"`
+	Location   ShiftLocation  `json:"location" bun:"rel:belongs-to,join:location_id=id"`
+	Template   *ShiftTemplate `json:"template,omitempty" bun:"rel:belongs-to,join:template_id=id"`
 
 	tableName struct{} `bun:"shifts"`
 }
 
+// Lokasyonun isimlendirilmiş nöbet zaman bloğu (ör. "Gündüz 08:00-16:00", "Gece 16:00-08:00").
+// Bitiş saati başlangıçtan küçük ya da eşitse nöbet ertesi güne sarkar.
+type ShiftTemplate struct {
+	BaseModel
+	LocationID      int64         `json:"location_id" bun:",notnull"`
+	Name            string        `json:"name" bun:",notnull"`
+	StartTime       string        `json:"start_time" bun:",notnull"`
+	EndTime         string        `json:"end_time" bun:",notnull"`
+	DurationMinutes int           `json:"duration_minutes" bun:",notnull"`
+	Location        ShiftLocation `json:"-" bun:"rel:belongs-to,join:location_id=id"`
+
+	tableName struct{} `bun:"shift_templates"`
+}
+
 type Holiday struct {
 	BaseModel
-	DoctorID    int64         `json:"doctor_id" bun:",notnull"`
-	LocationID  int64         `json:"location_id" bun:",notnull"`
-	HolidayDate time.Time     `json:"holiday_date" bun:",notnull"`
-	Doctor      Doctor        `json:"-" bun:"rel:belongs-to,join:doctor_id=id"`
-	Location    ShiftLocation `json:"-" bun:"rel:belongs-to,join:location_id=id"`
+	DoctorID    int64     `json:"doctor_id" bun:",notnull"`
+	LocationID  int64     `json:"location_id" bun:",notnull"`
+	HolidayDate time.Time `json:"holiday_date" bun:",notnull"`
+
+	// İzin talebinden oluşan kayıtlarda talep ve izin türü
+	LeaveRequestID int64         `json:"leave_request_id,omitempty" bun:",nullzero"`
+	LeaveType      string        `json:"leave_type,omitempty" bun:",nullzero"`
+	Doctor         Doctor        `json:"-" bun:"rel:belongs-to,join:doctor_id=id"`
+	Location       ShiftLocation `json:"-" bun:"rel:belongs-to,join:location_id=id"`
 
 	tableName struct{} `bun:"holidays"`
 }
@@ -34,5 +54,27 @@ type ShiftsStatus struct {
 	LocationID int64         `json:"location_id" bun:",notnull"`
 	Location   ShiftLocation `json:"-" bun:"rel:belongs-to,join:location_id=id"`
 
+	// Yayın durumu: draft -> published -> locked. Version son yayınlanan anlık görüntüdür.
+	State       string     `json:"state" bun:",notnull,default:'draft'"`
+	Version     int        `json:"version" bun:",notnull,default:0"`
+	PublishedAt *time.Time `json:"published_at,omitempty" bun:",nullzero"`
+	LockedAt    *time.Time `json:"locked_at,omitempty" bun:",nullzero"`
+
+	// Kapsama: gereken nöbet sayısı, bunlardan doldurulanlar ve yüzdesi
+	RequiredShifts int     `json:"required_shifts" bun:",notnull,default:0"`
+	CoveredShifts  int     `json:"covered_shifts" bun:",notnull,default:0"`
+	Coverage       float64 `json:"coverage" bun:",notnull,default:0"`
+
 	tableName struct{} `bun:"shifts_status"`
 }
+
+// Belirli bir gün için haftalık gereksinimin yerine geçen doktor sayısı
+type CoverageOverride struct {
+	BaseModel
+	LocationID      int64         `json:"location_id" bun:",notnull"`
+	OverrideDate    time.Time     `json:"override_date" bun:",notnull"`
+	DoctorsRequired int           `json:"doctors_required" bun:",notnull"`
+	Location        ShiftLocation `json:"-" bun:"rel:belongs-to,join:location_id=id"`
+
+	tableName struct{} `bun:"coverage_overrides"`
+}
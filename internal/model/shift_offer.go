@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// Nöbet ilanının durumları.
+// open -> claimed | transferred | cancelled | expired (onay gerekmeyen lokasyonda alınan nöbet doğrudan transferred olur)
+// claimed -> transferred | open | cancelled | expired (admin reddederse ilan tekrar açılır)
+// transferred, cancelled ve expired son durumlardır.
+const (
+	OfferStatusOpen        = "open"
+	OfferStatusClaimed     = "claimed"
+	OfferStatusTransferred = "transferred"
+	OfferStatusCancelled   = "cancelled"
+	OfferStatusExpired     = "expired"
+)
+
+// Doktorun karşılık beklemeden devretmek istediği nöbet. Lokasyondaki uygun ilk doktor nöbeti alır.
+type ShiftOffer struct {
+	BaseModel
+	ShiftID    int64      `json:"shift_id" bun:",notnull"`
+	LocationID int64      `json:"location_id" bun:",notnull"`
+	ShiftDate  time.Time  `json:"shift_date" bun:",notnull"`
+	OfferedBy  int64      `json:"offered_by" bun:",notnull"`
+	ClaimedBy  int64      `json:"claimed_by,omitempty" bun:",nullzero"`
+	ClaimedAt  *time.Time `json:"claimed_at,omitempty" bun:",nullzero"`
+	Comment    string     `json:"comment"`
+	Status     string     `json:"status" bun:",notnull,default:'open'"`
+	ReviewedBy int64      `json:"reviewed_by,omitempty" bun:",nullzero"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty" bun:",nullzero"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty" bun:",nullzero"`
+
+	Shift   Shift   `json:"shift" bun:"rel:belongs-to,join:shift_id=id"`
+	Offerer Doctor  `json:"-" bun:"rel:belongs-to,join:offered_by=id"`
+	Claimer *Doctor `json:"-" bun:"rel:belongs-to,join:claimed_by=id"`
+
+	tableName struct{} `bun:"shift_offers"`
+}
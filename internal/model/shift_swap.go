@@ -2,19 +2,38 @@ package model
 
 import "time"
 
+// Nöbet değişim talebinin durumları.
+// pending -> accepted | approved | rejected | cancelled | expired (onay gerekmeyen lokasyonda kabul doğrudan approved olur)
+// accepted -> approved | rejected | cancelled | expired
+// approved, rejected, cancelled ve expired son durumlardır.
+const (
+	SwapStatusPending   = "pending"
+	SwapStatusAccepted  = "accepted"
+	SwapStatusRejected  = "rejected"
+	SwapStatusApproved  = "approved"
+	SwapStatusCancelled = "cancelled"
+	SwapStatusExpired   = "expired"
+)
+
+// Nöbet değişim talebi. Talep eden doktor (Requester) kendi nöbetini (OfferedShift)
+// meslektaşının nöbetiyle (RequestedShift) değiştirmek ister. Değişim onaylandığında
+// iki nöbetin doktorları yer değiştirir.
 type ShiftSwapRequest struct {
 	BaseModel
-	LocationID       int64     `json:"location_id" bun:",notnull"`
-	RequesterID      int64     `json:"requester_id" bun:",notnull"`
-	RequestShiftDate time.Time `json:"request_shift_date" bun:",notnull"`
-	RequestedShiftID int64     `json:"requested_shift_id" bun:",notnull"`
-	AcceptorID       int64     `json:"acceptor_id" bun:",notnull"`
-	OfferedShiftDate time.Time `json:"offered_shift_date" bun:",notnull"`
-	OfferedShiftID   int64     `json:"offered_shift_id" bun:",notnull"`
-	MutualAgreement  bool      `json:"mutual_agreement"`
-	RequesterComment string    `json:"requester_comment"`
-	AcceptorComment  string    `json:"acceptor_comment"`
-	Status           string    `json:"status" bun:",notnull,default:'pending'"`
+	LocationID       int64      `json:"location_id" bun:",notnull"`
+	RequesterID      int64      `json:"requester_id" bun:",notnull"`
+	RequestShiftDate time.Time  `json:"request_shift_date" bun:",notnull"`
+	RequestedShiftID int64      `json:"requested_shift_id" bun:",notnull"`
+	AcceptorID       int64      `json:"acceptor_id" bun:",notnull"`
+	OfferedShiftDate time.Time  `json:"offered_shift_date" bun:",notnull"`
+	OfferedShiftID   int64      `json:"offered_shift_id" bun:",notnull"`
+	MutualAgreement  bool       `json:"mutual_agreement"`
+	RequesterComment string     `json:"requester_comment"`
+	AcceptorComment  string     `json:"acceptor_comment"`
+	Status           string     `json:"status" bun:",notnull,default:'pending'"`
+	ReviewedBy       int64      `json:"reviewed_by,omitempty" bun:",nullzero"` // onaylayan ya da reddeden admin
+	ReviewedAt       *time.Time `json:"reviewed_at,omitempty" bun:",nullzero"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty" bun:",nullzero"` // son duruma geçiş zamanı
 
 	Location       ShiftLocation `json:"-" bun:"rel:belongs-to,join:location_id=id"`
 	Requester      Doctor        `json:"requester" bun:"rel:belongs-to,join:requester_id=id"`
@@ -24,13 +43,3 @@ type ShiftSwapRequest struct {
 
 	tableName struct{} `bun:"shift_swap_requests"`
 }
-
-type Notification struct {
-	BaseModel
-	Message  string `json:"message" bun:",notnull"`
-	DoctorID int64  `json:"doctor_id" bun:",notnull"`
-	IsRead   bool   `json:"is_read" bun:",notnull,default:false"`
-	Doctor   Doctor `json:"-" bun:"rel:belongs-to,join:doctor_id=id"`
-
-	tableName struct{} `bun:"notifications"`
-}
@@ -36,7 +36,7 @@ type User struct {
 	tableName struct{} `bun:"users"`
 }
 
-func (u User) SetPassword(password string) error {
+func (u *User) SetPassword(password string) error {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
@@ -0,0 +1,19 @@
+package model_test
+
+import (
+	"shift-scheduling-v2/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserSetPasswordStoresHash(t *testing.T) {
+	var user model.User
+	require.NoError(t, user.SetPassword("gizli-sifre"))
+
+	assert.NotEmpty(t, user.Password)
+	assert.NotEqual(t, "gizli-sifre", user.Password)
+	assert.True(t, user.CheckPassword("gizli-sifre"))
+	assert.False(t, user.CheckPassword("yanlis"))
+}
@@ -0,0 +1,167 @@
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Politika tanımlanmamış lokasyonlarda kullanılan değerler
+const (
+	DefaultTimeZone        = "Europe/Istanbul"
+	DefaultShiftStart      = "08:00"
+	DefaultShiftHours      = 24
+	DefaultDoctorsRequired = 1
+)
+
+// Lokasyonun planlama politikası. Planlama, doğrulama ve değişim akışları
+// sabitler yerine bu belgeyi okur. Sıfır olan limit ve kurallar uygulanmaz.
+type Document struct {
+	TimeZone     string    `json:"time_zone"`
+	DefaultShift Shift     `json:"default_shift"`
+	Coverage     Coverage  `json:"coverage"`
+	RestRules    RestRules `json:"rest_rules"`
+	// Doktor başına aylık en fazla nöbet. Doktorun kendi limiti varsa küçük olan geçerlidir.
+	MaxShiftsPerMonth int   `json:"max_shifts_per_month"`
+	WeekendDays       []int `json:"weekend_days"` // 0: Pazar
+	// Doktorlar arasında kabul edilen nöbet değişimleri admin onayı bekler
+	SwapRequiresApproval bool `json:"swap_requires_approval"`
+	// İlana çıkarılan nöbeti alan doktor admin onayından sonra nöbetin sahibi olur
+	OfferRequiresApproval bool `json:"offer_requires_approval"`
+}
+
+// Lokasyonda şablon tanımlı değilken kullanılan nöbet bloğu
+type Shift struct {
+	Start string `json:"start"` // HH:MM
+	Hours int    `json:"hours"`
+}
+
+// Günlük doktor ihtiyacı. Weekdays'de olmayan günler için Default kullanılır.
+type Coverage struct {
+	Default  int               `json:"default"`
+	Weekdays []WeekdayCoverage `json:"weekdays"`
+}
+
+type WeekdayCoverage struct {
+	Weekday         int `json:"weekday"` // 0: Pazar
+	DoctorsRequired int `json:"doctors_required"`
+}
+
+type RestRules struct {
+	MinRestHours       int `json:"min_rest_hours"`
+	MaxConsecutiveDays int `json:"max_consecutive_days"`
+	MaxShiftsPerWeek   int `json:"max_shifts_per_week"`
+}
+
+// Doğrulamada bulunan tek bir hata
+type Problem struct {
+	Field   string
+	Message string
+}
+
+// Politika tanımlanmamış lokasyonun belgesi
+func Default() Document {
+	return Document{
+		TimeZone:     DefaultTimeZone,
+		DefaultShift: Shift{Start: DefaultShiftStart, Hours: DefaultShiftHours},
+		Coverage:     Coverage{Default: DefaultDoctorsRequired},
+		WeekendDays:  []int{int(time.Saturday), int(time.Sunday)},
+	}
+}
+
+// Belgeyi doğrular ve bulunan tüm hataları döner
+func (d Document) Validate() []Problem {
+	var problems []Problem
+	add := func(field string, format string, args ...any) {
+		problems = append(problems, Problem{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if d.TimeZone == "" {
+		add("time_zone", "Saat dilimi zorunludur")
+	} else if _, err := time.LoadLocation(d.TimeZone); err != nil {
+		add("time_zone", "Bilinmeyen saat dilimi: %s", d.TimeZone)
+	}
+
+	if _, err := time.Parse("15:04", d.DefaultShift.Start); err != nil {
+		add("default_shift.start", "Başlangıç saati HH:MM formatında olmalıdır")
+	}
+	if d.DefaultShift.Hours < 1 || d.DefaultShift.Hours > 48 {
+		add("default_shift.hours", "Nöbet süresi 1 ile 48 saat arasında olmalıdır")
+	}
+
+	if d.Coverage.Default < 0 {
+		add("coverage.default", "Doktor sayısı negatif olamaz")
+	}
+	seen := make(map[int]bool)
+	for i, w := range d.Coverage.Weekdays {
+		field := fmt.Sprintf("coverage.weekdays[%d]", i)
+		if w.Weekday < 0 || w.Weekday > 6 {
+			add(field, "Gün 0 (Pazar) ile 6 arasında olmalıdır")
+		} else if seen[w.Weekday] {
+			add(field, "%d. gün birden fazla tanımlanmış", w.Weekday)
+		}
+		seen[w.Weekday] = true
+		if w.DoctorsRequired < 0 {
+			add(field, "Doktor sayısı negatif olamaz")
+		}
+	}
+
+	if d.RestRules.MinRestHours < 0 || d.RestRules.MaxConsecutiveDays < 0 || d.RestRules.MaxShiftsPerWeek < 0 {
+		add("rest_rules", "Kural değerleri negatif olamaz")
+	}
+	if d.MaxShiftsPerMonth < 0 {
+		add("max_shifts_per_month", "Aylık nöbet limiti negatif olamaz")
+	}
+
+	days := make(map[int]bool)
+	for i, w := range d.WeekendDays {
+		field := fmt.Sprintf("weekend_days[%d]", i)
+		if w < 0 || w > 6 {
+			add(field, "Gün 0 (Pazar) ile 6 arasında olmalıdır")
+		} else if days[w] {
+			add(field, "%d. gün birden fazla tanımlanmış", w)
+		}
+		days[w] = true
+	}
+
+	return problems
+}
+
+// Belgenin saat dilimi. Geçersizse varsayılan saat dilimi kullanılır.
+func (d Document) Location() *time.Location {
+	if d.TimeZone != "" {
+		if loc, err := time.LoadLocation(d.TimeZone); err == nil {
+			return loc
+		}
+	}
+	if loc, err := time.LoadLocation(DefaultTimeZone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// Haftanın günü için gereken doktor sayısı
+func (d Document) Required(weekday time.Weekday) int {
+	for _, w := range d.Coverage.Weekdays {
+		if w.Weekday == int(weekday) {
+			return w.DoctorsRequired
+		}
+	}
+	return d.Coverage.Default
+}
+
+func (d Document) IsWeekend(day time.Time) bool {
+	return slices.Contains(d.WeekendDays, int(day.Weekday()))
+}
+
+// Doktorun aylık limiti ile lokasyon limitinden geçerli olanı. 0 limitsizdir.
+func (d Document) ShiftLimit(doctorLimit int) int {
+	switch {
+	case d.MaxShiftsPerMonth == 0:
+		return doctorLimit
+	case doctorLimit == 0:
+		return d.MaxShiftsPerMonth
+	default:
+		return min(doctorLimit, d.MaxShiftsPerMonth)
+	}
+}
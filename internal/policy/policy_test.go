@@ -0,0 +1,75 @@
+package policy_test
+
+import (
+	"shift-scheduling-v2/internal/policy"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultPolicyIsValid(t *testing.T) {
+	doc := policy.Default()
+
+	assert.Empty(t, doc.Validate())
+	assert.Equal(t, 1, doc.Required(time.Monday))
+	assert.True(t, doc.IsWeekend(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)))  // Cumartesi
+	assert.False(t, doc.IsWeekend(time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC))) // Pazartesi
+	assert.Equal(t, "Europe/Istanbul", doc.Location().String())
+}
+
+func TestPolicyValidateReportsEveryProblem(t *testing.T) {
+	doc := policy.Document{
+		TimeZone:     "Mars/Olympus",
+		DefaultShift: policy.Shift{Start: "8am", Hours: 0},
+		Coverage: policy.Coverage{
+			Default:  -1,
+			Weekdays: []policy.WeekdayCoverage{{Weekday: 1, DoctorsRequired: 2}, {Weekday: 1, DoctorsRequired: 3}, {Weekday: 7}},
+		},
+		RestRules:         policy.RestRules{MinRestHours: -4},
+		MaxShiftsPerMonth: -1,
+		WeekendDays:       []int{5, 5},
+	}
+
+	fields := make([]string, 0)
+	for _, p := range doc.Validate() {
+		fields = append(fields, p.Field)
+	}
+	assert.Equal(t, []string{
+		"time_zone",
+		"default_shift.start",
+		"default_shift.hours",
+		"coverage.default",
+		"coverage.weekdays[1]",
+		"coverage.weekdays[2]",
+		"rest_rules",
+		"max_shifts_per_month",
+		"weekend_days[1]",
+	}, fields)
+}
+
+func TestPolicyCoverageAndWeekend(t *testing.T) {
+	doc := policy.Default()
+	doc.TimeZone = "America/New_York"
+	doc.Coverage = policy.Coverage{Default: 2, Weekdays: []policy.WeekdayCoverage{{Weekday: int(time.Sunday), DoctorsRequired: 0}}}
+	doc.WeekendDays = []int{int(time.Friday), int(time.Saturday)}
+	require.Empty(t, doc.Validate())
+
+	assert.Equal(t, 0, doc.Required(time.Sunday))
+	assert.Equal(t, 2, doc.Required(time.Wednesday))
+	assert.True(t, doc.IsWeekend(time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)))  // Cuma
+	assert.False(t, doc.IsWeekend(time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC))) // Pazar
+	assert.Equal(t, "America/New_York", doc.Location().String())
+}
+
+func TestPolicyShiftLimit(t *testing.T) {
+	doc := policy.Default()
+	assert.Equal(t, 0, doc.ShiftLimit(0))
+	assert.Equal(t, 6, doc.ShiftLimit(6))
+
+	doc.MaxShiftsPerMonth = 8
+	assert.Equal(t, 8, doc.ShiftLimit(0))
+	assert.Equal(t, 6, doc.ShiftLimit(6))
+	assert.Equal(t, 8, doc.ShiftLimit(10))
+}
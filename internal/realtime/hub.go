@@ -0,0 +1,142 @@
+package realtime
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Olay tipleri
+const (
+	EventShiftCreated      = "shift.created"
+	EventShiftUpdated      = "shift.updated"
+	EventShiftDeleted      = "shift.deleted"
+	EventSchedulePublished = "schedule.published"
+	EventScheduleWithdrawn = "schedule.withdrawn"
+	EventSwapChanged       = "swap.changed"
+	EventOfferChanged      = "offer.changed"
+	EventNotification      = "notification"
+)
+
+// Olayı kimlerin göreceği
+type Audience string
+
+const (
+	// Adminler ve lokasyondaki doktorlar
+	AudienceLocation Audience = "location"
+	// Adminler ve DoctorIDs içindeki doktorlar
+	AudienceParticipants Audience = "participants"
+	// Sadece DoctorIDs içindeki doktorlar
+	AudiencePrivate Audience = "private"
+)
+
+// Abonelik başına tamponlanan olay sayısı, dolarsa yavaş istemcinin olayları atlanır
+const subscriberBuffer = 64
+
+type Event struct {
+	Type       string    `json:"type"`
+	Audience   Audience  `json:"audience"`
+	LocationID int64     `json:"location_id,omitempty"`
+	DoctorIDs  []int64   `json:"doctor_ids,omitempty"`
+	Data       any       `json:"data,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// Abonenin rolü ve lokasyonlarına göre hangi olayları alacağını belirler
+type Filter struct {
+	Admin     bool
+	DoctorID  int64
+	Locations []int64 // doktorun kayıtlı olduğu lokasyonlar
+	Location  int64   // istemcinin seçtiği lokasyon, 0 ise hepsi
+}
+
+func (f Filter) Match(ev Event) bool {
+	if f.Location != 0 && ev.LocationID != 0 && ev.LocationID != f.Location {
+		return false
+	}
+
+	involved := f.DoctorID != 0 && slices.Contains(ev.DoctorIDs, f.DoctorID)
+	switch ev.Audience {
+	case AudiencePrivate:
+		return involved
+	case AudienceParticipants:
+		return f.Admin || involved
+	default:
+		return f.Admin || slices.Contains(f.Locations, ev.LocationID)
+	}
+}
+
+type Subscription struct {
+	filter Filter
+	events chan Event
+	hub    *Hub
+	once   sync.Once
+}
+
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.hub.mu.Lock()
+		delete(s.hub.subs, s)
+		s.hub.mu.Unlock()
+		close(s.events)
+	})
+}
+
+// Bu sunucuya bağlı istemcilere olay dağıtır
+type Hub struct {
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[*Subscription]struct{})}
+}
+
+func (h *Hub) Subscribe(f Filter) *Subscription {
+	sub := &Subscription{filter: f, events: make(chan Event, subscriberBuffer), hub: h}
+
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+// Olayı filtresi eşleşen abonelere iletir, dolu tamponlu aboneler beklenmez
+func (h *Hub) Broadcast(ev Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subs {
+		if !sub.filter.Match(ev) {
+			continue
+		}
+		select {
+		case sub.events <- ev:
+		default:
+		}
+	}
+}
+
+// Tüm abonelikleri kapatır, açık akışlar sonlanır
+func (h *Hub) CloseAll() {
+	h.mu.RLock()
+	subs := make([]*Subscription, 0, len(h.subs))
+	for sub := range h.subs {
+		subs = append(subs, sub)
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range subs {
+		sub.Close()
+	}
+}
+
+func (h *Hub) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subs)
+}
@@ -0,0 +1,74 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"shift-scheduling-v2/pkg/cache"
+	"shift-scheduling-v2/pkg/logger"
+	"sync/atomic"
+	"time"
+)
+
+// Olayların sunucular arasında dağıtıldığı Redis kanalı
+const redisChannel = "shift-scheduling:events"
+
+// Abonelik koptuğunda yeniden bağlanmadan önce beklenen süre
+const resubscribeDelay = 3 * time.Second
+
+var (
+	defaultHub  = NewHub()
+	distributed atomic.Bool
+)
+
+// Redis aboneliğini başlatır. Start çağrılmazsa olaylar sadece bu sunucudaki istemcilere gider.
+func Start(ctx context.Context) {
+	distributed.Store(true)
+
+	go func() {
+		for {
+			err := cache.Subscribe(ctx, redisChannel, func(payload []byte) {
+				var ev Event
+				if err := json.Unmarshal(payload, &ev); err != nil {
+					logger.Error("Geçersiz olay: %v", err)
+					return
+				}
+				defaultHub.Broadcast(ev)
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Error("Olay aboneliği koptu: %v", err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(resubscribeDelay):
+			}
+		}
+	}()
+}
+
+// Olayı tüm sunuculara yayınlar. Redis'e yazılamazsa olay en azından yerel istemcilere iletilir.
+func Publish(ctx context.Context, ev Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	if distributed.Load() {
+		err := cache.Publish(ctx, redisChannel, ev)
+		if err == nil {
+			return
+		}
+		logger.Error("Olay yayınlanamadı (%s): %v", ev.Type, err)
+	}
+	defaultHub.Broadcast(ev)
+}
+
+// Sunucu kapanırken açık akışları sonlandırır
+func Stop() {
+	defaultHub.CloseAll()
+}
+
+func Subscribe(f Filter) *Subscription {
+	return defaultHub.Subscribe(f)
+}
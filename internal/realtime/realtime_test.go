@@ -0,0 +1,62 @@
+package realtime_test
+
+import (
+	"shift-scheduling-v2/internal/realtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealtimeFilterByRoleAndLocation(t *testing.T) {
+	admin := realtime.Filter{Admin: true}
+	adminOfTwo := realtime.Filter{Admin: true, Location: 2}
+	doctor := realtime.Filter{DoctorID: 5, Locations: []int64{1}}
+	other := realtime.Filter{DoctorID: 6, Locations: []int64{2}}
+
+	shift := realtime.Event{Type: realtime.EventShiftCreated, Audience: realtime.AudienceLocation, LocationID: 1, DoctorIDs: []int64{5}}
+	assert.True(t, admin.Match(shift))
+	assert.False(t, adminOfTwo.Match(shift))
+	assert.True(t, doctor.Match(shift))
+	assert.False(t, other.Match(shift))
+
+	swap := realtime.Event{Type: realtime.EventSwapChanged, Audience: realtime.AudienceParticipants, LocationID: 2, DoctorIDs: []int64{5, 7}}
+	assert.True(t, admin.Match(swap))
+	assert.True(t, doctor.Match(swap))
+	assert.False(t, other.Match(swap), "aynı lokasyonda olsa da talebin tarafı değil")
+
+	notification := realtime.Event{Type: realtime.EventNotification, Audience: realtime.AudiencePrivate, DoctorIDs: []int64{6}}
+	assert.False(t, admin.Match(notification))
+	assert.False(t, doctor.Match(notification))
+	assert.True(t, other.Match(notification))
+}
+
+func TestRealtimeHubDeliversMatchingEvents(t *testing.T) {
+	hub := realtime.NewHub()
+	first := hub.Subscribe(realtime.Filter{DoctorID: 1, Locations: []int64{10}})
+	second := hub.Subscribe(realtime.Filter{DoctorID: 2, Locations: []int64{20}})
+	defer second.Close()
+
+	hub.Broadcast(realtime.Event{Type: realtime.EventShiftUpdated, Audience: realtime.AudienceLocation, LocationID: 10})
+
+	select {
+	case ev := <-first.Events():
+		assert.Equal(t, realtime.EventShiftUpdated, ev.Type)
+	case <-time.After(time.Second):
+		t.Fatal("olay iletilmedi")
+	}
+	select {
+	case <-second.Events():
+		t.Fatal("başka lokasyonun olayı iletilmemeli")
+	default:
+	}
+
+	first.Close()
+	first.Close()
+	assert.Equal(t, 1, hub.Len())
+
+	hub.CloseAll()
+	_, open := <-second.Events()
+	assert.False(t, open)
+	assert.Equal(t, 0, hub.Len())
+}
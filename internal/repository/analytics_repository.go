@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/pkg/cache"
+	"sort"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+const (
+	analyticsCacheKeyPrefix = "analytics:"
+	analyticsCacheDuration  = 15 * time.Minute
+)
+
+type AnalyticsRepository struct {
+	db *bun.DB
+}
+
+func NewAnalyticsRepository(db *bun.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// Lokasyonun [Start, End) aralığı için yük sorgusu. Hafta sonu günleri politikadan,
+// resmi tatiller lokasyonun takvimlerinden gelir.
+type WorkloadQuery struct {
+	LocationID     int64
+	Start          time.Time
+	End            time.Time
+	WeekendDays    []int // 0: Pazar
+	PublicHolidays []time.Time
+}
+
+// Doktor bazında nöbet toplamları. Resmi tatildeki nöbet hafta sonuna sayılmaz,
+// ertesi güne sarkan nöbet gece nöbetidir.
+type ShiftLoad struct {
+	DoctorID        int64   `bun:"doctor_id" json:"doctor_id"`
+	Shifts          int     `bun:"shifts" json:"shifts"`
+	Hours           float64 `bun:"hours" json:"hours"`
+	WeekendShifts   int     `bun:"weekend_shifts" json:"weekend_shifts"`
+	HolidayShifts   int     `bun:"holiday_shifts" json:"holiday_shifts"`
+	OvernightShifts int     `bun:"overnight_shifts" json:"overnight_shifts"`
+}
+
+// Doktorun lokasyondaki izin günleri
+type LeaveLoad struct {
+	DoctorID int64 `bun:"doctor_id" json:"doctor_id"`
+	Days     int   `bun:"days" json:"days"`
+}
+
+// Doktorun açtığı, karşı taraf olduğu ve onaylanıp uygulanan değişim talepleri
+type SwapLoad struct {
+	DoctorID  int64 `bun:"doctor_id" json:"doctor_id"`
+	Requested int   `bun:"requested" json:"requested"`
+	Received  int   `bun:"received" json:"received"`
+	Completed int   `bun:"completed" json:"completed"`
+}
+
+// Önbelleğe alınan toplamlar
+type LocationWorkload struct {
+	Shifts []ShiftLoad `json:"shifts"`
+	Leaves []LeaveLoad `json:"leaves"`
+	Swaps  []SwapLoad  `json:"swaps"`
+}
+
+// Lokasyonun aralıktaki nöbet, izin ve değişim toplamlarını doktor bazında döner.
+// Sonuç önbelleğe alınır ve lokasyondaki nöbet, izin ya da değişim yazımlarında silinir.
+func (r *AnalyticsRepository) GetLocationWorkload(ctx context.Context, q WorkloadQuery) (*LocationWorkload, error) {
+	cacheKey := analyticsCacheKey(q)
+
+	var workload LocationWorkload
+	if err := cache.Get(ctx, cacheKey, &workload); err == nil {
+		return &workload, nil
+	}
+
+	shifts, err := r.shiftLoads(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	leaves, err := r.leaveLoads(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	swaps, err := r.swapLoads(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	workload = LocationWorkload{Shifts: shifts, Leaves: leaves, Swaps: swaps}
+
+	_ = cache.Set(ctx, cacheKey, &workload, analyticsCacheDuration)
+	return &workload, nil
+}
+
+func (r *AnalyticsRepository) shiftLoads(ctx context.Context, q WorkloadQuery) ([]ShiftLoad, error) {
+	holiday := bun.SafeQuery("FALSE")
+	if len(q.PublicHolidays) > 0 {
+		dates := make([]string, len(q.PublicHolidays))
+		for i, day := range q.PublicHolidays {
+			dates[i] = day.Format("2006-01-02")
+		}
+		holiday = bun.SafeQuery("shift_date IN (?)", bun.In(dates))
+	}
+	weekend := bun.SafeQuery("FALSE")
+	if len(q.WeekendDays) > 0 {
+		weekend = bun.SafeQuery("EXTRACT(DOW FROM shift_date) IN (?) AND NOT (?)", bun.In(q.WeekendDays), holiday)
+	}
+
+	var loads []ShiftLoad
+	err := r.db.NewSelect().
+		Model((*model.Shift)(nil)).
+		ColumnExpr("doctor_id").
+		ColumnExpr("COUNT(*) AS shifts").
+		ColumnExpr("COALESCE(SUM(EXTRACT(EPOCH FROM end_time::time - start_time::time) / 3600 + CASE WHEN end_time <= start_time THEN 24 ELSE 0 END), 0) AS hours").
+		ColumnExpr("COUNT(*) FILTER (WHERE ?) AS weekend_shifts", weekend).
+		ColumnExpr("COUNT(*) FILTER (WHERE ?) AS holiday_shifts", holiday).
+		ColumnExpr("COUNT(*) FILTER (WHERE end_time <= start_time) AS overnight_shifts").
+		Where("location_id = ?", q.LocationID).
+		Where("shift_date >= ? AND shift_date < ?", q.Start, q.End).
+		GroupExpr("doctor_id").
+		OrderExpr("doctor_id ASC").
+		Scan(ctx, &loads)
+	return loads, err
+}
+
+func (r *AnalyticsRepository) leaveLoads(ctx context.Context, q WorkloadQuery) ([]LeaveLoad, error) {
+	var loads []LeaveLoad
+	err := r.db.NewSelect().
+		Model((*model.Holiday)(nil)).
+		ColumnExpr("doctor_id").
+		ColumnExpr("COUNT(DISTINCT holiday_date) AS days").
+		Where("location_id = ?", q.LocationID).
+		Where("holiday_date >= ? AND holiday_date < ?", q.Start, q.End).
+		GroupExpr("doctor_id").
+		OrderExpr("doctor_id ASC").
+		Scan(ctx, &loads)
+	return loads, err
+}
+
+// Talepler değiştirilen nöbetlerden önce geleninin tarihine göre aralığa dahil edilir
+func (r *AnalyticsRepository) swapLoads(ctx context.Context, q WorkloadQuery) ([]SwapLoad, error) {
+	loads := make(map[int64]*SwapLoad)
+	for _, side := range []struct {
+		column string
+		count  string
+	}{{"requester_id", "requested"}, {"acceptor_id", "received"}} {
+		var rows []SwapLoad
+		err := r.db.NewSelect().
+			Model((*model.ShiftSwapRequest)(nil)).
+			ColumnExpr("? AS doctor_id", bun.Ident(side.column)).
+			ColumnExpr("COUNT(*) AS ?", bun.Ident(side.count)).
+			ColumnExpr("COUNT(*) FILTER (WHERE status = ?) AS completed", model.SwapStatusApproved).
+			Where("location_id = ?", q.LocationID).
+			Where("LEAST(request_shift_date, offered_shift_date) >= ?", q.Start).
+			Where("LEAST(request_shift_date, offered_shift_date) < ?", q.End).
+			GroupExpr("1").
+			Scan(ctx, &rows)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			load, ok := loads[row.DoctorID]
+			if !ok {
+				load = &SwapLoad{DoctorID: row.DoctorID}
+				loads[row.DoctorID] = load
+			}
+			load.Requested += row.Requested
+			load.Received += row.Received
+			load.Completed += row.Completed
+		}
+	}
+
+	result := make([]SwapLoad, 0, len(loads))
+	for _, load := range loads {
+		result = append(result, *load)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].DoctorID < result[j].DoctorID })
+	return result, nil
+}
+
+// Anahtar lokasyonla başlar, politika ve takvim değişikliklerinin eski sonucu
+// döndürmemesi için hafta sonu günleri ve tatillerin özetini de içerir
+func analyticsCacheKey(q WorkloadQuery) string {
+	h := fnv.New64a()
+	fmt.Fprint(h, q.WeekendDays)
+	for _, day := range q.PublicHolidays {
+		h.Write([]byte(day.Format("2006-01-02")))
+	}
+	return fmt.Sprintf("%s%d:%s:%s:%x", analyticsCacheKeyPrefix, q.LocationID, q.Start.Format("2006-01-02"), q.End.Format("2006-01-02"), h.Sum64())
+}
+
+// Lokasyonların önbellekteki analizlerini siler. Lokasyon verilmezse tüm analizler silinir.
+func invalidateAnalytics(ctx context.Context, locationIDs ...int64) {
+	if len(locationIDs) == 0 {
+		_ = cache.DeleteMany(ctx, analyticsCacheKeyPrefix+"*")
+		return
+	}
+	for _, id := range locationIDs {
+		_ = cache.DeleteMany(ctx, fmt.Sprintf("%s%d:*", analyticsCacheKeyPrefix, id))
+	}
+}
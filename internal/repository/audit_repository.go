@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/pkg/query"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Denetim kayıtları için filtre, sıfır değerli alanlar uygulanmaz
+type AuditFilter struct {
+	Entity   string
+	EntityID int64
+	ActorID  int64
+	From     time.Time
+	To       time.Time
+}
+
+type AuditRepository struct {
+	db *bun.DB
+}
+
+func NewAuditRepository(db *bun.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, entry *model.AuditLog) error {
+	_, err := r.db.NewInsert().Model(entry).Exec(ctx)
+	return err
+}
+
+// Kayıtları yeniden eskiye sayfalı olarak döner, sayfalama bilgisi güncellenir
+func (r *AuditRepository) List(ctx context.Context, f AuditFilter, p *query.Pagination) ([]model.AuditLog, error) {
+	var entries []model.AuditLog
+	q := r.db.NewSelect().Model(&entries)
+	if f.Entity != "" {
+		q = q.Where("entity = ?", f.Entity)
+	}
+	if f.EntityID != 0 {
+		q = q.Where("entity_id = ?", f.EntityID)
+	}
+	if f.ActorID != 0 {
+		q = q.Where("actor_id = ?", f.ActorID)
+	}
+	if !f.From.IsZero() {
+		q = q.Where("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		q = q.Where("created_at < ?", f.To)
+	}
+
+	if err := query.UpdatePaginationInfo(ctx, q, p); err != nil {
+		return nil, err
+	}
+
+	err := query.ApplyPagination(q, *p).
+		Order("created_at DESC", "id DESC").
+		Scan(ctx)
+	return entries, err
+}
+
+// before'dan eski kayıtları siler. Tablo tetikleyicisi silmeye sadece bu transaction içinde izin verir.
+func (r *AuditRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "SET LOCAL audit.purge = 'on'"); err != nil {
+		return 0, err
+	}
+	res, err := tx.NewDelete().
+		Model((*model.AuditLog)(nil)).
+		Where("created_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
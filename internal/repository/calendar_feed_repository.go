@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"shift-scheduling-v2/internal/model"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type CalendarFeedRepository struct {
+	db *bun.DB
+}
+
+func NewCalendarFeedRepository(db *bun.DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+func (r *CalendarFeedRepository) Create(ctx context.Context, token *model.CalendarFeedToken) error {
+	_, err := r.db.NewInsert().Model(token).Returning("*").Exec(ctx)
+	return err
+}
+
+func (r *CalendarFeedRepository) GetByID(ctx context.Context, id int64) (*model.CalendarFeedToken, error) {
+	var token model.CalendarFeedToken
+	err := r.db.NewSelect().Model(&token).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// İptal edilmemiş anahtarı özetiyle bulur
+func (r *CalendarFeedRepository) GetActiveByHash(ctx context.Context, kind string, hash string) (*model.CalendarFeedToken, error) {
+	var token model.CalendarFeedToken
+	err := r.db.NewSelect().Model(&token).
+		Where("kind = ? AND token_hash = ? AND revoked_at IS NULL", kind, hash).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Doktorun ya da lokasyonun anahtarları, iptal edilenler dahil
+func (r *CalendarFeedRepository) List(ctx context.Context, kind string, ownerID int64) ([]model.CalendarFeedToken, error) {
+	var tokens []model.CalendarFeedToken
+	q := r.db.NewSelect().Model(&tokens).Where("kind = ?", kind)
+	if kind == model.CalendarFeedDoctor {
+		q = q.Where("doctor_id = ?", ownerID)
+	} else {
+		q = q.Where("location_id = ?", ownerID)
+	}
+	err := q.Order("created_at DESC", "id DESC").Scan(ctx)
+	return tokens, err
+}
+
+func (r *CalendarFeedRepository) Revoke(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*model.CalendarFeedToken)(nil)).
+		Set("revoked_at = ?", at).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Exec(ctx)
+	return err
+}
+
+func (r *CalendarFeedRepository) Touch(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*model.CalendarFeedToken)(nil)).
+		Set("last_used_at = ?", at).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+// Aralıktaki nöbetlerden since sonrasında takvimden çıkanlar, yeniden eskiye
+func (r *CalendarFeedRepository) GetRemovals(ctx context.Context, kind string, ownerID int64, start time.Time, end time.Time, since time.Time) ([]model.ShiftRemoval, error) {
+	var removals []model.ShiftRemoval
+	q := r.db.NewSelect().Model(&removals)
+	if kind == model.CalendarFeedDoctor {
+		q = q.Where("doctor_id = ?", ownerID)
+	} else {
+		q = q.Where("location_id = ?", ownerID)
+	}
+	err := q.
+		Where("shift_date >= ? AND shift_date < ?", start, end).
+		Where("removed_at >= ?", since).
+		Order("removed_at DESC", "id DESC").
+		Scan(ctx)
+	return removals, err
+}
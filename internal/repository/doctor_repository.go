@@ -66,16 +66,32 @@ func (r *DoctorRepository) GetByShiftID(ctx context.Context, shiftID int64) (*mo
 	return &doctor, err
 }
 
+// Lokasyona bugün itibarıyla üye olan doktorlar
 func (r *DoctorRepository) GetByLocation(ctx context.Context, locationID int64) ([]model.Doctor, error) {
 	var doctors []model.Doctor
 	err := r.db.NewSelect().Model(&doctors).
-		Join("INNER JOIN doctor_shift_locations dsl ON dsl.doctor_id = doctor.id").
+		Join("INNER JOIN doctor_shift_locations dsl ON dsl.doctor_id = doctor.id AND dsl.deleted_at IS NULL").
 		Where("dsl.location_id = ?", locationID).
+		Where("dsl.effective_from IS NULL OR dsl.effective_from <= CURRENT_DATE").
+		Where("dsl.effective_to IS NULL OR dsl.effective_to >= CURRENT_DATE").
 		Relation("User").
 		Scan(ctx)
 	return doctors, err
 }
 
+// Lokasyonda [start, end) aralığının herhangi bir gününde geçerli olan üyelikler, doktor bilgileriyle
+func (r *DoctorRepository) GetMemberships(ctx context.Context, locationID int64, start time.Time, end time.Time) ([]model.DoctorShiftLocation, error) {
+	var memberships []model.DoctorShiftLocation
+	err := r.db.NewSelect().Model(&memberships).
+		Relation("Doctor.User").
+		Where("doctor_shift_location.location_id = ?", locationID).
+		Where("doctor_shift_location.effective_from IS NULL OR doctor_shift_location.effective_from < ?", end).
+		Where("doctor_shift_location.effective_to IS NULL OR doctor_shift_location.effective_to >= ?", start).
+		Order("doctor_shift_location.doctor_id ASC").
+		Scan(ctx)
+	return memberships, err
+}
+
 func (r *DoctorRepository) GetHolidaysByDoctor(ctx context.Context, doctorID int64) ([]model.Holiday, error) {
 	var holidays []model.Holiday
 	err := r.db.NewSelect().Model(&holidays).
@@ -99,6 +115,19 @@ func (r *DoctorRepository) GetHolidaysByLocation(ctx context.Context, locationID
 	return holidays, err
 }
 
+func (r *DoctorRepository) GetHolidaysByDoctorIDs(ctx context.Context, doctorIDs []int64, start time.Time, end time.Time) ([]model.Holiday, error) {
+	var holidays []model.Holiday
+	if len(doctorIDs) == 0 {
+		return holidays, nil
+	}
+
+	err := r.db.NewSelect().Model(&holidays).
+		Where("doctor_id IN (?)", bun.In(doctorIDs)).
+		Where("holiday_date >= ? AND holiday_date < ?", start, end).
+		Scan(ctx)
+	return holidays, err
+}
+
 func (r *DoctorRepository) List(ctx context.Context, relations ...string) ([]model.Doctor, int, error) {
 	var doctors []model.Doctor
 	query := r.db.NewSelect().Model(&doctors)
@@ -136,3 +165,107 @@ func (r *DoctorRepository) Delete(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+func (r *DoctorRepository) GetByUserID(ctx context.Context, userID int64) (*model.Doctor, error) {
+	var doctor model.Doctor
+	err := r.db.NewSelect().Model(&doctor).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &doctor, nil
+}
+
+// Doktorun bugün itibarıyla üye olduğu lokasyonlar
+func (r *DoctorRepository) GetLocationIDs(ctx context.Context, doctorID int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.NewSelect().
+		Model((*model.DoctorShiftLocation)(nil)).
+		Column("location_id").
+		Where("doctor_id = ?", doctorID).
+		Where("effective_from IS NULL OR effective_from <= CURRENT_DATE").
+		Where("effective_to IS NULL OR effective_to >= CURRENT_DATE").
+		Scan(ctx, &ids)
+	return ids, err
+}
+
+func (r *DoctorRepository) GetPreference(ctx context.Context, doctorID int64, year int, month int) (*model.DoctorPreference, error) {
+	var preference model.DoctorPreference
+	err := r.db.NewSelect().Model(&preference).
+		Where("doctor_id = ? AND year = ? AND month = ?", doctorID, year, month).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+func (r *DoctorRepository) GetPreferencesByDoctorIDs(ctx context.Context, doctorIDs []int64, year int, month int) ([]model.DoctorPreference, error) {
+	var preferences []model.DoctorPreference
+	if len(doctorIDs) == 0 {
+		return preferences, nil
+	}
+
+	err := r.db.NewSelect().Model(&preferences).
+		Where("doctor_id IN (?)", bun.In(doctorIDs)).
+		Where("year = ? AND month = ?", year, month).
+		Scan(ctx)
+	return preferences, err
+}
+
+func (r *DoctorRepository) UpsertPreference(ctx context.Context, preference *model.DoctorPreference) error {
+	_, err := r.db.NewInsert().Model(preference).
+		On("CONFLICT (doctor_id, year, month) DO UPDATE").
+		Set("preferred_weekdays = EXCLUDED.preferred_weekdays").
+		Set("avoid_dates = EXCLUDED.avoid_dates").
+		Set("preferred_templates = EXCLUDED.preferred_templates").
+		Set("weight = EXCLUDED.weight").
+		Set("deleted_at = NULL").
+		Returning("*").
+		Exec(ctx)
+	return err
+}
+
+func (r *DoctorRepository) DeletePreference(ctx context.Context, doctorID int64, year int, month int) error {
+	_, err := r.db.NewDelete().Model((*model.DoctorPreference)(nil)).
+		Where("doctor_id = ? AND year = ? AND month = ?", doctorID, year, month).
+		ForceDelete().
+		Exec(ctx)
+	return err
+}
+
+// Doktorları kullanıcı bilgileriyle birlikte döner
+func (r *DoctorRepository) GetByIDs(ctx context.Context, ids []int64) ([]model.Doctor, error) {
+	var doctors []model.Doctor
+	err := r.db.NewSelect().Model(&doctors).
+		Relation("User").
+		Where("doctor.id IN (?)", bun.In(ids)).
+		Scan(ctx)
+	return doctors, err
+}
+
+func (r *DoctorRepository) GetByUserIDs(ctx context.Context, userIDs []int64) ([]model.Doctor, error) {
+	var doctors []model.Doctor
+	if len(userIDs) == 0 {
+		return doctors, nil
+	}
+
+	err := r.db.NewSelect().Model(&doctors).
+		Where("user_id IN (?)", bun.In(userIDs)).
+		Scan(ctx)
+	return doctors, err
+}
+
+// Doktorların tüm lokasyonlardaki üyelikleri
+func (r *DoctorRepository) GetMembershipsByDoctorIDs(ctx context.Context, doctorIDs []int64) ([]model.DoctorShiftLocation, error) {
+	var memberships []model.DoctorShiftLocation
+	if len(doctorIDs) == 0 {
+		return memberships, nil
+	}
+
+	err := r.db.NewSelect().Model(&memberships).
+		Where("doctor_id IN (?)", bun.In(doctorIDs)).
+		Scan(ctx)
+	return memberships, err
+}
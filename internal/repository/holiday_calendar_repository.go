@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"shift-scheduling-v2/internal/model"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type HolidayCalendarRepository struct {
+	db *bun.DB
+}
+
+func NewHolidayCalendarRepository(db *bun.DB) *HolidayCalendarRepository {
+	return &HolidayCalendarRepository{db: db}
+}
+
+func (r *HolidayCalendarRepository) Create(ctx context.Context, calendar *model.HolidayCalendar) error {
+	_, err := r.db.NewInsert().Model(calendar).Exec(ctx)
+	return err
+}
+
+func (r *HolidayCalendarRepository) List(ctx context.Context) ([]model.HolidayCalendar, error) {
+	var calendars []model.HolidayCalendar
+	err := r.db.NewSelect().Model(&calendars).Order("name ASC").Scan(ctx)
+	return calendars, err
+}
+
+func (r *HolidayCalendarRepository) GetByID(ctx context.Context, id int64) (*model.HolidayCalendar, error) {
+	var calendar model.HolidayCalendar
+	err := r.db.NewSelect().
+		Model(&calendar).
+		Relation("Entries", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("holiday_date ASC")
+		}).
+		Where("holiday_calendar.id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+func (r *HolidayCalendarRepository) Update(ctx context.Context, calendar *model.HolidayCalendar) error {
+	_, err := r.db.NewUpdate().Model(calendar).Column("name", "description").WherePK().Exec(ctx)
+	return err
+}
+
+// Takvimi siler ve lokasyon bağlantılarını kaldırır
+func (r *HolidayCalendarRepository) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.NewDelete().Model((*model.LocationHolidayCalendar)(nil)).Where("calendar_id = ?", id).ForceDelete().Exec(ctx); err != nil {
+		return err
+	}
+	if _, err = tx.NewDelete().Model((*model.HolidayCalendar)(nil)).Where("id = ?", id).Exec(ctx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Tatil günlerini ekler, aynı tarihte kayıt varsa adını ve UID'ini günceller
+func (r *HolidayCalendarRepository) UpsertEntries(ctx context.Context, entries []model.PublicHoliday) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	_, err := r.db.NewInsert().Model(&entries).
+		On("CONFLICT (calendar_id, holiday_date) DO UPDATE").
+		Set("uid = EXCLUDED.uid").
+		Set("name = EXCLUDED.name").
+		Set("recurring = EXCLUDED.recurring").
+		Set("deleted_at = NULL").
+		Returning("*").
+		Exec(ctx)
+	return err
+}
+
+func (r *HolidayCalendarRepository) DeleteEntry(ctx context.Context, calendarID int64, entryID int64) error {
+	_, err := r.db.NewDelete().Model((*model.PublicHoliday)(nil)).
+		Where("id = ? AND calendar_id = ?", entryID, calendarID).
+		ForceDelete().
+		Exec(ctx)
+	return err
+}
+
+func (r *HolidayCalendarRepository) GetLocationCalendars(ctx context.Context, locationID int64) ([]model.LocationHolidayCalendar, error) {
+	var links []model.LocationHolidayCalendar
+	err := r.db.NewSelect().
+		Model(&links).
+		Relation("Calendar").
+		Where("location_holiday_calendar.location_id = ?", locationID).
+		Order("location_holiday_calendar.calendar_id ASC").
+		Scan(ctx)
+	return links, err
+}
+
+func (r *HolidayCalendarRepository) UpsertLocationCalendar(ctx context.Context, link *model.LocationHolidayCalendar) error {
+	_, err := r.db.NewInsert().Model(link).
+		On("CONFLICT (location_id, calendar_id) DO UPDATE").
+		Set("doctors_required = EXCLUDED.doctors_required").
+		Set("weight = EXCLUDED.weight").
+		Set("deleted_at = NULL").
+		Returning("*").
+		Exec(ctx)
+	return err
+}
+
+func (r *HolidayCalendarRepository) DeleteLocationCalendar(ctx context.Context, locationID int64, calendarID int64) error {
+	_, err := r.db.NewDelete().Model((*model.LocationHolidayCalendar)(nil)).
+		Where("location_id = ? AND calendar_id = ?", locationID, calendarID).
+		ForceDelete().
+		Exec(ctx)
+	return err
+}
+
+// Lokasyonun takvimlerinde aralığa düşen tatiller ile her yıl tekrarlanan tatiller.
+// Tekrarlanan kayıtların aralığa denk gelen yılları çağıran tarafından hesaplanır.
+func (r *HolidayCalendarRepository) GetEntriesForLocation(ctx context.Context, locationID int64, start time.Time, end time.Time) ([]model.PublicHoliday, error) {
+	var entries []model.PublicHoliday
+	err := r.db.NewSelect().
+		Model(&entries).
+		Join("INNER JOIN location_holiday_calendars lhc ON lhc.calendar_id = public_holiday.calendar_id AND lhc.deleted_at IS NULL").
+		Join("INNER JOIN holiday_calendars hc ON hc.id = public_holiday.calendar_id AND hc.deleted_at IS NULL").
+		Where("lhc.location_id = ?", locationID).
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("public_holiday.holiday_date >= ? AND public_holiday.holiday_date < ?", start, end).
+				WhereOr("public_holiday.recurring AND public_holiday.holiday_date < ?", end)
+		}).
+		Order("public_holiday.holiday_date ASC").
+		Scan(ctx)
+	return entries, err
+}
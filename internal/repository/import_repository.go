@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"shift-scheduling-v2/internal/model"
+	"slices"
+
+	"github.com/uptrace/bun"
+)
+
+// Toplu içe aktarılacak kayıtlar. Yeni kullanıcı ve doktorlar eklendikçe ID'leri
+// aynı işaretçiyi paylaşan üyelik ve tatil kayıtlarına taşınır.
+type ImportBatch struct {
+	Doctors     []ImportDoctor
+	Memberships []ImportMembership
+	Holidays    []ImportHoliday
+}
+
+// User.ID doluysa kullanıcı zaten vardır, yalnızca doktor kaydı eklenir
+type ImportDoctor struct {
+	User   *model.User
+	Doctor *model.Doctor
+}
+
+type ImportMembership struct {
+	Doctor *model.Doctor
+	Member model.DoctorShiftLocation
+}
+
+type ImportHoliday struct {
+	Doctor  *model.Doctor
+	Holiday model.Holiday
+}
+
+type ImportRepository struct {
+	db *bun.DB
+}
+
+func NewImportRepository(db *bun.DB) *ImportRepository {
+	return &ImportRepository{db: db}
+}
+
+// Tüm kayıtları tek işlemde ekler; herhangi biri başarısız olursa hiçbiri kalıcı olmaz
+func (r *ImportRepository) Apply(ctx context.Context, batch *ImportBatch) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, d := range batch.Doctors {
+		if d.User.ID == 0 {
+			if _, err = tx.NewInsert().Model(d.User).Returning("*").Exec(ctx); err != nil {
+				return err
+			}
+		}
+		d.Doctor.UserID = d.User.ID
+		if _, err = tx.NewInsert().Model(d.Doctor).Returning("*").Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	if len(batch.Memberships) > 0 {
+		members := make([]model.DoctorShiftLocation, len(batch.Memberships))
+		for i, m := range batch.Memberships {
+			members[i] = m.Member
+			members[i].DoctorID = m.Doctor.ID
+		}
+		if _, err = tx.NewInsert().Model(&members).Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	var locationIDs []int64
+	if len(batch.Holidays) > 0 {
+		holidays := make([]model.Holiday, len(batch.Holidays))
+		for i, h := range batch.Holidays {
+			holidays[i] = h.Holiday
+			holidays[i].DoctorID = h.Doctor.ID
+			if !slices.Contains(locationIDs, h.Holiday.LocationID) {
+				locationIDs = append(locationIDs, h.Holiday.LocationID)
+			}
+		}
+		if _, err = tx.NewInsert().Model(&holidays).Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	// Eklenen izinler lokasyonların izin günü analizini değiştirir
+	if len(locationIDs) > 0 {
+		invalidateAnalytics(ctx, locationIDs...)
+	}
+	return nil
+}
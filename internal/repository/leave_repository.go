@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"shift-scheduling-v2/internal/model"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type LeaveRepository struct {
+	db *bun.DB
+}
+
+func NewLeaveRepository(db *bun.DB) *LeaveRepository {
+	return &LeaveRepository{db: db}
+}
+
+func (r *LeaveRepository) Create(ctx context.Context, leave *model.LeaveRequest) error {
+	_, err := r.db.NewInsert().Model(leave).Exec(ctx)
+	return err
+}
+
+func (r *LeaveRepository) GetByID(ctx context.Context, id int64) (*model.LeaveRequest, error) {
+	var leave model.LeaveRequest
+	err := r.db.NewSelect().
+		Model(&leave).
+		Relation("Doctor.User").
+		Relation("Location").
+		Where("leave_request.id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &leave, nil
+}
+
+func (r *LeaveRepository) ListByDoctor(ctx context.Context, doctorID int64) ([]model.LeaveRequest, error) {
+	var leaves []model.LeaveRequest
+	err := r.db.NewSelect().
+		Model(&leaves).
+		Relation("Doctor.User").
+		Relation("Location").
+		Where("leave_request.doctor_id = ?", doctorID).
+		Order("leave_request.start_date DESC").
+		Scan(ctx)
+	return leaves, err
+}
+
+// Boş bırakılan filtreler uygulanmaz
+func (r *LeaveRepository) List(ctx context.Context, status string, locationID int64) ([]model.LeaveRequest, error) {
+	var leaves []model.LeaveRequest
+	query := r.db.NewSelect().
+		Model(&leaves).
+		Relation("Doctor.User").
+		Relation("Location")
+
+	if status != "" {
+		query = query.Where("leave_request.status = ?", status)
+	}
+	if locationID != 0 {
+		query = query.Where("leave_request.location_id = ?", locationID)
+	}
+
+	err := query.Order("leave_request.start_date ASC", "leave_request.id ASC").Scan(ctx)
+	return leaves, err
+}
+
+// Doktorun verilen aralıkla çakışan bekleyen ya da onaylı talebi olup olmadığını döner
+func (r *LeaveRepository) HasOverlap(ctx context.Context, doctorID int64, start time.Time, end time.Time) (bool, error) {
+	return r.db.NewSelect().
+		Model((*model.LeaveRequest)(nil)).
+		Where("doctor_id = ?", doctorID).
+		Where("status IN (?)", bun.In([]string{model.LeaveStatusPending, model.LeaveStatusApproved})).
+		Where("start_date <= ? AND end_date >= ?", end, start).
+		Exists(ctx)
+}
+
+// Talebi yalnızca hâlâ from durumundaysa günceller, aksi halde sql.ErrNoRows döner
+func (r *LeaveRepository) UpdateStatus(ctx context.Context, leave *model.LeaveRequest, from string) error {
+	return updateLeaveStatus(ctx, r.db, leave, from)
+}
+
+// Talebi onaylar ve her gün için Holiday kaydı oluşturur. Doktorun zaten tatil kaydı olan günler atlanır.
+func (r *LeaveRepository) Approve(ctx context.Context, leave *model.LeaveRequest) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = updateLeaveStatus(ctx, tx, leave, model.LeaveStatusPending); err != nil {
+		return err
+	}
+
+	var existing []time.Time
+	err = tx.NewSelect().
+		Model((*model.Holiday)(nil)).
+		Column("holiday_date").
+		Where("doctor_id = ?", leave.DoctorID).
+		Where("holiday_date >= ? AND holiday_date <= ?", leave.StartDate, leave.EndDate).
+		Scan(ctx, &existing)
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, day := range existing {
+		taken[day.Format("2006-01-02")] = true
+	}
+
+	var holidays []model.Holiday
+	for day := leave.StartDate; !day.After(leave.EndDate); day = day.AddDate(0, 0, 1) {
+		if taken[day.Format("2006-01-02")] {
+			continue
+		}
+		holidays = append(holidays, model.Holiday{
+			DoctorID:       leave.DoctorID,
+			LocationID:     leave.LocationID,
+			HolidayDate:    day,
+			LeaveRequestID: leave.ID,
+			LeaveType:      leave.LeaveType,
+		})
+	}
+
+	if len(holidays) > 0 {
+		if _, err = tx.NewInsert().Model(&holidays).Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	invalidateAnalytics(ctx, leave.LocationID)
+	return nil
+}
+
+func updateLeaveStatus(ctx context.Context, db bun.IDB, leave *model.LeaveRequest, from string) error {
+	res, err := db.NewUpdate().
+		Model(leave).
+		Column("status", "reviewed_by", "reviewed_at", "review_comment", "conflicting_shift_ids").
+		Where("id = ? AND status = ?", leave.ID, from).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Doktor, izin türü ve yıl bazında kullanılan izin günü
+type LeaveUsage struct {
+	DoctorID  int64   `bun:"doctor_id"`
+	LeaveType string  `bun:"leave_type"`
+	Year      int     `bun:"year"`
+	Days      float64 `bun:"days"`
+}
+
+func (r *LeaveRepository) GetEntitlements(ctx context.Context, doctorIDs []int64) ([]model.LeaveEntitlement, error) {
+	var entitlements []model.LeaveEntitlement
+	if len(doctorIDs) == 0 {
+		return entitlements, nil
+	}
+
+	err := r.db.NewSelect().
+		Model(&entitlements).
+		Where("doctor_id IN (?)", bun.In(doctorIDs)).
+		Order("doctor_id ASC", "leave_type ASC").
+		Scan(ctx)
+	return entitlements, err
+}
+
+func (r *LeaveRepository) UpsertEntitlement(ctx context.Context, entitlement *model.LeaveEntitlement) error {
+	_, err := r.db.NewInsert().Model(entitlement).
+		On("CONFLICT (doctor_id, leave_type) DO UPDATE").
+		Set("annual_days = EXCLUDED.annual_days").
+		Set("accrual = EXCLUDED.accrual").
+		Set("carry_over_max = EXCLUDED.carry_over_max").
+		Set("start_year = EXCLUDED.start_year").
+		Set("deleted_at = NULL").
+		Returning("*").
+		Exec(ctx)
+	return err
+}
+
+func (r *LeaveRepository) DeleteEntitlement(ctx context.Context, doctorID int64, leaveType string) error {
+	_, err := r.db.NewDelete().Model((*model.LeaveEntitlement)(nil)).
+		Where("doctor_id = ? AND leave_type = ?", doctorID, leaveType).
+		ForceDelete().
+		Exec(ctx)
+	return err
+}
+
+// Onaylı tatil kayıtlarından yıllara göre kullanılan izin günleri.
+// İzin türü olmayan (elle girilmiş) tatiller yıllık izinden düşülür.
+func (r *LeaveRepository) GetUsage(ctx context.Context, doctorIDs []int64, toYear int) ([]LeaveUsage, error) {
+	var usage []LeaveUsage
+	if len(doctorIDs) == 0 {
+		return usage, nil
+	}
+
+	err := r.db.NewSelect().
+		Model((*model.Holiday)(nil)).
+		ColumnExpr("doctor_id").
+		ColumnExpr("COALESCE(leave_type, ?) AS leave_type", model.LeaveTypeAnnual).
+		ColumnExpr("EXTRACT(YEAR FROM holiday_date)::int AS year").
+		ColumnExpr("COUNT(*) AS days").
+		Where("doctor_id IN (?)", bun.In(doctorIDs)).
+		Where("EXTRACT(YEAR FROM holiday_date) <= ?", toYear).
+		GroupExpr("1, 2, 3").
+		Scan(ctx, &usage)
+	return usage, err
+}
+
+// Doktorların bekleyen izin talepleri
+func (r *LeaveRepository) GetPending(ctx context.Context, doctorIDs []int64) ([]model.LeaveRequest, error) {
+	var leaves []model.LeaveRequest
+	if len(doctorIDs) == 0 {
+		return leaves, nil
+	}
+
+	err := r.db.NewSelect().
+		Model(&leaves).
+		Where("doctor_id IN (?)", bun.In(doctorIDs)).
+		Where("status = ?", model.LeaveStatusPending).
+		Scan(ctx)
+	return leaves, err
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"shift-scheduling-v2/internal/model"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type LocationRepository struct {
+	db *bun.DB
+}
+
+func NewLocationRepository(db *bun.DB) *LocationRepository {
+	return &LocationRepository{db: db}
+}
+
+func (r *LocationRepository) Create(ctx context.Context, location *model.ShiftLocation) error {
+	_, err := r.db.NewInsert().Model(location).Exec(ctx)
+	return err
+}
+
+func (r *LocationRepository) List(ctx context.Context, includeArchived bool) ([]model.ShiftLocation, error) {
+	var locations []model.ShiftLocation
+	query := r.db.NewSelect().Model(&locations)
+	if !includeArchived {
+		query = query.Where("archived_at IS NULL")
+	}
+
+	err := query.Order("name ASC").Scan(ctx)
+	return locations, err
+}
+
+func (r *LocationRepository) GetByID(ctx context.Context, id int64) (*model.ShiftLocation, error) {
+	var location model.ShiftLocation
+	err := r.db.NewSelect().Model(&location).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+func (r *LocationRepository) Update(ctx context.Context, location *model.ShiftLocation) error {
+	_, err := r.db.NewUpdate().
+		Model(location).
+		Column("name", "description").
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+// Lokasyonu arşivler, archivedAt nil ise arşivden çıkarır
+func (r *LocationRepository) SetArchived(ctx context.Context, id int64, archivedAt *time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*model.ShiftLocation)(nil)).
+		Set("archived_at = ?", archivedAt).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+// Lokasyonun üyelikleri. day verilirse yalnızca o gün geçerli olanlar döner.
+func (r *LocationRepository) ListMembers(ctx context.Context, locationID int64, day *time.Time) ([]model.DoctorShiftLocation, error) {
+	var members []model.DoctorShiftLocation
+	query := r.db.NewSelect().
+		Model(&members).
+		Relation("Doctor.User").
+		Where("doctor_shift_location.location_id = ?", locationID)
+
+	if day != nil {
+		query = query.
+			Where("doctor_shift_location.effective_from IS NULL OR doctor_shift_location.effective_from <= ?", *day).
+			Where("doctor_shift_location.effective_to IS NULL OR doctor_shift_location.effective_to >= ?", *day)
+	}
+
+	err := query.Order("doctor_shift_location.doctor_id ASC", "doctor_shift_location.effective_from ASC").Scan(ctx)
+	return members, err
+}
+
+func (r *LocationRepository) GetMember(ctx context.Context, locationID int64, id int64) (*model.DoctorShiftLocation, error) {
+	var member model.DoctorShiftLocation
+	err := r.db.NewSelect().
+		Model(&member).
+		Relation("Doctor.User").
+		Where("doctor_shift_location.id = ? AND doctor_shift_location.location_id = ?", id, locationID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *LocationRepository) CreateMember(ctx context.Context, member *model.DoctorShiftLocation) error {
+	_, err := r.db.NewInsert().Model(member).Exec(ctx)
+	return err
+}
+
+func (r *LocationRepository) UpdateMember(ctx context.Context, member *model.DoctorShiftLocation) error {
+	_, err := r.db.NewUpdate().Model(member).Column("effective_from", "effective_to").WherePK().Exec(ctx)
+	return err
+}
+
+func (r *LocationRepository) DeleteMember(ctx context.Context, id int64) error {
+	_, err := r.db.NewDelete().Model((*model.DoctorShiftLocation)(nil)).Where("id = ?", id).Exec(ctx)
+	return err
+}
+
+// Doktorun lokasyonda verilen dönemle çakışan başka bir üyeliği olup olmadığını döner
+func (r *LocationRepository) HasOverlappingMember(ctx context.Context, member model.DoctorShiftLocation) (bool, error) {
+	query := r.db.NewSelect().
+		Model((*model.DoctorShiftLocation)(nil)).
+		Where("doctor_id = ? AND location_id = ?", member.DoctorID, member.LocationID).
+		Where("id <> ?", member.ID)
+
+	if member.EffectiveTo != nil {
+		query = query.Where("effective_from IS NULL OR effective_from <= ?", *member.EffectiveTo)
+	}
+	if member.EffectiveFrom != nil {
+		query = query.Where("effective_to IS NULL OR effective_to >= ?", *member.EffectiveFrom)
+	}
+	return query.Exists(ctx)
+}
+
+// Lokasyon politikasının son sürümü
+func (r *LocationRepository) GetPolicy(ctx context.Context, locationID int64) (*model.LocationPolicy, error) {
+	var p model.LocationPolicy
+	err := r.db.NewSelect().
+		Model(&p).
+		Where("location_id = ?", locationID).
+		Order("version DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *LocationRepository) GetPolicyVersion(ctx context.Context, locationID int64, version int) (*model.LocationPolicy, error) {
+	var p model.LocationPolicy
+	err := r.db.NewSelect().
+		Model(&p).
+		Where("location_id = ? AND version = ?", locationID, version).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *LocationRepository) ListPolicyVersions(ctx context.Context, locationID int64) ([]model.LocationPolicy, error) {
+	var policies []model.LocationPolicy
+	err := r.db.NewSelect().
+		Model(&policies).
+		Where("location_id = ?", locationID).
+		Order("version DESC").
+		Scan(ctx)
+	return policies, err
+}
+
+// Politikayı lokasyonun bir sonraki sürümü olarak kaydeder. Lokasyon satırı kilitlenerek
+// eşzamanlı kayıtların aynı sürüm numarasını alması engellenir.
+func (r *LocationRepository) CreatePolicy(ctx context.Context, p *model.LocationPolicy) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.NewSelect().
+		Model((*model.ShiftLocation)(nil)).
+		Column("id").
+		Where("id = ?", p.LocationID).
+		For("UPDATE").
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.NewSelect().
+		Model((*model.LocationPolicy)(nil)).
+		ColumnExpr("COALESCE(MAX(version), 0) + 1").
+		Where("location_id = ?", p.LocationID).
+		Scan(ctx, &p.Version)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.NewInsert().Model(p).Returning("*").Exec(ctx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
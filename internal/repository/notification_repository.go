@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/pkg/query"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type NotificationRepository struct {
+	db *bun.DB
+}
+
+func NewNotificationRepository(db *bun.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, notification *model.Notification) error {
+	_, err := r.db.NewInsert().Model(notification).Exec(ctx)
+	return err
+}
+
+// Doktorun bildirimlerini yeniden eskiye sayfalı olarak döner, sayfalama bilgisi güncellenir
+func (r *NotificationRepository) ListByDoctor(ctx context.Context, doctorID int64, unreadOnly bool, p *query.Pagination) ([]model.Notification, error) {
+	var notifications []model.Notification
+	q := r.db.NewSelect().
+		Model(&notifications).
+		Where("doctor_id = ?", doctorID)
+	if unreadOnly {
+		q = q.Where("is_read = FALSE")
+	}
+
+	if err := query.UpdatePaginationInfo(ctx, q, p); err != nil {
+		return nil, err
+	}
+
+	err := query.ApplyPagination(q, *p).
+		Order("created_at DESC", "id DESC").
+		Scan(ctx)
+	return notifications, err
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, doctorID int64) (int, error) {
+	return r.db.NewSelect().
+		Model((*model.Notification)(nil)).
+		Where("doctor_id = ? AND is_read = FALSE", doctorID).
+		Count(ctx)
+}
+
+// Bildirim doktora ait değilse false döner
+func (r *NotificationRepository) MarkRead(ctx context.Context, doctorID int64, id int64) (bool, error) {
+	res, err := r.db.NewUpdate().
+		Model((*model.Notification)(nil)).
+		Set("is_read = TRUE").
+		Set("read_at = COALESCE(read_at, ?)", time.Now()).
+		Where("id = ? AND doctor_id = ?", id, doctorID).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+// Okunmamış bildirimleri okundu yapar ve etkilenen kayıt sayısını döner
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, doctorID int64) (int64, error) {
+	res, err := r.db.NewUpdate().
+		Model((*model.Notification)(nil)).
+		Set("is_read = TRUE").
+		Set("read_at = ?", time.Now()).
+		Where("doctor_id = ? AND is_read = FALSE", doctorID).
+		Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"shift-scheduling-v2/internal/model"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Geçiş sırasında ayın durumu başka bir istekle değişmiş
+var ErrScheduleStateChanged = errors.New("schedule state changed")
+
+type ScheduleRepository struct {
+	db *bun.DB
+}
+
+func NewScheduleRepository(db *bun.DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+// Ayın durumunu t.FromState'den t.ToState'e geçirir ve geçişi kaydeder. snapshot true ise
+// ayın nöbetleri yeni sürüm olarak saklanır ve t.Version bu sürüme ayarlanır.
+func (r *ScheduleRepository) Transition(ctx context.Context, t *model.ScheduleTransition, snapshot bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockStatus(ctx, tx, t)
+	if err != nil {
+		return err
+	}
+
+	t.Version = status.Version
+	if snapshot {
+		shifts, err := monthShifts(ctx, tx, t.LocationID, t.Year, t.Month)
+		if err != nil {
+			return err
+		}
+		if err = createSnapshot(ctx, tx, t, shifts); err != nil {
+			return err
+		}
+	}
+
+	if err = updateStatus(ctx, tx, status, t); err != nil {
+		return err
+	}
+	if _, err = tx.NewInsert().Model(t).Exec(ctx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Ayın nöbetlerini t.SourceVersion sürümündeki haliyle değiştirir. Ay yayındaysa geri alınan
+// hal yeni bir sürüm olarak yeniden yayınlanır.
+func (r *ScheduleRepository) Rollback(ctx context.Context, t *model.ScheduleTransition) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockStatus(ctx, tx, t)
+	if err != nil {
+		return err
+	}
+
+	var source model.ScheduleSnapshot
+	err = tx.NewSelect().
+		Model(&source).
+		Where("location_id = ? AND year = ? AND month = ? AND version = ?", t.LocationID, t.Year, t.Month, t.SourceVersion).
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	start := time.Date(t.Year, time.Month(t.Month), 1, 0, 0, 0, 0, time.UTC)
+	_, err = tx.NewDelete().
+		Model((*model.Shift)(nil)).
+		Where("location_id = ? AND shift_date >= ? AND shift_date < ?", t.LocationID, start, start.AddDate(0, 1, 0)).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(source.Shifts) > 0 {
+		shifts := make([]model.Shift, len(source.Shifts))
+		for i, ds := range source.Shifts {
+			shifts[i] = ds.ToShift(t.LocationID)
+		}
+		if _, err = tx.NewInsert().Model(&shifts).Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	t.Version = status.Version
+	if t.ToState != model.ScheduleStateDraft {
+		if err = createSnapshot(ctx, tx, t, source.Shifts); err != nil {
+			return err
+		}
+	}
+
+	if err = updateStatus(ctx, tx, status, t); err != nil {
+		return err
+	}
+	if _, err = tx.NewInsert().Model(t).Exec(ctx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	invalidateAnalytics(ctx, t.LocationID)
+	return nil
+}
+
+// Durum değiştirmeyen kayıtlar (kilitli aydaki değişiklikler) için
+func (r *ScheduleRepository) RecordTransition(ctx context.Context, t *model.ScheduleTransition) error {
+	_, err := r.db.NewInsert().Model(t).Exec(ctx)
+	return err
+}
+
+func (r *ScheduleRepository) ListTransitions(ctx context.Context, locationID int64, year int, month int) ([]model.ScheduleTransition, error) {
+	var transitions []model.ScheduleTransition
+	err := r.db.NewSelect().
+		Model(&transitions).
+		Where("location_id = ? AND year = ? AND month = ?", locationID, year, month).
+		Order("created_at ASC", "id ASC").
+		Scan(ctx)
+	return transitions, err
+}
+
+// Ayın sürümleri, nöbet listeleri olmadan
+func (r *ScheduleRepository) ListSnapshots(ctx context.Context, locationID int64, year int, month int) ([]model.ScheduleSnapshot, error) {
+	var snapshots []model.ScheduleSnapshot
+	err := r.db.NewSelect().
+		Model(&snapshots).
+		ExcludeColumn("shifts").
+		Where("location_id = ? AND year = ? AND month = ?", locationID, year, month).
+		Order("version DESC").
+		Scan(ctx)
+	return snapshots, err
+}
+
+func (r *ScheduleRepository) GetSnapshot(ctx context.Context, locationID int64, year int, month int, version int) (*model.ScheduleSnapshot, error) {
+	var snapshot model.ScheduleSnapshot
+	err := r.db.NewSelect().
+		Model(&snapshot).
+		Where("location_id = ? AND year = ? AND month = ? AND version = ?", locationID, year, month, version).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// Ayın durum kaydını kilitler, yoksa taslak olarak oluşturur. Kayıt t.FromState
+// durumunda değilse ErrScheduleStateChanged döner.
+func lockStatus(ctx context.Context, tx bun.Tx, t *model.ScheduleTransition) (*model.ShiftsStatus, error) {
+	var status model.ShiftsStatus
+	err := tx.NewSelect().
+		Model(&status).
+		Where("year = ? AND month = ? AND location_id = ?", t.Year, t.Month, t.LocationID).
+		For("UPDATE").
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		status = model.ShiftsStatus{Year: t.Year, Month: t.Month, LocationID: t.LocationID, State: model.ScheduleStateDraft}
+		_, err = tx.NewInsert().Model(&status).Returning("*").Exec(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if status.State != t.FromState {
+		return nil, ErrScheduleStateChanged
+	}
+	return &status, nil
+}
+
+func monthShifts(ctx context.Context, tx bun.Tx, locationID int64, year int, month int) ([]model.DraftShift, error) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+
+	var shifts []model.Shift
+	err := tx.NewSelect().
+		Model(&shifts).
+		Where("location_id = ? AND shift_date >= ? AND shift_date < ?", locationID, start, start.AddDate(0, 1, 0)).
+		Order("shift_date ASC", "start_time ASC", "doctor_id ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.DraftShift, len(shifts))
+	for i, shift := range shifts {
+		result[i] = model.DraftShift{
+			DoctorID:   shift.DoctorID,
+			ShiftDate:  shift.ShiftDate,
+			StartTime:  shift.StartTime,
+			EndTime:    shift.EndTime,
+			TemplateID: shift.TemplateID,
+		}
+	}
+	return result, nil
+}
+
+// Ayın bir sonraki sürümünü oluşturur ve t.Version'ı günceller
+func createSnapshot(ctx context.Context, tx bun.Tx, t *model.ScheduleTransition, shifts []model.DraftShift) error {
+	if shifts == nil {
+		shifts = []model.DraftShift{}
+	}
+
+	snapshot := model.ScheduleSnapshot{
+		LocationID: t.LocationID,
+		Year:       t.Year,
+		Month:      t.Month,
+		Shifts:     shifts,
+		CreatedBy:  t.CreatedBy,
+	}
+	err := tx.NewSelect().
+		Model((*model.ScheduleSnapshot)(nil)).
+		ColumnExpr("COALESCE(MAX(version), 0) + 1").
+		Where("location_id = ? AND year = ? AND month = ?", t.LocationID, t.Year, t.Month).
+		Scan(ctx, &snapshot.Version)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.NewInsert().Model(&snapshot).Exec(ctx); err != nil {
+		return err
+	}
+	t.Version = snapshot.Version
+	return nil
+}
+
+func updateStatus(ctx context.Context, tx bun.Tx, status *model.ShiftsStatus, t *model.ScheduleTransition) error {
+	now := time.Now()
+	query := tx.NewUpdate().
+		Model((*model.ShiftsStatus)(nil)).
+		Set("state = ?", t.ToState).
+		Set("version = ?", t.Version).
+		Where("id = ?", status.ID)
+
+	if t.Version != status.Version {
+		query = query.Set("published_at = ?", now)
+	}
+	switch {
+	case t.ToState == model.ScheduleStateLocked && status.State != model.ScheduleStateLocked:
+		query = query.Set("locked_at = ?", now)
+	case t.ToState != model.ScheduleStateLocked:
+		query = query.Set("locked_at = NULL")
+	}
+
+	_, err := query.Exec(ctx)
+	return err
+}
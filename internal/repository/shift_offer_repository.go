@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"shift-scheduling-v2/internal/model"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// İlandaki nöbet ilan verildikten sonra başka doktora geçmiş ya da silinmiş
+var ErrOfferShiftChanged = errors.New("offer shift changed")
+
+var activeOfferStatuses = []string{model.OfferStatusOpen, model.OfferStatusClaimed}
+
+type ShiftOfferRepository struct {
+	db *bun.DB
+}
+
+func NewShiftOfferRepository(db *bun.DB) *ShiftOfferRepository {
+	return &ShiftOfferRepository{db: db}
+}
+
+func (r *ShiftOfferRepository) Create(ctx context.Context, offer *model.ShiftOffer) error {
+	_, err := r.db.NewInsert().Model(offer).Exec(ctx)
+	return err
+}
+
+func (r *ShiftOfferRepository) GetByID(ctx context.Context, id int64) (*model.ShiftOffer, error) {
+	var offer model.ShiftOffer
+	err := r.db.NewSelect().
+		Model(&offer).
+		Relation("Shift").
+		Relation("Offerer.User").
+		Relation("Claimer.User").
+		Where("shift_offer.id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// Boş bırakılan filtreler uygulanmaz
+func (r *ShiftOfferRepository) List(ctx context.Context, statuses []string, locationIDs []int64) ([]model.ShiftOffer, error) {
+	var offers []model.ShiftOffer
+	query := r.db.NewSelect().
+		Model(&offers).
+		Relation("Shift").
+		Relation("Offerer.User").
+		Relation("Claimer.User")
+
+	if len(statuses) > 0 {
+		query = query.Where("shift_offer.status IN (?)", bun.In(statuses))
+	}
+	if len(locationIDs) > 0 {
+		query = query.Where("shift_offer.location_id IN (?)", bun.In(locationIDs))
+	}
+
+	err := query.Order("shift_offer.shift_date ASC", "shift_offer.id ASC").Scan(ctx)
+	return offers, err
+}
+
+// Doktorun verdiği ya da aldığı ilanlar
+func (r *ShiftOfferRepository) ListByDoctor(ctx context.Context, doctorID int64) ([]model.ShiftOffer, error) {
+	var offers []model.ShiftOffer
+	err := r.db.NewSelect().
+		Model(&offers).
+		Relation("Shift").
+		Relation("Offerer.User").
+		Relation("Claimer.User").
+		Where("shift_offer.offered_by = ? OR shift_offer.claimed_by = ?", doctorID, doctorID).
+		Order("shift_offer.created_at DESC").
+		Scan(ctx)
+	return offers, err
+}
+
+// Nöbet için açık ya da onay bekleyen bir ilan olup olmadığını döner
+func (r *ShiftOfferRepository) HasActiveForShift(ctx context.Context, shiftID int64) (bool, error) {
+	return r.db.NewSelect().
+		Model((*model.ShiftOffer)(nil)).
+		Where("shift_id = ?", shiftID).
+		Where("status IN (?)", bun.In(activeOfferStatuses)).
+		Exists(ctx)
+}
+
+// İlanı yalnızca hâlâ from durumundaysa günceller, transfer true ise nöbeti aynı transaction içinde
+// ilanı alan doktora devreder. İlan bu arada başka bir duruma geçtiyse sql.ErrNoRows,
+// nöbetin sahibi değiştiyse ErrOfferShiftChanged döner. Aynı ilanı aynı anda alan doktorlardan
+// yalnızca ilki başarılı olur.
+func (r *ShiftOfferRepository) Transition(ctx context.Context, offer *model.ShiftOffer, from string, transfer bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	exists, err := tx.NewSelect().
+		Model((*model.ShiftOffer)(nil)).
+		Where("id = ? AND status = ?", offer.ID, from).
+		For("UPDATE").
+		Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	if transfer {
+		var shift model.Shift
+		err = tx.NewSelect().
+			Model(&shift).
+			Where("id = ?", offer.ShiftID).
+			For("UPDATE").
+			Scan(ctx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrOfferShiftChanged
+			}
+			return err
+		}
+		if shift.DoctorID != offer.OfferedBy {
+			return ErrOfferShiftChanged
+		}
+
+		_, err = tx.NewUpdate().
+			Model((*model.Shift)(nil)).
+			Set("doctor_id = ?", offer.ClaimedBy).
+			Where("id = ?", offer.ShiftID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.NewUpdate().
+		Model(offer).
+		Column("status", "claimed_by", "claimed_at", "reviewed_by", "reviewed_at", "resolved_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	if transfer {
+		invalidateAnalytics(ctx, offer.LocationID)
+	}
+	return nil
+}
+
+// Nöbet günü gelmiş ya da geçmiş, sonuçlanmamış ilanları süresi dolmuş olarak işaretler
+func (r *ShiftOfferRepository) ExpireDue(ctx context.Context, now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	_, err := r.db.NewUpdate().
+		Model((*model.ShiftOffer)(nil)).
+		Set("status = ?", model.OfferStatusExpired).
+		Set("resolved_at = ?", now).
+		Where("status IN (?)", bun.In(activeOfferStatuses)).
+		Where("shift_date <= ?", today).
+		Exec(ctx)
+	return err
+}
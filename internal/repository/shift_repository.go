@@ -2,12 +2,17 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"shift-scheduling-v2/internal/model"
 	"time"
 
 	"github.com/uptrace/bun"
 )
 
+// Nöbetler yazılmadan önce ay başka bir istekle atanmış ya da taslaktan çıkmış
+var ErrMonthAssigned = errors.New("month already assigned")
+
 type ShiftRepository struct {
 	db *bun.DB
 }
@@ -41,7 +46,49 @@ func (r *ShiftRepository) DeleteShiftsForMonth(ctx context.Context, year int, mo
 		Model((*model.Shift)(nil)).
 		Where("EXTRACT(YEAR FROM shift_date) = ? AND EXTRACT(MONTH FROM shift_date) = ? AND location_id = ?", year, month, locationID).
 		Exec(ctx)
-	return err
+	if err != nil {
+		return err
+	}
+
+	invalidateAnalytics(ctx, int64(locationID))
+	return nil
+}
+
+// Ayın durumunu günceller ve nöbetlerini tek transaction içinde siler. Ay bu arada
+// taslak olmaktan çıktıysa hiçbir şey değişmez ve sql.ErrNoRows döner.
+func (r *ShiftRepository) ResetMonth(ctx context.Context, status *model.ShiftsStatus) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.NewUpdate().
+		Model(status).
+		Column("done", "covered_shifts", "coverage").
+		Where("id = ? AND state = ?", status.ID, model.ScheduleStateDraft).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.NewDelete().
+		Model((*model.Shift)(nil)).
+		Where("EXTRACT(YEAR FROM shift_date) = ? AND EXTRACT(MONTH FROM shift_date) = ? AND location_id = ?", status.Year, status.Month, status.LocationID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	invalidateAnalytics(ctx, status.LocationID)
+	return nil
 }
 
 func (r *ShiftRepository) IsDoctorAssignedToShift(ctx context.Context, doctorID int64, shiftDate time.Time) (bool, error) {
@@ -52,9 +99,14 @@ func (r *ShiftRepository) IsDoctorAssignedToShift(ctx context.Context, doctorID
 	return exists, err
 }
 
-func (r *ShiftRepository) Create(ctx context.Context, shift model.Shift) error {
-	_, err := r.db.NewInsert().Model(&shift).Exec(ctx)
-	return err
+func (r *ShiftRepository) Create(ctx context.Context, shift *model.Shift) error {
+	_, err := r.db.NewInsert().Model(shift).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	invalidateAnalytics(ctx, shift.LocationID)
+	return nil
 }
 
 func (r *ShiftRepository) GetShiftByDate(ctx context.Context, date time.Time) (*model.Shift, error) {
@@ -69,14 +121,15 @@ func (r *ShiftRepository) GetShiftByDate(ctx context.Context, date time.Time) (*
 	return &shift, nil
 }
 
-func (r *ShiftRepository) GetTodayShifts(ctx context.Context, date time.Time) ([]model.Shift, error) {
+func (r *ShiftRepository) GetShiftsByDates(ctx context.Context, dates []time.Time) ([]model.Shift, error) {
 	var shifts []model.Shift
 	err := r.db.NewSelect().
 		Model(&shifts).
 		Relation("Doctor").
 		Relation("Doctor.User").
 		Relation("Location").
-		Where("shift_date = ?", date).
+		Relation("Template").
+		Where("shift_date IN (?)", bun.In(dates)).
 		Scan(ctx)
 	return shifts, err
 }
@@ -88,6 +141,7 @@ func (r *ShiftRepository) GetAllShiftsWithDetails(ctx context.Context) ([]model.
 		Relation("Doctor").
 		Relation("Doctor.User").
 		Relation("Location").
+		Relation("Template").
 		Scan(ctx)
 	return shifts, err
 }
@@ -99,6 +153,7 @@ func (r *ShiftRepository) GetShiftsByLocationID(ctx context.Context, locationID
 		Relation("Doctor").
 		Relation("Doctor.User").
 		Relation("Location").
+		Relation("Template").
 		Where("location_id = ?", locationID)
 
 	if month != 0 && year != 0 {
@@ -137,7 +192,13 @@ func (r *ShiftRepository) DeleteShift(ctx context.Context, id int64) error {
 		Model((*model.Shift)(nil)).
 		Where("id = ?", id).
 		Exec(ctx)
-	return err
+	if err != nil {
+		return err
+	}
+
+	// Nöbetin lokasyonu burada bilinmediğinden tüm analizler silinir
+	invalidateAnalytics(ctx)
+	return nil
 }
 
 func (r *ShiftRepository) UpdateShift(ctx context.Context, shift model.Shift) error {
@@ -145,7 +206,13 @@ func (r *ShiftRepository) UpdateShift(ctx context.Context, shift model.Shift) er
 		Model(&shift).
 		WherePK().
 		Exec(ctx)
-	return err
+	if err != nil {
+		return err
+	}
+
+	// Nöbet başka bir lokasyondan taşınmış olabilir
+	invalidateAnalytics(ctx)
+	return nil
 }
 
 func (r *ShiftRepository) GetShiftsStatus(ctx context.Context) ([]model.ShiftsStatus, error) {
@@ -156,33 +223,292 @@ func (r *ShiftRepository) GetShiftsStatus(ctx context.Context) ([]model.ShiftsSt
 	return shiftsStatus, err
 }
 
+// Arşivlenmemiş lokasyonlar
 func (r *ShiftRepository) GetShiftLocations(ctx context.Context) ([]model.ShiftLocation, error) {
 	var locations []model.ShiftLocation
 	err := r.db.NewSelect().
 		Model(&locations).
+		Where("archived_at IS NULL").
 		Scan(ctx)
 	return locations, err
 }
 
-func (r *ShiftRepository) GetDoctorsByLocation(ctx context.Context, locationID int64) ([]model.Doctor, error) {
-	var doctors []model.Doctor
+func (r *ShiftRepository) GetShiftsByDoctorIDs(ctx context.Context, doctorIDs []int64, start time.Time, end time.Time) ([]model.Shift, error) {
+	var shifts []model.Shift
+	if len(doctorIDs) == 0 {
+		return shifts, nil
+	}
+
+	err := r.db.NewSelect().
+		Model(&shifts).
+		Where("doctor_id IN (?)", bun.In(doctorIDs)).
+		Where("shift_date >= ? AND shift_date < ?", start, end).
+		Order("shift_date ASC").
+		Scan(ctx)
+	return shifts, err
+}
+
+// Doktorun aralıktaki nöbetleri lokasyon ve şablon bilgisiyle
+func (r *ShiftRepository) GetDoctorShifts(ctx context.Context, doctorID int64, start time.Time, end time.Time) ([]model.Shift, error) {
+	var shifts []model.Shift
+	err := r.db.NewSelect().
+		Model(&shifts).
+		Relation("Doctor").
+		Relation("Doctor.User").
+		Relation("Location").
+		Relation("Template").
+		Where("shift.doctor_id = ?", doctorID).
+		Where("shift.shift_date >= ? AND shift.shift_date < ?", start, end).
+		Order("shift.shift_date ASC", "shift.start_time ASC").
+		Scan(ctx)
+	return shifts, err
+}
+
+// Lokasyonun aralıktaki nöbetleri doktor ve şablon bilgisiyle
+func (r *ShiftRepository) GetLocationShifts(ctx context.Context, locationID int64, start time.Time, end time.Time) ([]model.Shift, error) {
+	var shifts []model.Shift
+	err := r.db.NewSelect().
+		Model(&shifts).
+		Relation("Doctor").
+		Relation("Doctor.User").
+		Relation("Location").
+		Relation("Template").
+		Where("shift.location_id = ?", locationID).
+		Where("shift.shift_date >= ? AND shift.shift_date < ?", start, end).
+		Order("shift.shift_date ASC", "shift.start_time ASC").
+		Scan(ctx)
+	return shifts, err
+}
+
+// Planlanan nöbetleri tek transaction içinde kaydeder ve ayın durumunu kapsamasıyla birlikte günceller.
+// Ay bu arada atanmışsa hiçbir şey yazılmaz ve ErrMonthAssigned döner.
+func (r *ShiftRepository) AssignShiftsForMonth(ctx context.Context, shifts []model.Shift, status model.ShiftsStatus) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = assignShiftsTx(ctx, tx, shifts, status); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	invalidateAnalytics(ctx, status.LocationID)
+	return nil
+}
+
+// Ayın durum kaydını kilitleyip atanmış olarak işaretler, ardından nöbetleri ekler.
+// Aynı aya aynı anda yapılan ikinci atama kilidi bekler ve ErrMonthAssigned ile döner.
+func assignShiftsTx(ctx context.Context, tx bun.Tx, shifts []model.Shift, status model.ShiftsStatus) error {
+	var current model.ShiftsStatus
+	err := tx.NewSelect().
+		Model(&current).
+		Where("year = ? AND month = ? AND location_id = ?", status.Year, status.Month, status.LocationID).
+		For("UPDATE").
+		Scan(ctx)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		status.Done = true
+		if status.State == "" {
+			status.State = model.ScheduleStateDraft
+		}
+		if _, err = tx.NewInsert().Model(&status).Exec(ctx); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case current.Done || current.State != model.ScheduleStateDraft:
+		return ErrMonthAssigned
+	default:
+		_, err = tx.NewUpdate().
+			Model((*model.ShiftsStatus)(nil)).
+			Set("done = true").
+			Set("required_shifts = ?", status.RequiredShifts).
+			Set("covered_shifts = ?", status.CoveredShifts).
+			Set("coverage = ?", status.Coverage).
+			Where("id = ?", current.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(shifts) > 0 {
+		if _, err = tx.NewInsert().Model(&shifts).Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Ayın durum kaydı varsa kapsama bilgisini günceller
+func (r *ShiftRepository) UpdateShiftStatusCoverage(ctx context.Context, status model.ShiftsStatus) error {
+	_, err := r.db.NewUpdate().
+		Model((*model.ShiftsStatus)(nil)).
+		Set("required_shifts = ?", status.RequiredShifts).
+		Set("covered_shifts = ?", status.CoveredShifts).
+		Set("coverage = ?", status.Coverage).
+		Where("year = ? AND month = ? AND location_id = ?", status.Year, status.Month, status.LocationID).
+		Exec(ctx)
+	return err
+}
+
+// Lokasyonun verilen aralıktaki günlük nöbet sayıları (tarih -> sayı)
+func (r *ShiftRepository) CountShiftsByDate(ctx context.Context, locationID int64, start time.Time, end time.Time) (map[string]int, error) {
+	var rows []struct {
+		ShiftDate time.Time `bun:"shift_date"`
+		Count     int       `bun:"count"`
+	}
 	err := r.db.NewSelect().
-		Model(&doctors).
+		Model((*model.Shift)(nil)).
+		Column("shift_date").
+		ColumnExpr("COUNT(*) AS count").
+		Where("location_id = ?", locationID).
+		Where("shift_date >= ? AND shift_date < ?", start, end).
+		Group("shift_date").
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.ShiftDate.Format("2006-01-02")] = row.Count
+	}
+	return counts, nil
+}
+
+// Lokasyonun verilen aralıktaki gün ve şablon bazında nöbet sayıları (tarih -> şablon ID -> sayı).
+// Şablonsuz nöbetler 0 altında sayılır.
+func (r *ShiftRepository) CountShiftsByTemplate(ctx context.Context, locationID int64, start time.Time, end time.Time) (map[string]map[int64]int, error) {
+	var rows []struct {
+		ShiftDate  time.Time `bun:"shift_date"`
+		TemplateID int64     `bun:"template_id"`
+		Count      int       `bun:"count"`
+	}
+	err := r.db.NewSelect().
+		Model((*model.Shift)(nil)).
+		Column("shift_date").
+		ColumnExpr("COALESCE(template_id, 0) AS template_id").
+		ColumnExpr("COUNT(*) AS count").
 		Where("location_id = ?", locationID).
+		Where("shift_date >= ? AND shift_date < ?", start, end).
+		GroupExpr("shift_date, COALESCE(template_id, 0)").
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]map[int64]int)
+	for _, row := range rows {
+		key := row.ShiftDate.Format("2006-01-02")
+		if counts[key] == nil {
+			counts[key] = make(map[int64]int)
+		}
+		counts[key][row.TemplateID] += row.Count
+	}
+	return counts, nil
+}
+
+// Lokasyonun verilen aralıktaki gün bazlı gereksinimleri. Aralık sıfırsa tümü döner.
+func (r *ShiftRepository) GetCoverageOverrides(ctx context.Context, locationID int64, start time.Time, end time.Time) ([]model.CoverageOverride, error) {
+	var overrides []model.CoverageOverride
+	query := r.db.NewSelect().
+		Model(&overrides).
+		Where("location_id = ?", locationID)
+
+	if !start.IsZero() && !end.IsZero() {
+		query = query.Where("override_date >= ? AND override_date < ?", start, end)
+	}
+
+	err := query.Order("override_date ASC").Scan(ctx)
+	return overrides, err
+}
+
+func (r *ShiftRepository) UpsertCoverageOverride(ctx context.Context, override *model.CoverageOverride) error {
+	_, err := r.db.NewInsert().
+		Model(override).
+		On("CONFLICT (location_id, override_date) DO UPDATE").
+		Set("doctors_required = EXCLUDED.doctors_required").
+		Set("deleted_at = NULL").
+		Returning("*").
+		Exec(ctx)
+	return err
+}
+
+func (r *ShiftRepository) DeleteCoverageOverride(ctx context.Context, locationID int64, date time.Time) error {
+	_, err := r.db.NewDelete().
+		Model((*model.CoverageOverride)(nil)).
+		Where("location_id = ? AND override_date = ?", locationID, date).
+		ForceDelete().
+		Exec(ctx)
+	return err
+}
+
+func (r *ShiftRepository) CreateDraft(ctx context.Context, draft *model.ScheduleDraft) error {
+	_, err := r.db.NewInsert().Model(draft).Exec(ctx)
+	return err
+}
+
+func (r *ShiftRepository) GetDraftByID(ctx context.Context, id int64) (*model.ScheduleDraft, error) {
+	var draft model.ScheduleDraft
+	err := r.db.NewSelect().
+		Model(&draft).
+		Where("id = ?", id).
 		Scan(ctx)
-	return doctors, err
+	if err != nil {
+		return nil, err
+	}
+	return &draft, nil
 }
 
-func (r *ShiftRepository) AssignShiftsForMonth(ctx context.Context, doctors []model.Doctor, locationID int, startOfMonth time.Time, endOfMonth time.Time) error {
+// Taslaktaki nöbetleri olduğu gibi kaydeder ve ayın durumunu verilen kapsamayla günceller.
+// Taslak aynı anda iki kez kaydedilemez, ay bu arada atanmışsa ErrMonthAssigned döner.
+func (r *ShiftRepository) CommitDraft(ctx context.Context, draftID int64, status model.ShiftsStatus) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	// Nöbet atama işlemleri...
-	// Bu kısım oldukça karmaşık ve uzun olduğu için, service katmanında kalabilir
-	// Ya da ayrı bir "assignment" servisi oluşturulabilir
+	var draft model.ScheduleDraft
+	err = tx.NewSelect().
+		Model(&draft).
+		Where("id = ? AND status = ?", draftID, model.DraftStatusDraft).
+		For("UPDATE").
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	shifts := make([]model.Shift, len(draft.Shifts))
+	for i, ds := range draft.Shifts {
+		shifts[i] = ds.ToShift(draft.LocationID)
+	}
+
+	if err = assignShiftsTx(ctx, tx, shifts, status); err != nil {
+		return err
+	}
+
+	_, err = tx.NewUpdate().
+		Model((*model.ScheduleDraft)(nil)).
+		Set("status = ?", model.DraftStatusCommitted).
+		Set("committed_at = ?", time.Now()).
+		Where("id = ?", draftID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
 
-	return tx.Commit()
+	invalidateAnalytics(ctx, draft.LocationID)
+	return nil
 }
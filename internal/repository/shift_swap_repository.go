@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"shift-scheduling-v2/internal/model"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Değişime konu nöbetlerden biri talep oluşturulduktan sonra başka doktora geçmiş ya da silinmiş
+var ErrSwapShiftsChanged = errors.New("swap shifts changed")
+
+type ShiftSwapRepository struct {
+	db *bun.DB
+}
+
+func NewShiftSwapRepository(db *bun.DB) *ShiftSwapRepository {
+	return &ShiftSwapRepository{db: db}
+}
+
+func (r *ShiftSwapRepository) Create(ctx context.Context, swap *model.ShiftSwapRequest) error {
+	_, err := r.db.NewInsert().Model(swap).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	invalidateAnalytics(ctx, swap.LocationID)
+	return nil
+}
+
+func (r *ShiftSwapRepository) GetByID(ctx context.Context, id int64) (*model.ShiftSwapRequest, error) {
+	var swap model.ShiftSwapRequest
+	err := r.db.NewSelect().
+		Model(&swap).
+		Relation("Requester.User").
+		Relation("Acceptor.User").
+		Relation("RequestedShift").
+		Relation("OfferedShift").
+		Where("shift_swap_request.id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &swap, nil
+}
+
+// Doktorun talep eden ya da karşı taraf olduğu talepler
+func (r *ShiftSwapRepository) ListByDoctor(ctx context.Context, doctorID int64) ([]model.ShiftSwapRequest, error) {
+	var swaps []model.ShiftSwapRequest
+	err := r.db.NewSelect().
+		Model(&swaps).
+		Relation("Requester.User").
+		Relation("Acceptor.User").
+		Where("shift_swap_request.requester_id = ? OR shift_swap_request.acceptor_id = ?", doctorID, doctorID).
+		Order("shift_swap_request.created_at DESC").
+		Scan(ctx)
+	return swaps, err
+}
+
+// Boş bırakılan filtreler uygulanmaz
+func (r *ShiftSwapRepository) List(ctx context.Context, status string, locationID int64) ([]model.ShiftSwapRequest, error) {
+	var swaps []model.ShiftSwapRequest
+	query := r.db.NewSelect().
+		Model(&swaps).
+		Relation("Requester.User").
+		Relation("Acceptor.User")
+
+	if status != "" {
+		query = query.Where("shift_swap_request.status = ?", status)
+	}
+	if locationID != 0 {
+		query = query.Where("shift_swap_request.location_id = ?", locationID)
+	}
+
+	err := query.Order("shift_swap_request.created_at DESC").Scan(ctx)
+	return swaps, err
+}
+
+// Nöbet için sonuçlanmamış bir talep olup olmadığını döner
+func (r *ShiftSwapRepository) HasOpenForShift(ctx context.Context, shiftID int64) (bool, error) {
+	return r.db.NewSelect().
+		Model((*model.ShiftSwapRequest)(nil)).
+		Where("status IN (?)", bun.In([]string{model.SwapStatusPending, model.SwapStatusAccepted})).
+		Where("requested_shift_id = ? OR offered_shift_id = ?", shiftID, shiftID).
+		Exists(ctx)
+}
+
+// Talebin durumunu yalnızca hâlâ from durumundaysa günceller.
+// Talep bu arada başka bir duruma geçtiyse sql.ErrNoRows döner.
+func (r *ShiftSwapRepository) UpdateStatus(ctx context.Context, swap *model.ShiftSwapRequest, from string) error {
+	res, err := r.db.NewUpdate().
+		Model(swap).
+		Column("status", "acceptor_comment", "mutual_agreement", "reviewed_by", "reviewed_at", "resolved_at").
+		Where("id = ? AND status = ?", swap.ID, from).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	invalidateAnalytics(ctx, swap.LocationID)
+	return nil
+}
+
+// İki nöbetin doktorlarını tek transaction içinde değiştirir ve talebi günceller.
+// Talep from durumunda değilse sql.ErrNoRows, nöbetlerin sahibi değiştiyse ErrSwapShiftsChanged döner.
+func (r *ShiftSwapRepository) ExecuteSwap(ctx context.Context, swap *model.ShiftSwapRequest, from string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	exists, err := tx.NewSelect().
+		Model((*model.ShiftSwapRequest)(nil)).
+		Where("id = ? AND status = ?", swap.ID, from).
+		For("UPDATE").
+		Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	var shifts []model.Shift
+	err = tx.NewSelect().
+		Model(&shifts).
+		Where("id IN (?)", bun.In([]int64{swap.OfferedShiftID, swap.RequestedShiftID})).
+		For("UPDATE").
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	owners := make(map[int64]int64, len(shifts))
+	for _, shift := range shifts {
+		owners[shift.ID] = shift.DoctorID
+	}
+	if owners[swap.OfferedShiftID] != swap.RequesterID || owners[swap.RequestedShiftID] != swap.AcceptorID {
+		return ErrSwapShiftsChanged
+	}
+
+	for shiftID, doctorID := range map[int64]int64{
+		swap.OfferedShiftID:   swap.AcceptorID,
+		swap.RequestedShiftID: swap.RequesterID,
+	} {
+		_, err = tx.NewUpdate().
+			Model((*model.Shift)(nil)).
+			Set("doctor_id = ?", doctorID).
+			Where("id = ?", shiftID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.NewUpdate().
+		Model(swap).
+		Column("status", "acceptor_comment", "mutual_agreement", "reviewed_by", "reviewed_at", "resolved_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	invalidateAnalytics(ctx, swap.LocationID)
+	return nil
+}
+
+// Nöbet günü gelmiş ya da geçmiş, sonuçlanmamış talepleri süresi dolmuş olarak işaretler
+func (r *ShiftSwapRepository) ExpireDue(ctx context.Context, now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	_, err := r.db.NewUpdate().
+		Model((*model.ShiftSwapRequest)(nil)).
+		Set("status = ?", model.SwapStatusExpired).
+		Set("resolved_at = ?", now).
+		Where("status IN (?)", bun.In([]string{model.SwapStatusPending, model.SwapStatusAccepted})).
+		Where("LEAST(request_shift_date, offered_shift_date) <= ?", today).
+		Exec(ctx)
+	return err
+}
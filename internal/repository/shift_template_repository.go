@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"shift-scheduling-v2/internal/model"
+)
+
+func (r *ShiftRepository) GetTemplatesByLocation(ctx context.Context, locationID int64) ([]model.ShiftTemplate, error) {
+	var templates []model.ShiftTemplate
+	err := r.db.NewSelect().
+		Model(&templates).
+		Where("location_id = ?", locationID).
+		Order("start_time ASC", "id ASC").
+		Scan(ctx)
+	return templates, err
+}
+
+func (r *ShiftRepository) GetTemplateByID(ctx context.Context, id int64) (*model.ShiftTemplate, error) {
+	var template model.ShiftTemplate
+	err := r.db.NewSelect().
+		Model(&template).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *ShiftRepository) CreateTemplate(ctx context.Context, template *model.ShiftTemplate) error {
+	_, err := r.db.NewInsert().Model(template).Exec(ctx)
+	return err
+}
+
+func (r *ShiftRepository) UpdateTemplate(ctx context.Context, template *model.ShiftTemplate) error {
+	_, err := r.db.NewUpdate().
+		Model(template).
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+func (r *ShiftRepository) DeleteTemplate(ctx context.Context, id int64) error {
+	_, err := r.db.NewDelete().
+		Model((*model.ShiftTemplate)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
@@ -146,3 +146,27 @@ func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool,
 
 	return exists, nil
 }
+
+// E-posta ya da telefonu verilenlerden biri olan kullanıcılar. Benzersizlik silinmiş kayıtları da
+// kapsadığından silinmiş kullanıcılar da döner; e-postalar küçük harfe çevrilerek karşılaştırılır.
+func (r *UserRepository) GetByEmailsOrPhones(ctx context.Context, emails []string, phones []string) ([]model.User, error) {
+	var users []model.User
+	if len(emails) == 0 && len(phones) == 0 {
+		return users, nil
+	}
+
+	err := r.db.NewSelect().
+		Model(&users).
+		WhereAllWithDeleted().
+		WhereGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+			if len(emails) > 0 {
+				q = q.WhereOr("LOWER(email) IN (?)", bun.In(emails))
+			}
+			if len(phones) > 0 {
+				q = q.WhereOr("phone IN (?)", bun.In(phones))
+			}
+			return q
+		}).
+		Scan(ctx)
+	return users, err
+}
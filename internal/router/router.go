@@ -3,6 +3,8 @@ package router
 import (
 	"shift-scheduling-v2/internal/handler"
 	"shift-scheduling-v2/internal/middleware"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/pkg/response"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -16,16 +18,42 @@ type Router struct {
 	userHandler   *handler.UserHandler
 	doctorHandler *handler.DoctorHandler
 	shiftHandler  *handler.ShiftHandler
+	swapHandler   *handler.ShiftSwapHandler
+	offerHandler  *handler.ShiftOfferHandler
+	notifyHandler *handler.NotificationHandler
+	streamHandler *handler.StreamHandler
+	leaveHandler  *handler.LeaveHandler
+	calHandler    *handler.HolidayCalendarHandler
+	locHandler    *handler.LocationHandler
+	schedHandler  *handler.ScheduleHandler
+	auditHandler  *handler.AuditHandler
+	portalHandler *handler.DoctorPortalHandler
+	feedHandler   *handler.CalendarFeedHandler
+	importHandler *handler.ImportHandler
+	statsHandler  *handler.AnalyticsHandler
 	// Diğer handler'lar buraya eklenecek
 }
 
-func NewRouter(a *handler.AuthHandler, u *handler.UserHandler, d *handler.DoctorHandler, s *handler.ShiftHandler) *Router {
+func NewRouter(a *handler.AuthHandler, u *handler.UserHandler, d *handler.DoctorHandler, s *handler.ShiftHandler, sw *handler.ShiftSwapHandler, o *handler.ShiftOfferHandler, n *handler.NotificationHandler, st *handler.StreamHandler, l *handler.LeaveHandler, hc *handler.HolidayCalendarHandler, lc *handler.LocationHandler, sc *handler.ScheduleHandler, au *handler.AuditHandler, dp *handler.DoctorPortalHandler, cf *handler.CalendarFeedHandler, im *handler.ImportHandler, an *handler.AnalyticsHandler) *Router {
 	return &Router{
-		app:           fiber.New(),
+		app:           fiber.New(fiber.Config{ErrorHandler: response.ErrorHandler}),
 		authHandler:   a,
 		userHandler:   u,
 		doctorHandler: d,
 		shiftHandler:  s,
+		swapHandler:   sw,
+		offerHandler:  o,
+		notifyHandler: n,
+		streamHandler: st,
+		leaveHandler:  l,
+		calHandler:    hc,
+		locHandler:    lc,
+		schedHandler:  sc,
+		auditHandler:  au,
+		portalHandler: dp,
+		feedHandler:   cf,
+		importHandler: im,
+		statsHandler:  an,
 	}
 }
 
@@ -34,6 +62,7 @@ func (r *Router) SetupRoutes() {
 	r.app.Use(logger.New())
 	r.app.Use(recover.New())
 	r.app.Use(cors.New())
+	r.app.Use(middleware.ClientIP())
 
 	// API versiyonu
 	api := r.app.Group("/api")
@@ -67,6 +96,14 @@ func (r *Router) SetupRoutes() {
 
 	// Doctor routes
 	doctors := v1.Group("/doctors")
+
+	// Doktorun kendi tercihleri (admin grubundan önce tanımlanmalı)
+	doctorSelf := doctors.Group("/me")
+	doctorSelf.Use(middleware.AuthMiddleware(), middleware.HasRole(model.UserRoleDoctor))
+	doctorSelf.Get("/preferences/:year/:month", r.doctorHandler.GetMyPreference)
+	doctorSelf.Put("/preferences/:year/:month", r.doctorHandler.UpdateMyPreference)
+	doctorSelf.Delete("/preferences/:year/:month", r.doctorHandler.DeleteMyPreference)
+
 	adminDoctors := doctors.Group("/")
 	adminDoctors.Use(middleware.AuthMiddleware(), middleware.AdminOnly())
 	adminDoctors.Get("/", r.doctorHandler.List)
@@ -84,6 +121,8 @@ func (r *Router) SetupRoutes() {
 	adminShifts := shifts.Group("/")
 	adminShifts.Use(middleware.AuthMiddleware(), middleware.AdminOnly())
 	adminShifts.Post("/shifts/auto-assign", r.shiftHandler.AutoAssignShifts)
+	adminShifts.Get("/drafts/:id", r.shiftHandler.GetDraft)
+	adminShifts.Post("/drafts/:id/commit", r.shiftHandler.CommitDraft)
 	adminShifts.Post("/shifts/reset", r.shiftHandler.ResetShifts)
 	adminShifts.Get("/today-shifts", r.shiftHandler.GetTodayShifts)
 	adminShifts.Get("/shifts/:date", r.shiftHandler.GetShiftByDate)
@@ -95,7 +134,135 @@ func (r *Router) SetupRoutes() {
 	adminShifts.Put("/:id", r.shiftHandler.UpdateShift)
 	adminShifts.Get("/shifts-status", r.shiftHandler.GetShiftsStatus)
 	adminShifts.Get("/shifts-locations", r.shiftHandler.GetShiftLocations)
+	adminShifts.Get("/rest-rules/:location_id", r.shiftHandler.GetRestRules)
+	adminShifts.Put("/rest-rules/:location_id", r.shiftHandler.UpdateRestRules)
+	adminShifts.Get("/coverage/:location_id", r.shiftHandler.GetCoverage)
+	adminShifts.Put("/coverage/:location_id", r.shiftHandler.UpdateCoverage)
+	adminShifts.Put("/coverage/:location_id/overrides", r.shiftHandler.UpsertCoverageOverride)
+	adminShifts.Delete("/coverage/:location_id/overrides/:date", r.shiftHandler.DeleteCoverageOverride)
+	adminShifts.Get("/locations/:location_id/templates", r.shiftHandler.GetTemplates)
+	adminShifts.Post("/locations/:location_id/templates", r.shiftHandler.CreateTemplate)
+	adminShifts.Put("/templates/:id", r.shiftHandler.UpdateTemplate)
+	adminShifts.Delete("/templates/:id", r.shiftHandler.DeleteTemplate)
+	adminShifts.Get("/hours/:location_id", r.shiftHandler.GetWorkedHours)
+	adminShifts.Get("/export/:location_id/:year/:month", r.shiftHandler.ExportMonth)
 	adminShifts.Post("/", r.shiftHandler.Create)
+
+	// Swap routes (doktor ve admin rotaları aynı grupta, middleware rota bazında)
+	swaps := v1.Group("/swaps")
+	authRequired, doctorOnly, adminOnly := middleware.AuthMiddleware(), middleware.HasRole(model.UserRoleDoctor), middleware.AdminOnly()
+	swaps.Post("/", authRequired, doctorOnly, r.swapHandler.Create)
+	swaps.Get("/me", authRequired, doctorOnly, r.swapHandler.ListMine)
+	swaps.Post("/:id/accept", authRequired, doctorOnly, r.swapHandler.Accept)
+	swaps.Post("/:id/reject", authRequired, doctorOnly, r.swapHandler.Reject)
+	swaps.Post("/:id/cancel", authRequired, doctorOnly, r.swapHandler.Cancel)
+	swaps.Get("/", authRequired, adminOnly, r.swapHandler.List)
+	swaps.Post("/:id/approve", authRequired, adminOnly, r.swapHandler.Approve)
+	swaps.Post("/:id/deny", authRequired, adminOnly, r.swapHandler.Deny)
+
+	// Offer routes (nöbet ilan panosu)
+	offers := v1.Group("/offers")
+	offers.Post("/", authRequired, doctorOnly, r.offerHandler.Create)
+	offers.Get("/me", authRequired, doctorOnly, r.offerHandler.ListMine)
+	offers.Get("/board", authRequired, doctorOnly, r.offerHandler.Board)
+	offers.Post("/:id/claim", authRequired, doctorOnly, r.offerHandler.Claim)
+	offers.Post("/:id/cancel", authRequired, doctorOnly, r.offerHandler.Cancel)
+	offers.Get("/", authRequired, adminOnly, r.offerHandler.List)
+	offers.Get("/:id/candidates", authRequired, adminOnly, r.offerHandler.Candidates)
+	offers.Post("/:id/approve", authRequired, adminOnly, r.offerHandler.Approve)
+	offers.Post("/:id/deny", authRequired, adminOnly, r.offerHandler.Deny)
+
+	// Leave request routes
+	leaves := v1.Group("/leave-requests")
+	leaves.Post("/", authRequired, doctorOnly, r.leaveHandler.Create)
+	leaves.Get("/me", authRequired, doctorOnly, r.leaveHandler.ListMine)
+	leaves.Get("/me/balance", authRequired, doctorOnly, r.leaveHandler.MyBalance)
+	leaves.Post("/:id/cancel", authRequired, doctorOnly, r.leaveHandler.Cancel)
+	leaves.Get("/", authRequired, adminOnly, r.leaveHandler.List)
+	leaves.Post("/:id/approve", authRequired, adminOnly, r.leaveHandler.Approve)
+	leaves.Post("/:id/reject", authRequired, adminOnly, r.leaveHandler.Reject)
+	leaves.Get("/balances/:location_id", authRequired, adminOnly, r.leaveHandler.LocationBalances)
+	leaves.Get("/entitlements/:doctor_id", authRequired, adminOnly, r.leaveHandler.GetEntitlements)
+	leaves.Put("/entitlements/:doctor_id", authRequired, adminOnly, r.leaveHandler.SetEntitlement)
+	leaves.Delete("/entitlements/:doctor_id/:leave_type", authRequired, adminOnly, r.leaveHandler.DeleteEntitlement)
+
+	// Resmi tatil takvimleri
+	calendars := v1.Group("/holiday-calendars")
+	calendars.Use(authRequired, adminOnly)
+	calendars.Get("/", r.calHandler.List)
+	calendars.Post("/", r.calHandler.Create)
+	calendars.Get("/locations/:location_id", r.calHandler.GetLocationCalendars)
+	calendars.Put("/locations/:location_id/:id", r.calHandler.SetLocationCalendar)
+	calendars.Delete("/locations/:location_id/:id", r.calHandler.DeleteLocationCalendar)
+	calendars.Get("/:id", r.calHandler.GetByID)
+	calendars.Put("/:id", r.calHandler.Update)
+	calendars.Delete("/:id", r.calHandler.Delete)
+	calendars.Post("/:id/import", r.calHandler.Import)
+	calendars.Post("/:id/entries", r.calHandler.AddEntry)
+	calendars.Delete("/:id/entries/:entry_id", r.calHandler.DeleteEntry)
+
+	// Lokasyon ve üyelik yönetimi
+	locations := v1.Group("/locations")
+	locations.Use(authRequired, adminOnly)
+	locations.Get("/", r.locHandler.List)
+	locations.Post("/", r.locHandler.Create)
+	locations.Get("/:id", r.locHandler.GetByID)
+	locations.Put("/:id", r.locHandler.Update)
+	locations.Post("/:id/archive", r.locHandler.Archive)
+	locations.Post("/:id/restore", r.locHandler.Restore)
+	locations.Get("/:id/members", r.locHandler.ListMembers)
+	locations.Post("/:id/members", r.locHandler.AddMember)
+	locations.Put("/:id/members/:member_id", r.locHandler.UpdateMember)
+	locations.Delete("/:id/members/:member_id", r.locHandler.RemoveMember)
+	locations.Get("/:id/policy", r.locHandler.GetPolicy)
+	locations.Put("/:id/policy", r.locHandler.UpdatePolicy)
+	locations.Get("/:id/policy/versions", r.locHandler.ListPolicyVersions)
+	locations.Get("/:id/policy/versions/:version", r.locHandler.GetPolicyVersion)
+	locations.Get("/:id/calendar-feeds", r.feedHandler.ListForLocation)
+	locations.Post("/:id/calendar-feeds", r.feedHandler.CreateForLocation)
+	locations.Get("/:id/analytics", r.statsHandler.LocationWorkload)
+
+	// Aylık nöbet listesi yayın döngüsü. Doktorlar sadece yayınlanmış ayları görür.
+	schedules := v1.Group("/schedules/:location_id/:year/:month")
+	schedules.Get("/", authRequired, r.schedHandler.Get)
+	schedules.Get("/versions", authRequired, adminOnly, r.schedHandler.ListVersions)
+	schedules.Get("/versions/:version", authRequired, adminOnly, r.schedHandler.GetVersion)
+	schedules.Get("/diff", authRequired, adminOnly, r.schedHandler.Diff)
+	schedules.Get("/transitions", authRequired, adminOnly, r.schedHandler.ListTransitions)
+	schedules.Post("/rollback", authRequired, adminOnly, r.schedHandler.Rollback)
+	schedules.Post("/:action", authRequired, adminOnly, r.schedHandler.Transition)
+
+	// Denetim kayıtları, sadece okunur
+	v1.Get("/audit-logs", authRequired, adminOnly, r.auditHandler.List)
+
+	// Takvim abonelikleri: iptal admin tarafından, yayın anahtarla ve JWT olmadan
+	v1.Delete("/calendar-feeds/:id", authRequired, adminOnly, r.feedHandler.Revoke)
+	r.app.Get("/calendar/:kind/:token.ics", r.feedHandler.Feed)
+
+	// Toplu CSV içe aktarma (doktorlar, üyelikler, izinler)
+	v1.Post("/imports", authRequired, adminOnly, r.importHandler.Import)
+
+	// Notification routes
+	me := v1.Group("/me")
+	me.Get("/notifications", authRequired, doctorOnly, r.notifyHandler.GetMine)
+	me.Post("/notifications/read-all", authRequired, doctorOnly, r.notifyHandler.MarkAllRead)
+	me.Post("/notifications/:id/read", authRequired, doctorOnly, r.notifyHandler.MarkRead)
+
+	// Doktorun kendi nöbet, izin ve değişim bilgileri
+	myDoctor := me.Group("/doctor")
+	myDoctor.Use(authRequired, doctorOnly)
+	myDoctor.Get("/shifts", r.portalHandler.UpcomingShifts)
+	myDoctor.Get("/holidays", r.portalHandler.Holidays)
+	myDoctor.Get("/leave-requests", r.portalHandler.LeaveRequests)
+	myDoctor.Get("/colleagues/today", r.portalHandler.ColleaguesToday)
+	myDoctor.Get("/stats/:year/:month", r.portalHandler.MonthStats)
+	myDoctor.Get("/swaps", r.portalHandler.Swaps)
+	myDoctor.Get("/calendar-feeds", r.feedHandler.ListMine)
+	myDoctor.Post("/calendar-feeds", r.feedHandler.CreateMine)
+	myDoctor.Delete("/calendar-feeds/:id", r.feedHandler.RevokeMine)
+
+	// Canlı olay akışı (SSE), filtreleme rol ve lokasyona göre serviste yapılır
+	v1.Get("/stream", authRequired, r.streamHandler.Stream)
 }
 
 func (r *Router) GetApp() *fiber.App {
@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// İhlal edilemeyen kural. Allows false dönerse doktor o slota atanamaz.
+// Allows yalnızca doktorun kendi nöbetlerine bakmalıdır, motor bir atamadan sonra
+// sadece atanan doktorun adaylıklarını yeniden hesaplar.
+type HardConstraint interface {
+	Name() string
+	Allows(s *State, d Doctor, slot Slot) bool
+}
+
+// Doktor yalnızca lokasyona üye olduğu günlerde nöbet tutabilir
+type ActiveMember struct{}
+
+func (ActiveMember) Name() string { return "active_member" }
+
+func (ActiveMember) Allows(s *State, d Doctor, slot Slot) bool {
+	return d.ActiveOn(slot.Date)
+}
+
+// Doktor tatil gününde nöbet tutamaz
+type NotOnHoliday struct{}
+
+func (NotOnHoliday) Name() string { return "not_on_holiday" }
+
+func (NotOnHoliday) Allows(s *State, d Doctor, slot Slot) bool {
+	return !s.OnHoliday(d.ID, slot.Date)
+}
+
+// Doktor aynı gün ya da çakışan saatlerde iki nöbet tutamaz
+type NoDoubleBooking struct{}
+
+func (NoDoubleBooking) Name() string { return "no_double_booking" }
+
+func (NoDoubleBooking) Allows(s *State, d Doctor, slot Slot) bool {
+	for _, other := range s.Shifts(d.ID) {
+		if dayNumber(other.Date) == dayNumber(slot.Date) {
+			return false
+		}
+		if other.Start.Before(slot.End) && slot.Start.Before(other.End) {
+			return false
+		}
+	}
+	return true
+}
+
+// Doktor aylık nöbet limitini aşamaz
+type WithinShiftLimit struct{}
+
+func (WithinShiftLimit) Name() string { return "within_shift_limit" }
+
+func (WithinShiftLimit) Allows(s *State, d Doctor, slot Slot) bool {
+	if d.ShiftLimit <= 0 {
+		return true
+	}
+	return s.CountInMonth(d.ID, slot.Date) < d.ShiftLimit
+}
+
+// Gerektiğinde adım adım gevşetilebilen kısıt.
+// Relax bir adım gevşetilmiş kopyayı döner, daha fazla gevşetilemiyorsa false döner.
+type Relaxable interface {
+	HardConstraint
+	Relax() (Relaxable, bool)
+	String() string
+}
+
+// Doktorun iki nöbeti arasında en az Gap kadar dinlenme süresi olmalı.
+// Gevşetildiğinde Gap, Step kadar azalır ve sıfırın altına inmez.
+type MinRestGap struct {
+	Gap  time.Duration
+	Step time.Duration
+}
+
+func (MinRestGap) Name() string { return "min_rest_gap" }
+
+func (c MinRestGap) String() string {
+	return fmt.Sprintf("%s=%s", c.Name(), formatHours(c.Gap))
+}
+
+func (c MinRestGap) Allows(s *State, d Doctor, slot Slot) bool {
+	for _, other := range s.Shifts(d.ID) {
+		if !other.End.After(slot.Start) && slot.Start.Sub(other.End) < c.Gap {
+			return false
+		}
+		if !slot.End.After(other.Start) && other.Start.Sub(slot.End) < c.Gap {
+			return false
+		}
+	}
+	return true
+}
+
+func (c MinRestGap) Relax() (Relaxable, bool) {
+	if c.Gap <= 0 || c.Step <= 0 {
+		return c, false
+	}
+	c.Gap -= c.Step
+	if c.Gap < 0 {
+		c.Gap = 0
+	}
+	return c, true
+}
+
+func formatHours(d time.Duration) string {
+	return fmt.Sprintf("%gh", d.Hours())
+}
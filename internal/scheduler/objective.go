@@ -0,0 +1,227 @@
+package scheduler
+
+import (
+	"slices"
+	"time"
+)
+
+// İhlali engellenmeyen ama amaç fonksiyonunda cezalandırılan kural.
+// Penalty mevcut planın tamamı için ceza puanını döner.
+type SoftConstraint interface {
+	Name() string
+	Penalty(s *State) float64
+}
+
+// Cezası doktorların ayrı ayrı hesaplanan katkılarından bulunabilen soft kısıt.
+// Motor bir atama değiştiğinde yalnızca nöbetleri değişen doktorların katkısını yeniden hesaplar.
+// Terms doktorun katkısını, Combine tüm doktorların katkılarının toplamından cezayı döner.
+type Decomposable interface {
+	SoftConstraint
+	Terms(s *State, d Doctor) []float64
+	Combine(sums []float64, doctors int) float64
+}
+
+// Aynı doktorun nöbetleri arasındaki kısa aralıkları cezalandırır.
+// Art arda günler en yüksek cezayı alır, aralık büyüdükçe ceza azalır.
+type Spacing struct{}
+
+func (Spacing) Name() string { return "spacing" }
+
+func (c Spacing) Penalty(s *State) float64 {
+	total := 0.0
+	for _, d := range s.Doctors() {
+		total += c.doctorPenalty(s, d.ID)
+	}
+	return total
+}
+
+func (c Spacing) Terms(s *State, d Doctor) []float64 {
+	return []float64{c.doctorPenalty(s, d.ID)}
+}
+
+func (Spacing) Combine(sums []float64, _ int) float64 {
+	return sums[0]
+}
+
+func (Spacing) doctorPenalty(s *State, doctorID int64) float64 {
+	total := 0.0
+	shifts := s.Shifts(doctorID)
+	for i := 1; i < len(shifts); i++ {
+		gap := shifts[i].Start.Sub(shifts[i-1].End)
+		if gap < 48*time.Hour {
+			total += (48*time.Hour - gap).Hours() / 24
+		}
+	}
+	return total
+}
+
+// Doktorlar arasındaki yük farkını cezalandırır.
+// Her doktorun yükü bu ayki ağırlıklı nöbetlerinin limitine oranı ile önceki aylardan
+// taşıdığı fazlalığın (ya da eksiğin) HistoryWeight ile çarpımının toplamıdır.
+// Hafta sonu ve resmi tatil nöbetlerinin sayıları da aynı şekilde ayrıca dengelenir.
+// Ceza, yüklerin ortalamadan sapmalarının kareleri toplamıdır.
+type Fairness struct {
+	HistoryWeight float64
+}
+
+func (Fairness) Name() string { return "fairness" }
+
+func (f Fairness) Penalty(s *State) float64 {
+	doctors := s.Doctors()
+	if len(doctors) == 0 {
+		return 0
+	}
+
+	avg := averageLimit(doctors)
+	loads := make([]float64, len(doctors))
+	weekends := make([]float64, len(doctors))
+	publicHolidays := make([]float64, len(doctors))
+	past := make([]History, len(doctors))
+
+	for i, d := range doctors {
+		loads[i] = s.WeightedLoad(d.ID)
+		if d.ShiftLimit > 0 {
+			loads[i] = loads[i] / float64(d.ShiftLimit) * avg
+		}
+		for _, slot := range s.InHorizon(d.ID) {
+			switch slot.Kind {
+			case Weekend:
+				weekends[i]++
+			case PublicHoliday:
+				publicHolidays[i]++
+			}
+		}
+		past[i] = s.History(d.ID)
+	}
+
+	f.addHistory(loads, past, func(h History) float64 { return h.Weighted })
+	f.addHistory(weekends, past, func(h History) float64 { return float64(h.Weekend) })
+	f.addHistory(publicHolidays, past, func(h History) float64 { return float64(h.PublicHoliday) })
+
+	return variance(loads) + variance(weekends) + variance(publicHolidays)
+}
+
+// Doktorun yük, hafta sonu ve resmi tatil değerleri ile kareleri. Geçmişin ortalaması
+// tüm doktorlardan aynı miktarda düşüldüğünden sapmaları değiştirmez, katkıya eklenmez.
+func (f Fairness) Terms(s *State, d Doctor) []float64 {
+	load := s.WeightedLoad(d.ID)
+	if d.ShiftLimit > 0 {
+		load = load / float64(d.ShiftLimit) * averageLimit(s.Doctors())
+	}
+	var weekends, publicHolidays float64
+	for _, slot := range s.InHorizon(d.ID) {
+		switch slot.Kind {
+		case Weekend:
+			weekends++
+		case PublicHoliday:
+			publicHolidays++
+		}
+	}
+
+	past := s.History(d.ID)
+	load += f.HistoryWeight * past.Weighted
+	weekends += f.HistoryWeight * float64(past.Weekend)
+	publicHolidays += f.HistoryWeight * float64(past.PublicHoliday)
+
+	return []float64{load, load * load, weekends, weekends * weekends, publicHolidays, publicHolidays * publicHolidays}
+}
+
+// Her değer için sapmaların kareleri toplamı, Σx² - (Σx)²/n
+func (Fairness) Combine(sums []float64, doctors int) float64 {
+	if doctors == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for i := 0; i+1 < len(sums); i += 2 {
+		total += sums[i+1] - sums[i]*sums[i]/float64(doctors)
+	}
+	return total
+}
+
+// Geçmiş yükün ortalamadan farkını HistoryWeight oranında mevcut yüke ekler
+func (f Fairness) addHistory(values []float64, past []History, pick func(History) float64) {
+	if f.HistoryWeight == 0 {
+		return
+	}
+
+	mean := 0.0
+	for _, h := range past {
+		mean += pick(h)
+	}
+	mean /= float64(len(past))
+
+	for i, h := range past {
+		values[i] += f.HistoryWeight * (pick(h) - mean)
+	}
+}
+
+// Değerlerin ortalamadan sapmalarının kareleri toplamı
+func variance(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	total := 0.0
+	for _, v := range values {
+		total += (v - mean) * (v - mean)
+	}
+	return total
+}
+
+func averageLimit(doctors []Doctor) float64 {
+	sum, n := 0, 0
+	for _, d := range doctors {
+		if d.ShiftLimit > 0 {
+			sum += d.ShiftLimit
+			n++
+		}
+	}
+	if n == 0 {
+		return 1
+	}
+	return float64(sum) / float64(n)
+}
+
+// Doktorun önceki yıllarda nöbet tuttuğu resmi tatile yeniden atanmasını cezalandırır.
+// Böylece aynı bayram her yıl aynı doktorlara düşmez.
+type HolidayRotation struct{}
+
+func (HolidayRotation) Name() string { return "holiday_rotation" }
+
+func (c HolidayRotation) Penalty(s *State) float64 {
+	total := 0.0
+	for _, d := range s.Doctors() {
+		total += c.doctorPenalty(s, d.ID)
+	}
+	return total
+}
+
+func (c HolidayRotation) Terms(s *State, d Doctor) []float64 {
+	return []float64{c.doctorPenalty(s, d.ID)}
+}
+
+func (HolidayRotation) Combine(sums []float64, _ int) float64 {
+	return sums[0]
+}
+
+func (HolidayRotation) doctorPenalty(s *State, doctorID int64) float64 {
+	past := s.History(doctorID).Holidays
+	if len(past) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, slot := range s.InHorizon(doctorID) {
+		if slot.Holiday != "" && slices.Contains(past, slot.Holiday) {
+			total++
+		}
+	}
+	return total
+}
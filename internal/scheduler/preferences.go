@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"slices"
+	"time"
+)
+
+// Tercih edilmeyen güne (kaçınılacak tarih) düşen nöbetin cezası.
+// Gün ya da şablon tercihine uymayan nöbet 1 puan alır.
+const avoidDatePenalty = 3
+
+// Doktorun bir aya ait tercihleri. Boş olan tercih uygulanmaz.
+type Preference struct {
+	Weekdays  []time.Weekday // nöbet tutmak istediği günler
+	Avoid     []time.Time    // nöbet tutmak istemediği tarihler
+	Templates []int64        // tercih ettiği nöbet şablonları
+	Weight    float64        // tercihin önemi, 0 ise 1 kabul edilir
+}
+
+func (p Preference) weight() float64 {
+	if p.Weight <= 0 {
+		return 1
+	}
+	return p.Weight
+}
+
+func (p Preference) avoids(day time.Time) bool {
+	return slices.ContainsFunc(p.Avoid, func(t time.Time) bool { return dateKey(t) == dateKey(day) })
+}
+
+// Tercihe uymayan nöbetleri cezalandırır
+type Preferences struct {
+	ByDoctor map[int64]Preference
+}
+
+func (Preferences) Name() string { return "preferences" }
+
+func (c Preferences) Penalty(s *State) float64 {
+	total := 0.0
+	for doctorID := range c.ByDoctor {
+		total += c.doctorPenalty(s, doctorID)
+	}
+	return total
+}
+
+// Tercihi olmayan doktorun katkısı sıfırdır
+func (c Preferences) Terms(s *State, d Doctor) []float64 {
+	return []float64{c.doctorPenalty(s, d.ID)}
+}
+
+func (Preferences) Combine(sums []float64, _ int) float64 {
+	return sums[0]
+}
+
+func (c Preferences) doctorPenalty(s *State, doctorID int64) float64 {
+	p, ok := c.ByDoctor[doctorID]
+	if !ok {
+		return 0
+	}
+
+	penalty := 0.0
+	for _, slot := range s.InHorizon(doctorID) {
+		if p.avoids(slot.Date) {
+			penalty += avoidDatePenalty
+		}
+		if len(p.Weekdays) > 0 && !slices.Contains(p.Weekdays, slot.Date.Weekday()) {
+			penalty++
+		}
+		if len(p.Templates) > 0 && !slices.Contains(p.Templates, slot.Template) {
+			penalty++
+		}
+	}
+	return penalty * p.weight()
+}
+
+// Doktorun karşılanan tercih sayısı
+type PreferenceStat struct {
+	Requested int
+	Satisfied int
+}
+
+// Atamalara göre doktor bazında karşılanan tercihleri sayar.
+// Her kaçınılacak tarih ile gün ya da şablon tercihi olan her nöbet bir tercih sayılır.
+func (c Preferences) Report(assignments []Assignment) map[int64]PreferenceStat {
+	byDoctor := make(map[int64][]Slot)
+	for _, a := range assignments {
+		byDoctor[a.DoctorID] = append(byDoctor[a.DoctorID], a.Slot)
+	}
+
+	result := make(map[int64]PreferenceStat, len(c.ByDoctor))
+	for doctorID, p := range c.ByDoctor {
+		var stat PreferenceStat
+		slots := byDoctor[doctorID]
+
+		for _, day := range p.Avoid {
+			stat.Requested++
+			if !slices.ContainsFunc(slots, func(slot Slot) bool { return dateKey(slot.Date) == dateKey(day) }) {
+				stat.Satisfied++
+			}
+		}
+
+		for _, slot := range slots {
+			if len(p.Weekdays) > 0 {
+				stat.Requested++
+				if slices.Contains(p.Weekdays, slot.Date.Weekday()) {
+					stat.Satisfied++
+				}
+			}
+			if len(p.Templates) > 0 {
+				stat.Requested++
+				if slices.Contains(p.Templates, slot.Template) {
+					stat.Satisfied++
+				}
+			}
+		}
+
+		result[doctorID] = stat
+	}
+	return result
+}
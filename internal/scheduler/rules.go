@@ -0,0 +1,99 @@
+package scheduler
+
+import "time"
+
+// Lokasyona özel dinlenme kuralları. Sıfır olan kural uygulanmaz.
+type RestRules struct {
+	MinRestHours       int
+	MaxConsecutiveDays int
+	MaxShiftsPerWeek   int
+}
+
+// Kuralları hard kısıtlara çevirir
+func (r RestRules) Constraints() []HardConstraint {
+	var result []HardConstraint
+	if r.MinRestHours > 0 {
+		result = append(result, MinRest{Hours: r.MinRestHours})
+	}
+	if r.MaxConsecutiveDays > 0 {
+		result = append(result, MaxConsecutiveDays{Days: r.MaxConsecutiveDays})
+	}
+	if r.MaxShiftsPerWeek > 0 {
+		result = append(result, MaxShiftsPerWindow{Shifts: r.MaxShiftsPerWeek, Days: 7})
+	}
+	return result
+}
+
+// İki nöbet arasında en az Hours saat dinlenme olmalı
+type MinRest struct {
+	Hours int
+}
+
+func (MinRest) Name() string { return "min_rest_hours" }
+
+func (c MinRest) Allows(s *State, d Doctor, slot Slot) bool {
+	return MinRestGap{Gap: time.Duration(c.Hours) * time.Hour}.Allows(s, d, slot)
+}
+
+// Doktor art arda en fazla Days gün nöbet tutabilir
+type MaxConsecutiveDays struct {
+	Days int
+}
+
+func (MaxConsecutiveDays) Name() string { return "max_consecutive_days" }
+
+func (c MaxConsecutiveDays) Allows(s *State, d Doctor, slot Slot) bool {
+	// Slotun iki yanında Days günden uzağa bakmak sonucu değiştirmez
+	day := dayNumber(slot.Date)
+	busy := make([]bool, 2*c.Days+1)
+	for _, other := range s.Shifts(d.ID) {
+		if offset := dayNumber(other.Date) - day + c.Days; offset >= 0 && offset < len(busy) {
+			busy[offset] = true
+		}
+	}
+
+	run := 1
+	for i := c.Days - 1; i >= 0 && busy[i]; i-- {
+		run++
+	}
+	for i := c.Days + 1; i < len(busy) && busy[i]; i++ {
+		run++
+	}
+	return run <= c.Days
+}
+
+// Doktor herhangi bir Days günlük kayan pencerede en fazla Shifts nöbet tutabilir
+type MaxShiftsPerWindow struct {
+	Shifts int
+	Days   int
+}
+
+func (MaxShiftsPerWindow) Name() string { return "max_shifts_per_week" }
+
+func (c MaxShiftsPerWindow) Allows(s *State, d Doctor, slot Slot) bool {
+	if c.Days <= 0 {
+		return true
+	}
+
+	// Slotu içeren pencereler slottan en fazla Days-1 gün uzağa uzanır
+	day := dayNumber(slot.Date)
+	counts := make([]int, 2*c.Days-1)
+	for _, other := range s.Shifts(d.ID) {
+		if offset := dayNumber(other.Date) - day + c.Days - 1; offset >= 0 && offset < len(counts) {
+			counts[offset]++
+		}
+	}
+	counts[c.Days-1]++
+
+	// Slotu içeren her pencereyi kontrol et
+	for start := 0; start < c.Days; start++ {
+		total := 0
+		for i := start; i < start+c.Days; i++ {
+			total += counts[i]
+		}
+		if total > c.Shifts {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,754 @@
+package scheduler
+
+import (
+	"sort"
+	"time"
+)
+
+// Doktorun motor tarafından bilinmesi gereken bilgileri
+type Doctor struct {
+	ID         int64
+	ShiftLimit int      // 0 veya negatif ise sınırsız
+	Periods    []Period // lokasyondaki üyelik dönemleri, boşsa her gün aktif
+}
+
+// Üyeliğin geçerli olduğu gün aralığı (iki uç dahil). Sıfır olan uç açıktır.
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// Doktorun verilen günde lokasyona üye olup olmadığını döner
+func (d Doctor) ActiveOn(day time.Time) bool {
+	if len(d.Periods) == 0 {
+		return true
+	}
+
+	key := dateKey(day)
+	for _, p := range d.Periods {
+		if (p.From.IsZero() || dateKey(p.From) <= key) && (p.To.IsZero() || key <= dateKey(p.To)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Günün türü, adalet hesabında ağırlık belirlemek için kullanılır
+type DayKind int
+
+const (
+	Weekday DayKind = iota
+	Weekend
+	PublicHoliday
+)
+
+// Doldurulması gereken tek bir nöbet
+type Slot struct {
+	Date     time.Time
+	Start    time.Time
+	End      time.Time
+	Kind     DayKind
+	Weight   float64 // adalet hesabındaki ağırlık, 0 ise 1 kabul edilir
+	Template int64   // slotun ait olduğu nöbet şablonu, 0 ise şablonsuz
+	Holiday  string  // resmi tatil günlerinde tatilin adı
+}
+
+func (s Slot) weight() float64 {
+	if s.Weight <= 0 {
+		return 1
+	}
+	return s.Weight
+}
+
+// Bir nöbetin bir doktora atanması
+type Assignment struct {
+	DoctorID int64
+	Slot     Slot
+}
+
+// Motorun girdisi
+type Input struct {
+	Doctors  []Doctor
+	Slots    []Slot
+	Holidays map[int64][]time.Time // doktor ID -> tatil günleri
+	Existing []Assignment          // veritabanında zaten bulunan nöbetler
+	History  map[int64]History     // doktor ID -> önceki aylardaki yük
+}
+
+// Doktorun önceki aylardaki nöbet yükü
+type History struct {
+	Shifts        int
+	Weighted      float64
+	Weekend       int
+	PublicHoliday int
+	Holidays      []string // önceki yıllarda nöbet tutulan resmi tatillerin adları
+}
+
+// Motorun çıktısı
+type Plan struct {
+	Assignments []Assignment
+	Relaxed     []RelaxedAssignment // ikinci turda kısıtlar gevşetilerek yapılan atamalar
+	Unassigned  []Unfilled
+	Score       float64 // amaç fonksiyonunun değeri, düşük olan daha iyi
+	Stats       []DoctorStat
+}
+
+// Kısıtlar gevşetilerek doldurulan slot
+type RelaxedAssignment struct {
+	Assignment
+	Step        int      // kaçıncı gevşetme adımında dolduğu
+	Relaxations []string // gevşetilen kısıtların yeni halleri
+}
+
+// Hiçbir gevşetme adımında doldurulamayan slot
+type Unfilled struct {
+	Slot     Slot
+	Blockers map[string]int // kısıt adı -> o kısıt yüzünden elenen doktor sayısı
+}
+
+// Doktorun plan sonundaki nöbet dağılımı
+type DoctorStat struct {
+	DoctorID          int64
+	Shifts            int
+	Weekend           int
+	PublicHoliday     int
+	Weighted          float64
+	Hours             float64 // nöbetlerin gerçek süreleri toplamı
+	History           History
+	Deviation         float64 // nöbet sayısının ortalamadan farkı
+	WeightedDeviation float64 // ağırlıklı yükün ortalamadan farkı
+}
+
+// Soft kısıtın amaç fonksiyonundaki ağırlığı
+type Weighted struct {
+	Constraint SoftConstraint
+	Weight     float64
+}
+
+type Engine struct {
+	hard          []HardConstraint
+	relaxable     []Relaxable
+	soft          []Weighted
+	maxIterations int
+	timeBudget    time.Duration // iyileştirme turlarının toplam süresi, 0 ise sınırsız
+}
+
+// Varsayılan kısıtlarla motor oluşturur
+func NewEngine() *Engine {
+	return &Engine{
+		hard: []HardConstraint{
+			ActiveMember{},
+			NotOnHoliday{},
+			NoDoubleBooking{},
+			WithinShiftLimit{},
+		},
+		relaxable: []Relaxable{
+			MinRestGap{Gap: 24 * time.Hour, Step: 12 * time.Hour},
+		},
+		soft: []Weighted{
+			{Constraint: Spacing{}, Weight: 1},
+			{Constraint: Fairness{HistoryWeight: 0.25}, Weight: 2},
+			{Constraint: HolidayRotation{}, Weight: 1},
+		},
+		maxIterations: 50,
+	}
+}
+
+// Yalnızca verilen hard kısıtlarla doğrulama için motor oluşturur
+func NewValidator(hard ...HardConstraint) *Engine {
+	return &Engine{hard: hard}
+}
+
+func (e *Engine) WithHard(c ...HardConstraint) *Engine {
+	e.hard = append(e.hard, c...)
+	return e
+}
+
+func (e *Engine) WithRelaxable(c ...Relaxable) *Engine {
+	e.relaxable = append(e.relaxable, c...)
+	return e
+}
+
+func (e *Engine) WithSoft(c SoftConstraint, weight float64) *Engine {
+	e.soft = append(e.soft, Weighted{Constraint: c, Weight: weight})
+	return e
+}
+
+// İyileştirme turlarını süreyle de sınırlar. Süre dolduğunda plan o ana kadar
+// yapılan tur sayısına bağlı olduğundan aynı girdi farklı sonuç verebilir.
+func (e *Engine) WithTimeBudget(d time.Duration) *Engine {
+	e.timeBudget = d
+	return e
+}
+
+// Girdiye göre tüm slotları doldurmaya çalışır.
+// Önce en az adayı olan slottan başlayarak açgözlü bir atama yapılır,
+// ardından amaç fonksiyonunu düşüren taşımalarla plan iyileştirilir.
+// İyileştirme en fazla maxIterations tur, süre sınırı verilmişse o süre boyunca yapılır.
+// Son olarak boş kalan slotlar gevşetilebilen kısıtlar adım adım
+// gevşetilerek limitinin altındaki doktorlara dağıtılır.
+func (e *Engine) Solve(in Input) Plan {
+	s := newState(in)
+	sc := newScorer(e, s)
+	c := e.newCandidates(s)
+
+	var deadline time.Time
+	if e.timeBudget > 0 {
+		deadline = time.Now().Add(e.timeBudget)
+	}
+
+	pending := make([]int, len(in.Slots))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	for len(pending) > 0 {
+		// En kısıtlı slotu seç
+		best := 0
+		for i, idx := range pending {
+			if n, bestCount := c.counts[idx], c.counts[pending[best]]; n < bestCount || (n == bestCount && in.Slots[idx].Start.Before(in.Slots[pending[best]].Start)) {
+				best = i
+			}
+		}
+
+		idx := pending[best]
+		pending = append(pending[:best], pending[best+1:]...)
+
+		if doctorID, ok := e.cheapest(s, sc, idx, c.of(idx)); ok {
+			s.assign(idx, doctorID)
+			sc.commit(doctorID)
+			c.refresh(doctorID, pending)
+		}
+	}
+
+	e.improve(s, sc, deadline)
+	relaxed := e.redistribute(s, sc)
+
+	p := s.plan(e.objective(s))
+	p.Relaxed = relaxed
+	p.Unassigned = e.unfilled(s)
+	return p
+}
+
+// Hard kısıtı ihlal eden atama
+type Violation struct {
+	Assignment
+	Constraint string
+}
+
+// Önerilen atamaları mevcut duruma karşı başlangıç sırasıyla hard kısıtlara göre doğrular.
+// Gevşetilebilen kısıtlar doğrulamaya dahil edilmez.
+func (e *Engine) Validate(in Input, proposed []Assignment) []Violation {
+	s := newState(in)
+
+	sorted := append([]Assignment(nil), proposed...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Slot.Start.Before(sorted[j].Slot.Start) })
+
+	doctors := make(map[int64]Doctor, len(s.doctors))
+	for _, d := range s.doctors {
+		doctors[d.ID] = d
+	}
+
+	var violations []Violation
+	for _, a := range sorted {
+		d, ok := doctors[a.DoctorID]
+		if !ok {
+			d = Doctor{ID: a.DoctorID}
+		}
+		if name := e.blockedBy(s, d, a.Slot, nil); name != "" {
+			violations = append(violations, Violation{Assignment: a, Constraint: name})
+		}
+		s.insert(a.DoctorID, a.Slot)
+	}
+	return violations
+}
+
+// Açgözlü atama sırasında slotlara hard kısıtları ihlal etmeden atanabilecek doktorlar.
+// Hard kısıtlar yalnızca doktorun kendi nöbetlerine baktığından bir atamadan sonra
+// sadece atanan doktorun adaylıkları yeniden hesaplanır.
+type candidates struct {
+	e       *Engine
+	s       *State
+	allowed [][]bool // slot indeksi -> doktor indeksi
+	counts  []int    // slot indeksi -> aday sayısı
+}
+
+func (e *Engine) newCandidates(s *State) *candidates {
+	c := &candidates{
+		e:       e,
+		s:       s,
+		allowed: make([][]bool, len(s.slots)),
+		counts:  make([]int, len(s.slots)),
+	}
+	for idx, slot := range s.slots {
+		c.allowed[idx] = make([]bool, len(s.doctors))
+		for i, d := range s.doctors {
+			if e.allowed(s, d, slot) {
+				c.allowed[idx][i] = true
+				c.counts[idx]++
+			}
+		}
+	}
+	return c
+}
+
+// Slotun adayları, ID sırasında
+func (c *candidates) of(idx int) []Doctor {
+	var result []Doctor
+	for i, d := range c.s.doctors {
+		if c.allowed[idx][i] {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// Doktorun bekleyen slotlardaki adaylığını yeniden hesaplar
+func (c *candidates) refresh(doctorID int64, pending []int) {
+	i, ok := c.s.doctorIndex(doctorID)
+	if !ok {
+		return
+	}
+
+	d := c.s.doctors[i]
+	for _, idx := range pending {
+		allowed := c.e.allowed(c.s, d, c.s.slots[idx])
+		if allowed == c.allowed[idx][i] {
+			continue
+		}
+		c.allowed[idx][i] = allowed
+		if allowed {
+			c.counts[idx]++
+		} else {
+			c.counts[idx]--
+		}
+	}
+}
+
+func (e *Engine) allowed(s *State, d Doctor, slot Slot) bool {
+	return e.blockedBy(s, d, slot, e.relaxable) == ""
+}
+
+// Doktoru slota atamaya engel olan ilk kısıtın adını döner, engel yoksa boş döner
+func (e *Engine) blockedBy(s *State, d Doctor, slot Slot, relaxable []Relaxable) string {
+	for _, c := range e.hard {
+		if !c.Allows(s, d, slot) {
+			return c.Name()
+		}
+	}
+	for _, c := range relaxable {
+		if !c.Allows(s, d, slot) {
+			return c.Name()
+		}
+	}
+	return ""
+}
+
+// Gevşetme adımları. Her adımda sıradaki gevşetilebilen kısıt bir kez gevşetilir,
+// bir kısıt tamamen gevşetilmeden sonrakine geçilmez.
+func (e *Engine) relaxationSteps() [][]Relaxable {
+	var steps [][]Relaxable
+	current := append([]Relaxable(nil), e.relaxable...)
+	for {
+		relaxed := false
+		for i, c := range current {
+			if next, ok := c.Relax(); ok {
+				current = append([]Relaxable(nil), current...)
+				current[i] = next
+				relaxed = true
+				break
+			}
+		}
+		if !relaxed {
+			return steps
+		}
+		steps = append(steps, current)
+	}
+}
+
+// Boş kalan slotları kısıtları adım adım gevşeterek doldurur
+func (e *Engine) redistribute(s *State, sc *scorer) []RelaxedAssignment {
+	var result []RelaxedAssignment
+	steps := e.relaxationSteps()
+
+	for _, idx := range s.unassignedByStart() {
+		for step, relaxable := range steps {
+			var candidates []Doctor
+			for _, d := range s.doctors {
+				if e.blockedBy(s, d, s.slots[idx], relaxable) == "" {
+					candidates = append(candidates, d)
+				}
+			}
+
+			doctorID, ok := e.cheapest(s, sc, idx, candidates)
+			if !ok {
+				continue
+			}
+
+			s.assign(idx, doctorID)
+			sc.commit(doctorID)
+			ra := RelaxedAssignment{
+				Assignment: Assignment{DoctorID: doctorID, Slot: s.slots[idx]},
+				Step:       step + 1,
+			}
+			for i, c := range relaxable {
+				if c.String() != e.relaxable[i].String() {
+					ra.Relaxations = append(ra.Relaxations, c.String())
+				}
+			}
+			result = append(result, ra)
+			break
+		}
+	}
+	return result
+}
+
+// Hâlâ boş olan slotları, en gevşek halde bile doktorları eleyen kısıtlarla birlikte döner
+func (e *Engine) unfilled(s *State) []Unfilled {
+	loosest := e.relaxable
+	if steps := e.relaxationSteps(); len(steps) > 0 {
+		loosest = steps[len(steps)-1]
+	}
+
+	var result []Unfilled
+	for _, idx := range s.unassignedByStart() {
+		u := Unfilled{Slot: s.slots[idx], Blockers: make(map[string]int)}
+		for _, d := range s.doctors {
+			if name := e.blockedBy(s, d, s.slots[idx], loosest); name != "" {
+				u.Blockers[name]++
+			}
+		}
+		result = append(result, u)
+	}
+	return result
+}
+
+// Amaç fonksiyonunu en az artıran adayı döner. Adaylar ID sırasında olduğundan
+// eşit maliyette küçük ID'li doktor seçilir.
+func (e *Engine) cheapest(s *State, sc *scorer, idx int, candidates []Doctor) (int64, bool) {
+	if len(candidates) == 0 {
+		return 0, false
+	}
+
+	before := sc.score()
+	var bestID int64
+	bestCost := 0.0
+	for i, d := range candidates {
+		s.assign(idx, d.ID)
+		cost := sc.score(d.ID) - before
+		s.unassign(idx)
+
+		if i == 0 || cost < bestCost-1e-9 {
+			bestID, bestCost = d.ID, cost
+		}
+	}
+	return bestID, true
+}
+
+// Atanmış slotları başka doktorlara taşıyarak amaç fonksiyonunu düşürmeye çalışır.
+// Süre dolduğunda o ana kadarki en iyi planla çıkılır.
+func (e *Engine) improve(s *State, sc *scorer, deadline time.Time) {
+	for iter := 0; iter < e.maxIterations; iter++ {
+		improved := false
+		for idx := range s.slots {
+			if expired(deadline) {
+				return
+			}
+
+			current := s.assigned[idx]
+			if current == 0 {
+				continue
+			}
+
+			before := sc.score()
+			s.unassign(idx)
+
+			bestID, bestScore := current, before
+			for _, d := range s.doctors {
+				if d.ID == current || !e.allowed(s, d, s.slots[idx]) {
+					continue
+				}
+				s.assign(idx, d.ID)
+				if score := sc.score(current, d.ID); score < bestScore-1e-9 {
+					bestID, bestScore = d.ID, score
+				}
+				s.unassign(idx)
+			}
+
+			s.assign(idx, bestID)
+			if bestID != current {
+				sc.commit(current, bestID)
+				improved = true
+			}
+		}
+		if !improved && !e.swap(s, sc, deadline) {
+			return
+		}
+	}
+}
+
+// İki doktorun nöbetlerini karşılıklı değiştirerek amaç fonksiyonunu düşürmeye çalışır.
+// Tek taşımaların nöbet sayılarını bozduğu durumlarda (ör. gün tercihleri) işe yarar.
+func (e *Engine) swap(s *State, sc *scorer, deadline time.Time) bool {
+	improved := false
+	score := sc.score()
+	for i := range s.slots {
+		if expired(deadline) {
+			return improved
+		}
+		for j := i + 1; j < len(s.slots); j++ {
+			a, b := s.assigned[i], s.assigned[j]
+			if a == 0 || b == 0 || a == b || s.slots[i] == s.slots[j] {
+				continue
+			}
+
+			s.unassign(i)
+			s.unassign(j)
+
+			ok := e.allowed(s, s.doctor(b), s.slots[i])
+			if ok {
+				s.assign(i, b)
+				ok = e.allowed(s, s.doctor(a), s.slots[j])
+				if ok {
+					s.assign(j, a)
+					if next := sc.score(a, b); next < score-1e-9 {
+						sc.commit(a, b)
+						score = sc.score()
+						improved = true
+						continue
+					}
+					s.unassign(j)
+				}
+				s.unassign(i)
+			}
+
+			s.assign(i, a)
+			s.assign(j, b)
+		}
+	}
+	return improved
+}
+
+func expired(deadline time.Time) bool {
+	return !deadline.IsZero() && time.Now().After(deadline)
+}
+
+func (e *Engine) objective(s *State) float64 {
+	total := 0.0
+	for _, w := range e.soft {
+		total += w.Weight * w.Constraint.Penalty(s)
+	}
+	return total
+}
+
+// Planlama sırasında atamaların tutulduğu yapı
+type State struct {
+	doctors  []Doctor
+	slots    []Slot
+	assigned []int64 // slot index -> doktor ID, 0 ise boş
+	holidays map[int64]map[string]bool
+	shifts   map[int64][]Slot // doktor ID -> mevcut ve planlanmış nöbetler, başlangıca göre sıralı
+	history  map[int64]History
+	from, to string // planlanan dönemin ilk ve son günü, boşsa sınır yok
+}
+
+func newState(in Input) *State {
+	s := &State{
+		doctors:  append([]Doctor(nil), in.Doctors...),
+		slots:    append([]Slot(nil), in.Slots...),
+		assigned: make([]int64, len(in.Slots)),
+		holidays: make(map[int64]map[string]bool),
+		shifts:   make(map[int64][]Slot),
+		history:  in.History,
+	}
+
+	sort.Slice(s.doctors, func(i, j int) bool { return s.doctors[i].ID < s.doctors[j].ID })
+
+	for _, slot := range in.Slots {
+		key := dateKey(slot.Date)
+		if s.from == "" || key < s.from {
+			s.from = key
+		}
+		if key > s.to {
+			s.to = key
+		}
+	}
+
+	for doctorID, days := range in.Holidays {
+		s.holidays[doctorID] = make(map[string]bool, len(days))
+		for _, day := range days {
+			s.holidays[doctorID][dateKey(day)] = true
+		}
+	}
+
+	for _, a := range in.Existing {
+		s.insert(a.DoctorID, a.Slot)
+	}
+
+	return s
+}
+
+func (s *State) assign(idx int, doctorID int64) {
+	s.assigned[idx] = doctorID
+	s.insert(doctorID, s.slots[idx])
+}
+
+func (s *State) unassign(idx int) {
+	doctorID := s.assigned[idx]
+	s.assigned[idx] = 0
+
+	list := s.shifts[doctorID]
+	for i, slot := range list {
+		if slot == s.slots[idx] {
+			s.shifts[doctorID] = append(list[:i], list[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *State) insert(doctorID int64, slot Slot) {
+	list := s.shifts[doctorID]
+	i := sort.Search(len(list), func(i int) bool { return slot.Start.Before(list[i].Start) })
+	list = append(list, Slot{})
+	copy(list[i+1:], list[i:])
+	list[i] = slot
+	s.shifts[doctorID] = list
+}
+
+func (s *State) Doctors() []Doctor { return s.doctors }
+
+func (s *State) doctor(id int64) Doctor {
+	if i, ok := s.doctorIndex(id); ok {
+		return s.doctors[i]
+	}
+	return Doctor{ID: id}
+}
+
+// Doktorun ID'ye göre sıralı doktor listesindeki yeri
+func (s *State) doctorIndex(id int64) (int, bool) {
+	i := sort.Search(len(s.doctors), func(i int) bool { return s.doctors[i].ID >= id })
+	return i, i < len(s.doctors) && s.doctors[i].ID == id
+}
+
+// Doktorun tatilde olup olmadığını döner
+func (s *State) OnHoliday(doctorID int64, day time.Time) bool {
+	return s.holidays[doctorID][dateKey(day)]
+}
+
+// Doktorun mevcut ve planlanmış tüm nöbetleri, başlangıca göre sıralı.
+// Dönen dilim değiştirilmemelidir.
+func (s *State) Shifts(doctorID int64) []Slot {
+	return s.shifts[doctorID]
+}
+
+// Doktorun planlanan dönem içindeki mevcut ve planlanmış nöbetleri.
+// Dönem dışındaki nöbetler yalnızca dinlenme kuralları için tutulur.
+func (s *State) InHorizon(doctorID int64) []Slot {
+	if s.from == "" {
+		return s.shifts[doctorID]
+	}
+
+	var result []Slot
+	for _, slot := range s.shifts[doctorID] {
+		if key := dateKey(slot.Date); key >= s.from && key <= s.to {
+			result = append(result, slot)
+		}
+	}
+	return result
+}
+
+// Doktorun planlanan dönem içindeki nöbet sayısı
+func (s *State) Count(doctorID int64) int {
+	return len(s.InHorizon(doctorID))
+}
+
+// Doktorun verilen tarihle aynı takvim ayındaki nöbet sayısı
+func (s *State) CountInMonth(doctorID int64, day time.Time) int {
+	n := 0
+	for _, slot := range s.shifts[doctorID] {
+		if slot.Date.Year() == day.Year() && slot.Date.Month() == day.Month() {
+			n++
+		}
+	}
+	return n
+}
+
+// Doktorun planlanan dönem içindeki nöbetlerinin ağırlıklı toplamı
+func (s *State) WeightedLoad(doctorID int64) float64 {
+	total := 0.0
+	for _, slot := range s.InHorizon(doctorID) {
+		total += slot.weight()
+	}
+	return total
+}
+
+// Doktorun önceki aylardaki yükü
+func (s *State) History(doctorID int64) History {
+	return s.history[doctorID]
+}
+
+func (s *State) plan(score float64) Plan {
+	p := Plan{Score: score, Stats: s.stats()}
+	for idx, doctorID := range s.assigned {
+		if doctorID != 0 {
+			p.Assignments = append(p.Assignments, Assignment{DoctorID: doctorID, Slot: s.slots[idx]})
+		}
+	}
+	return p
+}
+
+// Boş slotların indeksleri, başlangıç zamanına göre sıralı
+func (s *State) unassignedByStart() []int {
+	var result []int
+	for idx, doctorID := range s.assigned {
+		if doctorID == 0 {
+			result = append(result, idx)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return s.slots[result[i]].Start.Before(s.slots[result[j]].Start) })
+	return result
+}
+
+func (s *State) stats() []DoctorStat {
+	if len(s.doctors) == 0 {
+		return nil
+	}
+
+	stats := make([]DoctorStat, len(s.doctors))
+	mean, weightedMean := 0.0, 0.0
+	for i, d := range s.doctors {
+		stat := DoctorStat{DoctorID: d.ID, History: s.History(d.ID)}
+		for _, slot := range s.InHorizon(d.ID) {
+			stat.Shifts++
+			stat.Weighted += slot.weight()
+			stat.Hours += slot.End.Sub(slot.Start).Hours()
+			switch slot.Kind {
+			case Weekend:
+				stat.Weekend++
+			case PublicHoliday:
+				stat.PublicHoliday++
+			}
+		}
+		mean += float64(stat.Shifts)
+		weightedMean += stat.Weighted
+		stats[i] = stat
+	}
+	mean /= float64(len(stats))
+	weightedMean /= float64(len(stats))
+
+	for i := range stats {
+		stats[i].Deviation = float64(stats[i].Shifts) - mean
+		stats[i].WeightedDeviation = stats[i].Weighted - weightedMean
+	}
+	return stats
+}
+
+func dateKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// Tarihin takvim günü, dateKey ile aynı günler aynı sayıyı alır
+func dayNumber(t time.Time) int {
+	y, m, d := t.Date()
+	return int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
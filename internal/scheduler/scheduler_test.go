@@ -0,0 +1,443 @@
+package scheduler_test
+
+import (
+	"shift-scheduling-v2/internal/scheduler"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func monthSlots(year int, month time.Month) []scheduler.Slot {
+	var slots []scheduler.Slot
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	for day := start; day.Month() == month; day = day.AddDate(0, 0, 1) {
+		begin := day.Add(8 * time.Hour)
+		slots = append(slots, scheduler.Slot{Date: day, Start: begin, End: begin.Add(24 * time.Hour)})
+	}
+	return slots
+}
+
+func TestSchedulerFillsMonth(t *testing.T) {
+	slots := monthSlots(2025, time.April)
+	input := scheduler.Input{
+		Doctors: []scheduler.Doctor{
+			{ID: 1, ShiftLimit: 10},
+			{ID: 2, ShiftLimit: 10},
+			{ID: 3, ShiftLimit: 10},
+		},
+		Slots: slots,
+	}
+
+	plan := scheduler.NewEngine().Solve(input)
+
+	assert.Empty(t, plan.Unassigned)
+	assert.Len(t, plan.Assignments, len(slots))
+
+	counts := make(map[int64]int)
+	for _, a := range plan.Assignments {
+		counts[a.DoctorID]++
+	}
+	for _, n := range counts {
+		assert.Equal(t, 10, n)
+	}
+}
+
+func TestSchedulerRespectsHardConstraints(t *testing.T) {
+	slots := monthSlots(2025, time.April)
+	holiday := time.Date(2025, time.April, 10, 0, 0, 0, 0, time.UTC)
+	existing := slots[0]
+
+	input := scheduler.Input{
+		Doctors: []scheduler.Doctor{
+			{ID: 1, ShiftLimit: 5},
+			{ID: 2, ShiftLimit: 30},
+		},
+		Slots:    slots[1:],
+		Holidays: map[int64][]time.Time{2: {holiday}},
+		Existing: []scheduler.Assignment{{DoctorID: 1, Slot: existing}},
+	}
+
+	plan := scheduler.NewEngine().Solve(input)
+
+	doctor1 := 0
+	for _, a := range plan.Assignments {
+		if a.DoctorID == 1 {
+			doctor1++
+		}
+		if a.DoctorID == 2 {
+			assert.False(t, a.Slot.Date.Equal(holiday), "tatil gününe nöbet atanmamalı")
+		}
+	}
+	assert.LessOrEqual(t, doctor1, 4, "mevcut nöbet limite dahil edilmeli")
+}
+
+func TestSchedulerReportsUnassignedSlots(t *testing.T) {
+	slots := monthSlots(2025, time.February)
+	input := scheduler.Input{
+		Doctors: []scheduler.Doctor{{ID: 1, ShiftLimit: 3}},
+		Slots:   slots,
+	}
+
+	plan := scheduler.NewEngine().Solve(input)
+
+	assert.Len(t, plan.Assignments, 3)
+	assert.Len(t, plan.Unassigned, len(slots)-3)
+}
+
+func TestSchedulerBalancesWithHistory(t *testing.T) {
+	slots := monthSlots(2025, time.April)
+	input := scheduler.Input{
+		Doctors: []scheduler.Doctor{
+			{ID: 1, ShiftLimit: 15},
+			{ID: 2, ShiftLimit: 15},
+			{ID: 3, ShiftLimit: 15},
+		},
+		Slots: slots,
+		History: map[int64]scheduler.History{
+			1: {Shifts: 40, Weighted: 40},
+			2: {Shifts: 20, Weighted: 20},
+			3: {Shifts: 20, Weighted: 20},
+		},
+	}
+
+	plan := scheduler.NewEngine().Solve(input)
+
+	stats := make(map[int64]scheduler.DoctorStat)
+	for _, stat := range plan.Stats {
+		stats[stat.DoctorID] = stat
+	}
+	assert.Less(t, stats[1].Shifts, stats[2].Shifts, "geçmişte fazla nöbet tutan doktor daha az nöbet almalı")
+	assert.Equal(t, len(slots), stats[1].Shifts+stats[2].Shifts+stats[3].Shifts)
+}
+
+func TestSchedulerSpreadsWeightedDays(t *testing.T) {
+	slots := monthSlots(2025, time.April)
+	for i := range slots {
+		if wd := slots[i].Date.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			slots[i].Kind = scheduler.Weekend
+			slots[i].Weight = 1.5
+		}
+	}
+
+	input := scheduler.Input{
+		Doctors: []scheduler.Doctor{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}},
+		Slots:   slots,
+	}
+
+	plan := scheduler.NewEngine().Solve(input)
+
+	minWeekend, maxWeekend := len(slots), 0
+	for _, stat := range plan.Stats {
+		minWeekend = min(minWeekend, stat.Weekend)
+		maxWeekend = max(maxWeekend, stat.Weekend)
+		assert.InDelta(t, 0, stat.WeightedDeviation, 1.5)
+	}
+	assert.LessOrEqual(t, maxWeekend-minWeekend, 1)
+}
+
+func TestSchedulerRelaxesRestGapForUncoveredDays(t *testing.T) {
+	slots := monthSlots(2025, time.April)
+	input := scheduler.Input{
+		Doctors: []scheduler.Doctor{
+			{ID: 1, ShiftLimit: 16},
+			{ID: 2, ShiftLimit: 16},
+		},
+		Slots: slots,
+		Holidays: map[int64][]time.Time{
+			2: {
+				time.Date(2025, time.April, 10, 0, 0, 0, 0, time.UTC),
+				time.Date(2025, time.April, 11, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	plan := scheduler.NewEngine().Solve(input)
+
+	assert.Empty(t, plan.Unassigned)
+	assert.NotEmpty(t, plan.Relaxed, "art arda nöbet gerektiren günler gevşetilerek doldurulmalı")
+	for _, r := range plan.Relaxed {
+		assert.NotEmpty(t, r.Relaxations)
+	}
+}
+
+func TestSchedulerReportsBlockersForUncoverableDays(t *testing.T) {
+	day := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	begin := day.Add(8 * time.Hour)
+	input := scheduler.Input{
+		Doctors:  []scheduler.Doctor{{ID: 1}, {ID: 2}},
+		Slots:    []scheduler.Slot{{Date: day, Start: begin, End: begin.Add(24 * time.Hour)}},
+		Holidays: map[int64][]time.Time{1: {day}, 2: {day}},
+	}
+
+	plan := scheduler.NewEngine().Solve(input)
+
+	assert.Len(t, plan.Unassigned, 1)
+	assert.Equal(t, 2, plan.Unassigned[0].Blockers["not_on_holiday"])
+}
+
+func TestSchedulerValidateDetectsConflicts(t *testing.T) {
+	slots := monthSlots(2025, time.April)
+	input := scheduler.Input{
+		Doctors:  []scheduler.Doctor{{ID: 1, ShiftLimit: 4}},
+		Holidays: map[int64][]time.Time{1: {slots[3].Date}},
+		Existing: []scheduler.Assignment{{DoctorID: 1, Slot: slots[0]}},
+	}
+
+	proposed := []scheduler.Assignment{
+		{DoctorID: 1, Slot: slots[0]},
+		{DoctorID: 1, Slot: slots[3]},
+		{DoctorID: 1, Slot: slots[6]},
+		{DoctorID: 1, Slot: slots[9]},
+	}
+
+	violations := scheduler.NewEngine().Validate(input, proposed)
+
+	names := make([]string, len(violations))
+	for i, v := range violations {
+		names[i] = v.Constraint
+	}
+	assert.Equal(t, []string{"no_double_booking", "not_on_holiday", "within_shift_limit"}, names)
+}
+
+func TestSchedulerAppliesRestRules(t *testing.T) {
+	slots := monthSlots(2025, time.April)
+	input := scheduler.Input{
+		Doctors: []scheduler.Doctor{{ID: 1}, {ID: 2}, {ID: 3}},
+		Slots:   slots,
+	}
+	rules := scheduler.RestRules{MinRestHours: 24, MaxConsecutiveDays: 1, MaxShiftsPerWeek: 3}
+
+	plan := scheduler.NewEngine().WithHard(rules.Constraints()...).Solve(input)
+
+	assert.Empty(t, plan.Unassigned)
+	violations := scheduler.NewValidator(rules.Constraints()...).Validate(scheduler.Input{Doctors: input.Doctors}, plan.Assignments)
+	assert.Empty(t, violations)
+}
+
+func TestRestRulesNameViolatedRule(t *testing.T) {
+	slots := monthSlots(2025, time.April)
+	input := scheduler.Input{
+		Doctors:  []scheduler.Doctor{{ID: 1}},
+		Existing: []scheduler.Assignment{{DoctorID: 1, Slot: slots[0]}, {DoctorID: 1, Slot: slots[2]}, {DoctorID: 1, Slot: slots[4]}},
+	}
+
+	validate := func(rules scheduler.RestRules, slot scheduler.Slot) []string {
+		var names []string
+		for _, v := range scheduler.NewValidator(rules.Constraints()...).Validate(input, []scheduler.Assignment{{DoctorID: 1, Slot: slot}}) {
+			names = append(names, v.Constraint)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"min_rest_hours"}, validate(scheduler.RestRules{MinRestHours: 12}, slots[1]))
+	assert.Equal(t, []string{"max_consecutive_days"}, validate(scheduler.RestRules{MaxConsecutiveDays: 1}, slots[1]))
+	assert.Equal(t, []string{"max_shifts_per_week"}, validate(scheduler.RestRules{MaxShiftsPerWeek: 3}, slots[6]))
+	assert.Empty(t, validate(scheduler.RestRules{MaxShiftsPerWeek: 3}, slots[8]))
+}
+
+func TestSchedulerFillsMultipleSlotsPerDay(t *testing.T) {
+	var slots []scheduler.Slot
+	for _, slot := range monthSlots(2025, time.April) {
+		required := 3
+		if wd := slot.Date.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			required = 4
+		}
+		for i := 0; i < required; i++ {
+			slots = append(slots, slot)
+		}
+	}
+
+	doctors := make([]scheduler.Doctor, 10)
+	for i := range doctors {
+		doctors[i] = scheduler.Doctor{ID: int64(i + 1)}
+	}
+
+	plan := scheduler.NewEngine().Solve(scheduler.Input{Doctors: doctors, Slots: slots})
+
+	assert.Empty(t, plan.Unassigned)
+	assert.Len(t, plan.Assignments, len(slots))
+
+	perDay := make(map[string]map[int64]bool)
+	for _, a := range plan.Assignments {
+		key := a.Slot.Date.Format("2006-01-02")
+		if perDay[key] == nil {
+			perDay[key] = make(map[int64]bool)
+		}
+		assert.False(t, perDay[key][a.DoctorID], "aynı doktor aynı güne iki kez atanmamalı")
+		perDay[key][a.DoctorID] = true
+	}
+}
+
+func TestSchedulerFillsTemplateSlotsAndSumsHours(t *testing.T) {
+	var slots []scheduler.Slot
+	for _, day := range monthSlots(2025, time.April)[:7] {
+		dayStart := day.Date.Add(8 * time.Hour)
+		nightStart := day.Date.Add(16 * time.Hour)
+		slots = append(slots,
+			scheduler.Slot{Date: day.Date, Start: dayStart, End: dayStart.Add(8 * time.Hour), Template: 1},
+			scheduler.Slot{Date: day.Date, Start: nightStart, End: nightStart.Add(16 * time.Hour), Template: 2},
+		)
+	}
+
+	doctors := []scheduler.Doctor{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	plan := scheduler.NewEngine().Solve(scheduler.Input{Doctors: doctors, Slots: slots})
+
+	assert.Empty(t, plan.Unassigned)
+
+	perTemplate := make(map[int64]int)
+	for _, a := range plan.Assignments {
+		perTemplate[a.Slot.Template]++
+	}
+	assert.Equal(t, map[int64]int{1: 7, 2: 7}, perTemplate)
+
+	hours := 0.0
+	for _, stat := range plan.Stats {
+		hours += stat.Hours
+	}
+	assert.InDelta(t, 7*(8+16), hours, 1e-9, "gece yarısını geçen nöbetler gerçek süreleriyle sayılmalı")
+}
+
+func TestSchedulerHonoursPreferences(t *testing.T) {
+	slots := monthSlots(2025, time.April)
+	avoid := []time.Time{
+		time.Date(2025, time.April, 14, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.April, 15, 0, 0, 0, 0, time.UTC),
+	}
+	preferences := scheduler.Preferences{ByDoctor: map[int64]scheduler.Preference{
+		1: {Avoid: avoid},
+		2: {Weekdays: []time.Weekday{time.Friday}, Weight: 2},
+	}}
+
+	input := scheduler.Input{
+		Doctors: []scheduler.Doctor{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}},
+		Slots:   slots,
+	}
+	plan := scheduler.NewEngine().WithSoft(preferences, 1).Solve(input)
+
+	assert.Empty(t, plan.Unassigned)
+	fridays := 0
+	for _, a := range plan.Assignments {
+		if a.DoctorID == 1 {
+			for _, day := range avoid {
+				assert.False(t, a.Slot.Date.Equal(day), "kaçınılan güne nöbet atanmamalı")
+			}
+		}
+		if a.DoctorID == 2 && a.Slot.Date.Weekday() == time.Friday {
+			fridays++
+		}
+	}
+	assert.Equal(t, 4, fridays, "tercih edilen günlerin hepsi doktora verilmeli")
+
+	report := preferences.Report(plan.Assignments)
+	assert.Equal(t, scheduler.PreferenceStat{Requested: 2, Satisfied: 2}, report[1])
+	assert.Equal(t, 4, report[2].Satisfied)
+	_, ok := report[3]
+	assert.False(t, ok)
+}
+
+func TestSchedulerRotatesPublicHolidaysAcrossYears(t *testing.T) {
+	slots := monthSlots(2025, time.April)
+	holiday := time.Date(2025, time.April, 23, 0, 0, 0, 0, time.UTC)
+	for i := range slots {
+		if slots[i].Date.Equal(holiday) {
+			slots[i].Kind = scheduler.PublicHoliday
+			slots[i].Weight = 2
+			slots[i].Holiday = "Ulusal Egemenlik ve Çocuk Bayramı"
+		}
+	}
+
+	input := scheduler.Input{
+		Doctors: []scheduler.Doctor{{ID: 1}, {ID: 2}, {ID: 3}},
+		Slots:   slots,
+		History: map[int64]scheduler.History{
+			1: {PublicHoliday: 1, Holidays: []string{"Cumhuriyet Bayramı"}},
+			2: {PublicHoliday: 1, Holidays: []string{"Ulusal Egemenlik ve Çocuk Bayramı"}},
+			3: {PublicHoliday: 1, Holidays: []string{"Zafer Bayramı"}},
+		},
+	}
+
+	plan := scheduler.NewEngine().Solve(input)
+
+	for _, a := range plan.Assignments {
+		if a.Slot.Date.Equal(holiday) {
+			assert.NotEqual(t, int64(2), a.DoctorID, "geçen yıl aynı bayramda nöbet tutan doktor tekrar atanmamalı")
+		}
+	}
+}
+
+func TestSchedulerOnlyAssignsActiveMembers(t *testing.T) {
+	slots := monthSlots(2025, time.April)
+	joined := time.Date(2025, time.April, 16, 0, 0, 0, 0, time.UTC)
+	left := time.Date(2025, time.April, 10, 0, 0, 0, 0, time.UTC)
+
+	input := scheduler.Input{
+		Doctors: []scheduler.Doctor{
+			{ID: 1},
+			{ID: 2, Periods: []scheduler.Period{{From: joined}}},
+			{ID: 3, Periods: []scheduler.Period{{To: left}}},
+		},
+		Slots: slots,
+	}
+
+	plan := scheduler.NewEngine().Solve(input)
+
+	assert.Empty(t, plan.Unassigned)
+	for _, a := range plan.Assignments {
+		switch a.DoctorID {
+		case 2:
+			assert.False(t, a.Slot.Date.Before(joined), "üyelik başlamadan nöbet atanmamalı")
+		case 3:
+			assert.False(t, a.Slot.Date.After(left), "üyelik bittikten sonra nöbet atanmamalı")
+		}
+	}
+}
+
+func TestSchedulerIsDeterministic(t *testing.T) {
+	var slots []scheduler.Slot
+	for _, slot := range monthSlots(2025, time.May) {
+		slots = append(slots, slot, slot)
+	}
+	doctors := make([]scheduler.Doctor, 8)
+	for i := range doctors {
+		doctors[i] = scheduler.Doctor{ID: int64(i + 1), ShiftLimit: 9}
+	}
+	input := scheduler.Input{Doctors: doctors, Slots: slots}
+
+	first := scheduler.NewEngine().Solve(input)
+	second := scheduler.NewEngine().Solve(input)
+
+	assert.Equal(t, first.Assignments, second.Assignments)
+	assert.Equal(t, first.Score, second.Score)
+}
+
+// Günde perDay slotlu bir ayı doctors doktorla, servisin kullandığı kural ve tercihlerle planlar
+func benchmarkSolve(b *testing.B, doctors int, perDay int) {
+	var slots []scheduler.Slot
+	for _, slot := range monthSlots(2025, time.May) {
+		for i := 0; i < perDay; i++ {
+			slots = append(slots, slot)
+		}
+	}
+
+	input := scheduler.Input{Slots: slots, History: make(map[int64]scheduler.History)}
+	preferences := scheduler.Preferences{ByDoctor: make(map[int64]scheduler.Preference)}
+	for i := 0; i < doctors; i++ {
+		id := int64(i + 1)
+		input.Doctors = append(input.Doctors, scheduler.Doctor{ID: id, ShiftLimit: 7})
+		input.History[id] = scheduler.History{Shifts: i % 4, Weighted: float64(i % 4)}
+		if i%3 == 0 {
+			preferences.ByDoctor[id] = scheduler.Preference{Weekdays: []time.Weekday{time.Monday, time.Wednesday, time.Friday}}
+		}
+	}
+	rules := scheduler.RestRules{MaxConsecutiveDays: 2, MaxShiftsPerWeek: 3}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scheduler.NewEngine().WithHard(rules.Constraints()...).WithSoft(preferences, 1).Solve(input)
+	}
+}
+
+func BenchmarkSolve30Doctors124Slots(b *testing.B) { benchmarkSolve(b, 30, 4) }
+
+func BenchmarkSolve40Doctors186Slots(b *testing.B) { benchmarkSolve(b, 40, 6) }
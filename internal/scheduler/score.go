@@ -0,0 +1,94 @@
+package scheduler
+
+// Amaç fonksiyonunu doktor bazında önbelleğe alır. Bir taşıma yalnızca nöbetleri değişen
+// doktorların katkıları yeniden hesaplanarak puanlanır. Ayrıştırılamayan soft kısıtlar
+// her seferinde planın tamamı üzerinden hesaplanır.
+type scorer struct {
+	e       *Engine
+	s       *State
+	terms   [][][]float64 // soft kısıt -> doktor indeksi -> katkı
+	sums    [][]float64   // soft kısıt -> katkıların toplamı
+	scratch [][]float64   // puanlama sırasında değiştirilen toplamlar
+}
+
+func newScorer(e *Engine, s *State) *scorer {
+	sc := &scorer{
+		e:       e,
+		s:       s,
+		terms:   make([][][]float64, len(e.soft)),
+		sums:    make([][]float64, len(e.soft)),
+		scratch: make([][]float64, len(e.soft)),
+	}
+
+	for k, w := range e.soft {
+		c, ok := w.Constraint.(Decomposable)
+		if !ok {
+			continue
+		}
+		sc.terms[k] = make([][]float64, len(s.doctors))
+		for i, d := range s.doctors {
+			sc.terms[k][i] = c.Terms(s, d)
+		}
+		sc.resum(k)
+	}
+	return sc
+}
+
+// Son commit'ten bu yana yalnızca verilen doktorların nöbetleri değişmiş kabul edilerek
+// mevcut durumun puanı. Aynı doktor iki kez verilmemelidir.
+func (sc *scorer) score(changed ...int64) float64 {
+	total := 0.0
+	for k, w := range sc.e.soft {
+		c, ok := w.Constraint.(Decomposable)
+		if !ok {
+			total += w.Weight * w.Constraint.Penalty(sc.s)
+			continue
+		}
+
+		sums := append(sc.scratch[k][:0], sc.sums[k]...)
+		for _, id := range changed {
+			i, ok := sc.s.doctorIndex(id)
+			if !ok {
+				continue
+			}
+			for j, v := range c.Terms(sc.s, sc.s.doctors[i]) {
+				sums[j] += v - sc.terms[k][i][j]
+			}
+		}
+		sc.scratch[k] = sums
+		if len(sums) > 0 {
+			total += w.Weight * c.Combine(sums, len(sc.s.doctors))
+		}
+	}
+	return total
+}
+
+// Verilen doktorların katkılarını mevcut duruma göre günceller
+func (sc *scorer) commit(changed ...int64) {
+	for k, w := range sc.e.soft {
+		c, ok := w.Constraint.(Decomposable)
+		if !ok {
+			continue
+		}
+		for _, id := range changed {
+			if i, ok := sc.s.doctorIndex(id); ok {
+				sc.terms[k][i] = c.Terms(sc.s, sc.s.doctors[i])
+			}
+		}
+		sc.resum(k)
+	}
+}
+
+// Toplamlar yuvarlama hatası birikmemesi için katkılardan baştan hesaplanır
+func (sc *scorer) resum(k int) {
+	sums := sc.sums[k][:0]
+	for _, terms := range sc.terms[k] {
+		for len(sums) < len(terms) {
+			sums = append(sums, 0)
+		}
+		for j, v := range terms {
+			sums[j] += v
+		}
+	}
+	sc.sums[k] = sums
+}
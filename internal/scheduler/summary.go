@@ -0,0 +1,31 @@
+package scheduler
+
+// Bir doktorun nöbetlerinin özeti. Saatler slotun gerçek süresinden hesaplanır.
+type Summary struct {
+	Shifts          int
+	Hours           float64
+	WeekendShifts   int
+	HolidayShifts   int
+	OvernightShifts int
+	Load            float64 // ağırlıklı nöbet yükü
+}
+
+// Slotları özetler. Ertesi güne sarkan nöbetler gece nöbeti sayılır.
+func Summarize(slots []Slot) Summary {
+	var s Summary
+	for _, slot := range slots {
+		s.Shifts++
+		s.Hours += slot.End.Sub(slot.Start).Hours()
+		s.Load += slot.weight()
+		switch slot.Kind {
+		case Weekend:
+			s.WeekendShifts++
+		case PublicHoliday:
+			s.HolidayShifts++
+		}
+		if slot.End.YearDay() != slot.Start.YearDay() || slot.End.Year() != slot.Start.Year() {
+			s.OvernightShifts++
+		}
+	}
+	return s
+}
@@ -0,0 +1,35 @@
+package scheduler_test
+
+import (
+	"shift-scheduling-v2/internal/scheduler"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeCountsHoursKindsAndOvernightShifts(t *testing.T) {
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	if err != nil {
+		t.Skip("saat dilimi verisi bulunamadı")
+	}
+	at := func(day int, hour int) time.Time { return time.Date(2025, 3, day, hour, 0, 0, 0, istanbul) }
+
+	slots := []scheduler.Slot{
+		{Start: at(3, 8), End: at(3, 16), Kind: scheduler.Weekday},                   // gündüz
+		{Start: at(8, 16), End: at(9, 8), Kind: scheduler.Weekend, Weight: 1.5},      // gece, hafta sonu
+		{Start: at(18, 8), End: at(19, 8), Kind: scheduler.PublicHoliday, Weight: 2}, // 24 saat
+	}
+
+	summary := scheduler.Summarize(slots)
+	assert.Equal(t, 3, summary.Shifts)
+	assert.InDelta(t, 48, summary.Hours, 0.001)
+	assert.Equal(t, 1, summary.WeekendShifts)
+	assert.Equal(t, 1, summary.HolidayShifts)
+	assert.Equal(t, 2, summary.OvernightShifts)
+	assert.InDelta(t, 4.5, summary.Load, 0.001)
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	assert.Equal(t, scheduler.Summary{}, scheduler.Summarize(nil))
+}
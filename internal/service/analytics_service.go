@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/pkg/errorx"
+	"sort"
+	"time"
+)
+
+// Tek sorguda analiz edilebilecek en uzun aralık
+const analyticsMaxDays = 366
+
+type AnalyticsService struct {
+	analyticsRepo *repository.AnalyticsRepository
+	doctorRepo    *repository.DoctorRepository
+	locationRepo  *repository.LocationRepository
+	shiftService  *ShiftService
+}
+
+func NewAnalyticsService(analyticsRepo *repository.AnalyticsRepository, doctorRepo *repository.DoctorRepository, locationRepo *repository.LocationRepository, shiftService *ShiftService) *AnalyticsService {
+	return &AnalyticsService{
+		analyticsRepo: analyticsRepo,
+		doctorRepo:    doctorRepo,
+		locationRepo:  locationRepo,
+		shiftService:  shiftService,
+	}
+}
+
+// Lokasyonun [from, to] aralığındaki doktor bazında nöbet yükü. Boş bırakılan from içinde
+// bulunulan ayın ilk günü, to ise from'un ayının son günüdür.
+func (s *AnalyticsService) LocationWorkload(ctx context.Context, locationID int64, from time.Time, to time.Time) (*dto.LocationWorkloadDTO, error) {
+	if from.IsZero() {
+		now := time.Now().UTC()
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	if to.IsZero() {
+		to = time.Date(from.Year(), from.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+	}
+	if to.Before(from) {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Bitiş tarihi başlangıçtan önce olamaz")
+	}
+	end := to.AddDate(0, 0, 1)
+	if end.Sub(from) > analyticsMaxDays*24*time.Hour {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Aralık en fazla 366 gün olabilir")
+	}
+
+	location, err := s.locationRepo.GetByID(ctx, locationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.WithDetails(errorx.ErrNotFound, "Lokasyon bulunamadı")
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	p, err := s.shiftService.policy(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+	holidays, err := s.shiftService.publicHolidays(ctx, locationID, from, end)
+	if err != nil {
+		return nil, err
+	}
+	dates := make([]time.Time, 0, len(holidays))
+	for key := range holidays {
+		day, _ := time.Parse("2006-01-02", key)
+		dates = append(dates, day)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	workload, err := s.analyticsRepo.GetLocationWorkload(ctx, repository.WorkloadQuery{
+		LocationID:     locationID,
+		Start:          from,
+		End:            end,
+		WeekendDays:    p.WeekendDays,
+		PublicHolidays: dates,
+	})
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	memberships, err := s.doctorRepo.GetMemberships(ctx, locationID, from, end)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	doctors := make(map[int64]model.Doctor)
+	members := make(map[int64]bool)
+	for _, m := range memberships {
+		doctors[m.DoctorID] = m.Doctor
+		members[m.DoctorID] = true
+	}
+
+	// Üyeliği sona ermiş ama aralıkta kaydı olan doktorlar
+	var missing []int64
+	addMissing := func(doctorID int64) {
+		if _, ok := doctors[doctorID]; !ok {
+			doctors[doctorID] = model.Doctor{}
+			missing = append(missing, doctorID)
+		}
+	}
+	for _, l := range workload.Shifts {
+		addMissing(l.DoctorID)
+	}
+	for _, l := range workload.Leaves {
+		addMissing(l.DoctorID)
+	}
+	for _, l := range workload.Swaps {
+		addMissing(l.DoctorID)
+	}
+	if len(missing) > 0 {
+		others, err := s.doctorRepo.GetByIDs(ctx, missing)
+		if err != nil {
+			return nil, errorx.ErrDatabaseOperation
+		}
+		for _, d := range others {
+			doctors[d.ID] = d
+		}
+	}
+
+	span := monthSpan(from, end)
+	rows := make(map[int64]*dto.DoctorWorkloadDTO, len(doctors))
+	for id, d := range doctors {
+		rows[id] = &dto.DoctorWorkloadDTO{
+			DoctorID:   id,
+			Name:       doctorName(d),
+			Member:     members[id],
+			ShiftLimit: p.ShiftLimit(d.ShiftLimit),
+		}
+	}
+	for _, l := range workload.Shifts {
+		row := rows[l.DoctorID]
+		row.Shifts = l.Shifts
+		row.Hours = roundHours(l.Hours)
+		row.WeekendShifts = l.WeekendShifts
+		row.HolidayShifts = l.HolidayShifts
+		row.OvernightShifts = l.OvernightShifts
+	}
+	for _, l := range workload.Leaves {
+		rows[l.DoctorID].LeaveDays = l.Days
+	}
+	for _, l := range workload.Swaps {
+		rows[l.DoctorID].Swaps = dto.SwapCountsDTO{Requested: l.Requested, Received: l.Received, Completed: l.Completed}
+	}
+
+	result := &dto.LocationWorkloadDTO{
+		LocationID: location.ID,
+		Location:   location.Name,
+		From:       from.Format("2006-01-02"),
+		To:         to.Format("2006-01-02"),
+		Doctors:    make([]dto.DoctorWorkloadDTO, 0, len(rows)),
+	}
+	for _, row := range rows {
+		if row.ShiftLimit > 0 {
+			capacity := float64(row.ShiftLimit) * span
+			utilisation := math.Round(float64(row.Shifts)/capacity*10000) / 100
+			row.Capacity = math.Round(capacity*10) / 10
+			row.Utilisation = &utilisation
+		}
+		result.Doctors = append(result.Doctors, *row)
+	}
+	sort.Slice(result.Doctors, func(i, j int) bool {
+		a, b := result.Doctors[i], result.Doctors[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.DoctorID < b.DoctorID
+	})
+
+	result.Summary = workloadSummary(result.Doctors)
+	return result, nil
+}
+
+// Toplamlar tüm doktorlardan, en yüklü ve en az yüklü doktor aralıkta üye olanlardan hesaplanır
+func workloadSummary(doctors []dto.DoctorWorkloadDTO) dto.WorkloadSummaryDTO {
+	summary := dto.WorkloadSummaryDTO{Doctors: len(doctors)}
+	var completed, members int
+	var maxHours, minHours float64
+	for _, d := range doctors {
+		summary.Shifts += d.Shifts
+		summary.Hours += d.Hours
+		summary.WeekendShifts += d.WeekendShifts
+		summary.HolidayShifts += d.HolidayShifts
+		summary.OvernightShifts += d.OvernightShifts
+		summary.LeaveDays += d.LeaveDays
+		summary.SwapsRequested += d.Swaps.Requested
+		completed += d.Swaps.Completed
+
+		if !d.Member {
+			continue
+		}
+		if members == 0 || d.Shifts > summary.MaxShifts {
+			summary.MaxShifts = d.Shifts
+			summary.MostLoadedDoctorID = d.DoctorID
+		}
+		if members == 0 || d.Shifts < summary.MinShifts {
+			summary.MinShifts = d.Shifts
+			summary.LeastLoadedDoctorID = d.DoctorID
+		}
+		if members == 0 || d.Hours > maxHours {
+			maxHours = d.Hours
+		}
+		if members == 0 || d.Hours < minHours {
+			minHours = d.Hours
+		}
+		summary.AverageShifts += float64(d.Shifts)
+		members++
+	}
+
+	// Uygulanan her değişim iki doktorda da sayılır
+	summary.SwapsCompleted = completed / 2
+	summary.Hours = roundHours(summary.Hours)
+	if members > 0 {
+		summary.AverageShifts = math.Round(summary.AverageShifts/float64(members)*100) / 100
+		summary.ShiftGap = summary.MaxShifts - summary.MinShifts
+		summary.HourGap = roundHours(maxHours - minHours)
+	}
+	return summary
+}
+
+// [start, end) aralığının kaç aya denk geldiği. Kısmi aylar gün oranıyla sayılır.
+func monthSpan(start time.Time, end time.Time) float64 {
+	var span float64
+	for month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC); month.Before(end); month = month.AddDate(0, 1, 0) {
+		next := month.AddDate(0, 1, 0)
+		from, to := month, next
+		if start.After(from) {
+			from = start
+		}
+		if end.Before(to) {
+			to = end
+		}
+		span += to.Sub(from).Hours() / next.Sub(month).Hours()
+	}
+	return span
+}
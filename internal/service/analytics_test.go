@@ -0,0 +1,32 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocationWorkloadRejectsInvalidRange(t *testing.T) {
+	// Aralık doğrulaması veritabanına gitmeden yapılır
+	s := service.NewAnalyticsService(nil, nil, nil, nil)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	for name, r := range map[string][2]time.Time{
+		"ters":       {day(2025, 3, 10), day(2025, 3, 9)},
+		"çok uzun":   {day(2025, 1, 1), day(2026, 1, 2)},
+		"artık yıl+": {day(2024, 1, 1), day(2025, 1, 1)},
+	} {
+		resp, err := s.LocationWorkload(context.Background(), 1, r[0], r[1])
+		assert.Nil(t, resp, name)
+
+		var e *errorx.Error
+		require.True(t, errors.As(err, &e), name)
+		assert.Equal(t, errorx.StatusUnprocessableEntity, e.Code, name)
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"shift-scheduling-v2/internal/audit"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/logger"
+	"shift-scheduling-v2/pkg/query"
+	"time"
+)
+
+// Saklama süresi dolan kayıtların silinme aralığı
+const auditPurgeInterval = 24 * time.Hour
+
+type AuditService struct {
+	repo          *repository.AuditRepository
+	retentionDays int
+}
+
+func NewAuditService(repo *repository.AuditRepository, retentionDays int) *AuditService {
+	return &AuditService{repo: repo, retentionDays: retentionDays}
+}
+
+// Değişikliği işlemi yapan kullanıcı ve istemci adresiyle kaydeder. before ve after
+// nil olabilir. Değişiklik zaten yazıldığından kayıt hatası isteği bozmaz, loglanır.
+func (s *AuditService) Record(ctx context.Context, action string, entity string, entityID int64, before any, after any) {
+	if s == nil {
+		return
+	}
+
+	entry := model.AuditLog{
+		ActorID:  audit.Actor(ctx),
+		Action:   action,
+		Entity:   entity,
+		EntityID: entityID,
+		IP:       audit.ClientIP(ctx),
+	}
+	var err error
+	if entry.Before, err = audit.Encode(before); err != nil {
+		logger.Error("Denetim kaydı hazırlanamadı (%s %s %d): %v", action, entity, entityID, err)
+		return
+	}
+	if entry.After, err = audit.Encode(after); err != nil {
+		logger.Error("Denetim kaydı hazırlanamadı (%s %s %d): %v", action, entity, entityID, err)
+		return
+	}
+
+	if err = s.repo.Create(context.WithoutCancel(ctx), &entry); err != nil {
+		logger.Error("Denetim kaydı yazılamadı (%s %s %d): %v", action, entity, entityID, err)
+	}
+}
+
+func (s *AuditService) List(ctx context.Context, filter repository.AuditFilter, p query.Pagination) (*dto.AuditListDTO, error) {
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Başlangıç bitişten önce olmalı")
+	}
+
+	entries, err := s.repo.List(ctx, filter, &p)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := &dto.AuditListDTO{
+		Items:      make([]dto.AuditLogDTO, len(entries)),
+		Pagination: query.GetPaginationResponse(p),
+	}
+	for i, entry := range entries {
+		result.Items[i] = dto.AuditLogDTO{}.ToResponseModel(entry)
+	}
+	return result, nil
+}
+
+// Saklama süresi tanımlıysa eski kayıtları başlangıçta ve günde bir kez siler, ctx kapanınca durur
+func (s *AuditService) StartRetention(ctx context.Context) {
+	if _, ok := audit.Cutoff(time.Now(), s.retentionDays); !ok {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(auditPurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			s.purge(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *AuditService) purge(ctx context.Context) {
+	cutoff, ok := audit.Cutoff(time.Now(), s.retentionDays)
+	if !ok {
+		return
+	}
+
+	deleted, err := s.repo.Purge(ctx, cutoff)
+	if err != nil {
+		logger.Error("Eski denetim kayıtları silinemedi: %v", err)
+		return
+	}
+	if deleted > 0 {
+		logger.Info("%d eski denetim kaydı silindi", deleted)
+	}
+}
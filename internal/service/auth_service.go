@@ -15,12 +15,14 @@ import (
 type AuthService struct {
 	authRepo *repository.AuthRepository
 	userRepo *repository.UserRepository
+	audit    *AuditService
 }
 
-func NewAuthService(authRepo *repository.AuthRepository, userRepo *repository.UserRepository) *AuthService {
+func NewAuthService(authRepo *repository.AuthRepository, userRepo *repository.UserRepository, audit *AuditService) *AuthService {
 	return &AuthService{
 		authRepo: authRepo,
 		userRepo: userRepo,
+		audit:    audit,
 	}
 }
 
@@ -56,6 +58,7 @@ func (s *AuthService) Register(ctx context.Context, req *dto.RegisterRequest) (*
 
 		return nil, errorx.ErrDatabaseOperation
 	}
+	s.audit.Record(ctx, model.AuditActionCreate, model.AuditEntityUser, user.ID, nil, dto.UserResponseDTO{}.ToResponseModel(*user))
 
 	return user, nil
 }
@@ -244,6 +247,8 @@ func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword stri
 	if err = s.userRepo.Update(ctx, user); err != nil {
 		return errorx.ErrDatabaseOperation
 	}
+	// Şifre kayda geçmez, sadece sıfırlandığı bilinir
+	s.audit.Record(ctx, model.AuditActionUpdate, model.AuditEntityUser, user.ID, nil, map[string]any{"password_reset": true})
 
 	// Kullanıcının tüm oturumlarını sonlandır
 	sessions, err := s.authRepo.GetSessionsByUserID(ctx, user.ID)
@@ -0,0 +1,140 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"regexp"
+	"shift-scheduling-v2/config"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/jwt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+// Kullanıcı tablosunu bellekte tutan, bun'ın ürettiği sorgulara cevap veren sürücü.
+// bun değerleri sorguya gömdüğünden eklenen kullanıcının şifresi INSERT metninden okunur.
+type usersDB struct {
+	mu       sync.Mutex
+	inserted []string
+	password string
+}
+
+var (
+	returningRe = regexp.MustCompile(`RETURNING (.+)$`)
+	bcryptRe    = regexp.MustCompile(`'(\$2[aby]\$[^']+)'`)
+)
+
+func (db *usersDB) respond(query string) ([]string, [][]driver.Value) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "SELECT EXISTS"):
+		return []string{"exists"}, [][]driver.Value{{db.password != ""}}
+	case strings.HasPrefix(query, `INSERT INTO "users"`):
+		db.inserted = append(db.inserted, query)
+		if m := bcryptRe.FindStringSubmatch(query); m != nil {
+			db.password = m[1]
+		}
+	case strings.HasPrefix(query, "SELECT") && strings.Contains(query, `FROM "users"`):
+		if db.password == "" {
+			return []string{"id"}, nil
+		}
+		return []string{"id", "email", "password", "role", "status"},
+			[][]driver.Value{{int64(1), "ali@example.com", db.password, int64(model.UserRoleNormal), string(model.StatusActive)}}
+	}
+
+	// Eklenen kayıtların RETURNING sütunları
+	m := returningRe.FindStringSubmatch(query)
+	if m == nil {
+		return nil, nil
+	}
+	var columns []string
+	var row []driver.Value
+	for _, c := range strings.Split(m[1], ", ") {
+		c = strings.Trim(c, `"`)
+		columns = append(columns, c)
+		switch c {
+		case "id":
+			row = append(row, int64(1))
+		case "created_at", "updated_at":
+			row = append(row, time.Now())
+		default:
+			row = append(row, nil)
+		}
+	}
+	return columns, [][]driver.Value{row}
+}
+
+func (db *usersDB) Connect(context.Context) (driver.Conn, error) { return usersConn{db}, nil }
+func (db *usersDB) Driver() driver.Driver                        { return nil }
+
+type usersConn struct{ db *usersDB }
+
+func (c usersConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c usersConn) Close() error                        { return nil }
+func (c usersConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+func (c usersConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	columns, rows := c.db.respond(query)
+	return &usersRows{columns: columns, rows: rows}, nil
+}
+
+func (c usersConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.db.respond(query)
+	return driver.RowsAffected(1), nil
+}
+
+type usersRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *usersRows) Columns() []string { return r.columns }
+func (r *usersRows) Close() error      { return nil }
+func (r *usersRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestRegisterStoresHashAndLoginChecksIt(t *testing.T) {
+	jwt.Init(&config.JWTConfig{Secret: "test-secret", RefreshSecret: "test-refresh-secret", Expiration: 24, RefreshExpiration: 7})
+
+	fake := &usersDB{}
+	db := bun.NewDB(sql.OpenDB(fake), pgdialect.New())
+	defer db.Close()
+	s := service.NewAuthService(repository.NewAuthRepository(db), repository.NewUserRepository(db), nil)
+
+	ctx := context.WithValue(context.Background(), "user_agent", "test")
+	ctx = context.WithValue(ctx, "client_ip", "127.0.0.1")
+
+	_, err := s.Register(ctx, &dto.RegisterRequest{Email: "ali@example.com", Password: "gizli-sifre", Name: "Ali", Surname: "Yılmaz"})
+	require.NoError(t, err)
+
+	require.Len(t, fake.inserted, 1)
+	assert.NotContains(t, fake.inserted[0], "gizli-sifre")
+	require.NotEmpty(t, fake.password, "şifre hash'i kaydedilmedi")
+
+	resp, err := s.Login(ctx, &dto.LoginRequest{Email: "ali@example.com", Password: "gizli-sifre"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+
+	_, err = s.Login(ctx, &dto.LoginRequest{Email: "ali@example.com", Password: "yanlis"})
+	assert.ErrorIs(t, err, jwt.ErrInvalidCredentials)
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/ics"
+	"strings"
+	"time"
+)
+
+// Takvim aboneliğinde yayınlanan aralık ve iptal edilen nöbetlerin ne kadar süre yayınlanacağı
+const (
+	calendarFeedPastDays   = 60
+	calendarFeedFutureDays = 365
+	calendarRemovalDays    = 90
+	calendarTokenBytes     = 32
+)
+
+// Doktor ve lokasyon nöbetlerini anahtarla erişilen, salt okunur ICS abonelikleri olarak yayınlar.
+// Sadece yayınlanmış ya da kilitlenmiş aylar görünür; silinen veya başkasına geçen nöbetler
+// aynı UID ile iptal edilmiş etkinlik olarak yazılır.
+type CalendarFeedService struct {
+	feedRepo     *repository.CalendarFeedRepository
+	shiftRepo    *repository.ShiftRepository
+	doctorRepo   *repository.DoctorRepository
+	locationRepo *repository.LocationRepository
+	shiftService *ShiftService
+	publicURL    string
+}
+
+func NewCalendarFeedService(feedRepo *repository.CalendarFeedRepository, shiftRepo *repository.ShiftRepository, doctorRepo *repository.DoctorRepository, locationRepo *repository.LocationRepository, shiftService *ShiftService, publicURL string) *CalendarFeedService {
+	return &CalendarFeedService{
+		feedRepo:     feedRepo,
+		shiftRepo:    shiftRepo,
+		doctorRepo:   doctorRepo,
+		locationRepo: locationRepo,
+		shiftService: shiftService,
+		publicURL:    strings.TrimRight(publicURL, "/"),
+	}
+}
+
+func (s *CalendarFeedService) CreateForDoctor(ctx context.Context, userID int64, req dto.CalendarFeedRequest) (*dto.CalendarFeedDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.create(ctx, model.CalendarFeedToken{Kind: model.CalendarFeedDoctor, DoctorID: doctor.ID, Name: req.Name, CreatedBy: userID})
+}
+
+func (s *CalendarFeedService) ListForDoctor(ctx context.Context, userID int64) ([]dto.CalendarFeedDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.list(ctx, model.CalendarFeedDoctor, doctor.ID)
+}
+
+// Doktor sadece kendi aboneliğini iptal edebilir
+func (s *CalendarFeedService) RevokeForDoctor(ctx context.Context, userID int64, id int64) error {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return err
+	}
+
+	token, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if token.Kind != model.CalendarFeedDoctor || token.DoctorID != doctor.ID {
+		return errorx.ErrNotFound
+	}
+	return s.revoke(ctx, token.ID)
+}
+
+func (s *CalendarFeedService) CreateForLocation(ctx context.Context, locationID int64, userID int64, req dto.CalendarFeedRequest) (*dto.CalendarFeedDTO, error) {
+	if _, err := s.location(ctx, locationID); err != nil {
+		return nil, err
+	}
+	return s.create(ctx, model.CalendarFeedToken{Kind: model.CalendarFeedLocation, LocationID: locationID, Name: req.Name, CreatedBy: userID})
+}
+
+func (s *CalendarFeedService) ListForLocation(ctx context.Context, locationID int64) ([]dto.CalendarFeedDTO, error) {
+	if _, err := s.location(ctx, locationID); err != nil {
+		return nil, err
+	}
+	return s.list(ctx, model.CalendarFeedLocation, locationID)
+}
+
+// Admin her türlü aboneliği iptal edebilir
+func (s *CalendarFeedService) Revoke(ctx context.Context, id int64) error {
+	if _, err := s.load(ctx, id); err != nil {
+		return err
+	}
+	return s.revoke(ctx, id)
+}
+
+// Anahtara ait takvimi ICS olarak üretir. Bilinmeyen ya da iptal edilmiş anahtar için ErrNotFound döner.
+func (s *CalendarFeedService) Feed(ctx context.Context, kind string, token string) ([]byte, error) {
+	if kind != model.CalendarFeedDoctor && kind != model.CalendarFeedLocation {
+		return nil, errorx.ErrNotFound
+	}
+
+	feed, err := s.feedRepo.GetActiveByHash(ctx, kind, hashFeedToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.ErrNotFound
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	now := time.Now()
+	_ = s.feedRepo.Touch(ctx, feed.ID, now)
+
+	today := truncateDay(now.UTC())
+	start, end := today.AddDate(0, 0, -calendarFeedPastDays), today.AddDate(0, 0, calendarFeedFutureDays)
+
+	var (
+		cal     ics.Calendar
+		shifts  []model.Shift
+		ownerID int64
+	)
+	scope := newMonthScope(s.shiftService)
+	if kind == model.CalendarFeedDoctor {
+		ownerID = feed.DoctorID
+		doctors, err := s.doctorRepo.GetByIDs(ctx, []int64{ownerID})
+		if err != nil || len(doctors) == 0 {
+			return nil, errorx.ErrDatabaseOperation
+		}
+		cal.Name = fmt.Sprintf("Nöbetlerim - %s", doctorName(doctors[0]))
+		if shifts, err = s.shiftRepo.GetDoctorShifts(ctx, ownerID, start, end); err != nil {
+			return nil, errorx.ErrDatabaseOperation
+		}
+	} else {
+		ownerID = feed.LocationID
+		location, err := s.location(ctx, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		p, err := scope.policy(ctx, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		cal.Name = fmt.Sprintf("Nöbet Listesi - %s", location.Name)
+		cal.TimeZone = p.loc.String()
+		if shifts, err = s.shiftRepo.GetLocationShifts(ctx, ownerID, start, end); err != nil {
+			return nil, errorx.ErrDatabaseOperation
+		}
+	}
+
+	live := make(map[int64]bool, len(shifts))
+	locations := make(map[int64]string)
+	for _, shift := range shifts {
+		visible, err := scope.published(ctx, shift.LocationID, shift.ShiftDate)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			continue
+		}
+
+		event, err := s.shiftEvent(ctx, scope, kind, shift)
+		if err != nil {
+			return nil, err
+		}
+		cal.Events = append(cal.Events, event)
+		live[shift.ID] = true
+		locations[shift.LocationID] = shift.Location.Name
+	}
+
+	removals, err := s.feedRepo.GetRemovals(ctx, kind, ownerID, start, end, now.AddDate(0, 0, -calendarRemovalDays))
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	for _, removal := range removals {
+		// Nöbet hâlâ takvimdeyse ya da daha yeni bir iptal yazıldıysa atlanır
+		if live[removal.ShiftID] {
+			continue
+		}
+		live[removal.ShiftID] = true
+
+		// Hiç yayınlanmamış aylardaki nöbetler takvime girmemiştir
+		status, err := scope.status(ctx, removal.LocationID, removal.ShiftDate)
+		if err != nil {
+			return nil, err
+		}
+		if !isVisible(status.State) && status.Version == 0 {
+			continue
+		}
+
+		name, ok := locations[removal.LocationID]
+		if !ok {
+			location, err := s.location(ctx, removal.LocationID)
+			if err != nil {
+				return nil, err
+			}
+			name = location.Name
+			locations[removal.LocationID] = name
+		}
+
+		event, err := s.removalEvent(ctx, scope, removal, name)
+		if err != nil {
+			return nil, err
+		}
+		cal.Events = append(cal.Events, event)
+	}
+
+	var buf bytes.Buffer
+	if err = ics.Write(&buf, cal, now); err != nil {
+		return nil, errorx.ErrInternal
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *CalendarFeedService) shiftEvent(ctx context.Context, scope *monthScope, kind string, shift model.Shift) (ics.FeedEvent, error) {
+	p, err := scope.policy(ctx, shift.LocationID)
+	if err != nil {
+		return ics.FeedEvent{}, err
+	}
+	slot, err := shiftSlot(shift.ShiftDate, shift.StartTime, shift.EndTime, p)
+	if err != nil {
+		return ics.FeedEvent{}, err
+	}
+
+	summary := fmt.Sprintf("Nöbet - %s", shift.Location.Name)
+	if kind == model.CalendarFeedLocation {
+		summary = doctorName(shift.Doctor)
+	}
+	if shift.Template != nil {
+		summary = fmt.Sprintf("%s (%s)", summary, shift.Template.Name)
+	}
+
+	return ics.FeedEvent{
+		UID:         shiftEventUID(shift.ID),
+		Summary:     summary,
+		Description: fmt.Sprintf("%s\n%s %s-%s", doctorName(shift.Doctor), shift.Location.Name, shift.StartTime, shift.EndTime),
+		Location:    shift.Location.Name,
+		Start:       slot.Start,
+		End:         slot.End,
+		Modified:    shift.UpdatedAt,
+	}, nil
+}
+
+func (s *CalendarFeedService) removalEvent(ctx context.Context, scope *monthScope, removal model.ShiftRemoval, location string) (ics.FeedEvent, error) {
+	p, err := scope.policy(ctx, removal.LocationID)
+	if err != nil {
+		return ics.FeedEvent{}, err
+	}
+	slot, err := shiftSlot(removal.ShiftDate, removal.StartTime, removal.EndTime, p)
+	if err != nil {
+		return ics.FeedEvent{}, err
+	}
+
+	return ics.FeedEvent{
+		UID:       shiftEventUID(removal.ShiftID),
+		Summary:   fmt.Sprintf("İptal: Nöbet - %s", location),
+		Location:  location,
+		Start:     slot.Start,
+		End:       slot.End,
+		Modified:  removal.RemovedAt,
+		Cancelled: true,
+	}, nil
+}
+
+// Anahtar sadece oluşturulduğunda döner, veritabanında özeti saklanır
+func (s *CalendarFeedService) create(ctx context.Context, token model.CalendarFeedToken) (*dto.CalendarFeedDTO, error) {
+	raw := make([]byte, calendarTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, errorx.ErrInternal
+	}
+	secret := base64.RawURLEncoding.EncodeToString(raw)
+
+	token.Name = strings.TrimSpace(token.Name)
+	token.TokenHash = hashFeedToken(secret)
+	if err := s.feedRepo.Create(ctx, &token); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := dto.CalendarFeedDTO{}.ToResponseModel(token)
+	result.Token = secret
+	result.URL = fmt.Sprintf("%s/calendar/%s/%s.ics", s.publicURL, token.Kind, secret)
+	return &result, nil
+}
+
+func (s *CalendarFeedService) list(ctx context.Context, kind string, ownerID int64) ([]dto.CalendarFeedDTO, error) {
+	tokens, err := s.feedRepo.List(ctx, kind, ownerID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]dto.CalendarFeedDTO, len(tokens))
+	for i, token := range tokens {
+		result[i] = dto.CalendarFeedDTO{}.ToResponseModel(token)
+	}
+	return result, nil
+}
+
+func (s *CalendarFeedService) revoke(ctx context.Context, id int64) error {
+	if err := s.feedRepo.Revoke(ctx, id, time.Now()); err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	return nil
+}
+
+func (s *CalendarFeedService) load(ctx context.Context, id int64) (*model.CalendarFeedToken, error) {
+	token, err := s.feedRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.ErrNotFound
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return token, nil
+}
+
+func (s *CalendarFeedService) location(ctx context.Context, id int64) (*model.ShiftLocation, error) {
+	location, err := s.locationRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.WithDetails(errorx.ErrNotFound, "Lokasyon bulunamadı")
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return location, nil
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Nöbet güncellendiğinde ya da iptal edildiğinde istemcinin aynı etkinliği bulması için sabit kalır
+func shiftEventUID(shiftID int64) string {
+	return fmt.Sprintf("shift-%d@shift-scheduling", shiftID)
+}
+
+func doctorName(doctor model.Doctor) string {
+	return strings.TrimSpace(strings.Join([]string{doctor.Title, doctor.User.Name, doctor.User.Surname}, " "))
+}
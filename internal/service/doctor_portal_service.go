@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/internal/scheduler"
+	"shift-scheduling-v2/pkg/errorx"
+	"sort"
+	"time"
+)
+
+// Doktorun kendi nöbetlerini, izinlerini ve değişim taleplerini gördüğü self-servis uçlar.
+// Nöbetler sadece yayınlanmış ya da kilitlenmiş aylardan gösterilir.
+type DoctorPortalService struct {
+	shiftRepo    *repository.ShiftRepository
+	doctorRepo   *repository.DoctorRepository
+	leaveRepo    *repository.LeaveRepository
+	swapRepo     *repository.ShiftSwapRepository
+	shiftService *ShiftService
+}
+
+func NewDoctorPortalService(shiftRepo *repository.ShiftRepository, doctorRepo *repository.DoctorRepository, leaveRepo *repository.LeaveRepository, swapRepo *repository.ShiftSwapRepository, shiftService *ShiftService) *DoctorPortalService {
+	return &DoctorPortalService{
+		shiftRepo:    shiftRepo,
+		doctorRepo:   doctorRepo,
+		leaveRepo:    leaveRepo,
+		swapRepo:     swapRepo,
+		shiftService: shiftService,
+	}
+}
+
+// İstek boyunca lokasyon politikalarını ve ayların yayın durumunu bir kez okur
+type monthScope struct {
+	shiftService *ShiftService
+	policies     map[int64]locationPolicy
+	statuses     map[string]model.ShiftsStatus
+}
+
+func newMonthScope(shiftService *ShiftService) *monthScope {
+	return &monthScope{shiftService: shiftService, policies: map[int64]locationPolicy{}, statuses: map[string]model.ShiftsStatus{}}
+}
+
+func (m *monthScope) policy(ctx context.Context, locationID int64) (locationPolicy, error) {
+	if p, ok := m.policies[locationID]; ok {
+		return p, nil
+	}
+	p, err := m.shiftService.policy(ctx, locationID)
+	if err != nil {
+		return locationPolicy{}, err
+	}
+	m.policies[locationID] = p
+	return p, nil
+}
+
+// Ayın durum kaydı. Kaydı olmayan aylar hiç yayınlanmamış taslaktır.
+func (m *monthScope) status(ctx context.Context, locationID int64, day time.Time) (model.ShiftsStatus, error) {
+	key := fmt.Sprintf("%d-%s", locationID, day.Format("2006-01"))
+	if status, ok := m.statuses[key]; ok {
+		return status, nil
+	}
+	status, err := m.shiftService.shiftRepo.GetShiftStatus(ctx, day.Year(), int(day.Month()), int(locationID))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return model.ShiftsStatus{}, errorx.ErrDatabaseOperation
+		}
+		status = &model.ShiftsStatus{LocationID: locationID, Year: day.Year(), Month: int(day.Month()), State: model.ScheduleStateDraft}
+	}
+	m.statuses[key] = *status
+	return *status, nil
+}
+
+func (m *monthScope) published(ctx context.Context, locationID int64, day time.Time) (bool, error) {
+	status, err := m.status(ctx, locationID, day)
+	if err != nil {
+		return false, err
+	}
+	return isVisible(status.State), nil
+}
+
+// Bugünden itibaren verilen gün sayısı kadar ileriye kadar olan nöbetler.
+// "Bugün" her nöbet için kendi lokasyonunun saat dilimine göre belirlenir.
+func (s *DoctorPortalService) UpcomingShifts(ctx context.Context, userID int64, days int) ([]dto.ShiftListWithDetailsDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Saat dilimi farkları için aralık bir gün geniş tutulur
+	today := truncateDay(time.Now().UTC())
+	shifts, err := s.shiftRepo.GetDoctorShifts(ctx, doctor.ID, today.AddDate(0, 0, -1), today.AddDate(0, 0, days+1))
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	scope := newMonthScope(s.shiftService)
+	result := make([]dto.ShiftListWithDetailsDTO, 0, len(shifts))
+	for _, shift := range shifts {
+		p, err := scope.policy(ctx, shift.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		if shift.ShiftDate.Before(p.today()) || !shift.ShiftDate.Before(p.today().AddDate(0, 0, days)) {
+			continue
+		}
+		visible, err := scope.published(ctx, shift.LocationID, shift.ShiftDate)
+		if err != nil {
+			return nil, err
+		}
+		if visible {
+			result = append(result, dto.ShiftListWithDetailsDTO{}.ToResponseModel(shift))
+		}
+	}
+	return result, nil
+}
+
+// Bugün ve sonrasındaki izin günleri
+func (s *DoctorPortalService) Holidays(ctx context.Context, userID int64) ([]dto.DoctorHolidayDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.doctorRepo.GetHolidaysByDoctor(ctx, doctor.ID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].HolidayDate.Before(holidays[j].HolidayDate) })
+
+	today := truncateDay(time.Now().UTC())
+	result := make([]dto.DoctorHolidayDTO, 0, len(holidays))
+	for _, holiday := range holidays {
+		if !holiday.HolidayDate.Before(today) {
+			result = append(result, dto.DoctorHolidayDTO{}.ToResponseModel(holiday))
+		}
+	}
+	return result, nil
+}
+
+func (s *DoctorPortalService) LeaveRequests(ctx context.Context, userID int64) ([]dto.LeaveResponseDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	leaves, err := s.leaveRepo.ListByDoctor(ctx, doctor.ID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return leaveResponses(leaves), nil
+}
+
+// Doktorun üye olduğu lokasyonlarda bugün nöbetçi olan meslektaşları
+func (s *DoctorPortalService) ColleaguesToday(ctx context.Context, userID int64) ([]dto.ShiftListWithDetailsDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	locationIDs, err := s.doctorRepo.GetLocationIDs(ctx, doctor.ID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	scope := newMonthScope(s.shiftService)
+	today := make(map[int64]time.Time, len(locationIDs))
+	var dates []time.Time
+	for _, locationID := range locationIDs {
+		p, err := scope.policy(ctx, locationID)
+		if err != nil {
+			return nil, err
+		}
+		visible, err := scope.published(ctx, locationID, p.today())
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			continue
+		}
+		today[locationID] = p.today()
+		dates = append(dates, p.today())
+	}
+
+	result := []dto.ShiftListWithDetailsDTO{}
+	if len(dates) == 0 {
+		return result, nil
+	}
+
+	shifts, err := s.shiftRepo.GetShiftsByDates(ctx, dates)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	for _, shift := range shifts {
+		day, ok := today[shift.LocationID]
+		if ok && shift.DoctorID != doctor.ID && shift.ShiftDate.Equal(day) {
+			result = append(result, dto.ShiftListWithDetailsDTO{}.ToResponseModel(shift))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].LocationID != result[j].LocationID {
+			return result[i].LocationID < result[j].LocationID
+		}
+		return result[i].StartTime < result[j].StartTime
+	})
+	return result, nil
+}
+
+// Doktorun ayda tuttuğu nöbetlerin sayısı, saatleri ve gün türlerine göre dağılımı.
+// Yayınlanmamış aylardaki nöbetler sayılmaz.
+func (s *DoctorPortalService) MonthStats(ctx context.Context, userID int64, year int, month int) (*dto.DoctorMonthStatsDTO, error) {
+	if err := validMonth(month); err != nil {
+		return nil, err
+	}
+
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	shifts, err := s.shiftRepo.GetDoctorShifts(ctx, doctor.ID, start, end)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	scope := newMonthScope(s.shiftService)
+	slots := make(map[int64][]scheduler.Slot)
+	names := make(map[int64]string)
+	for _, shift := range shifts {
+		visible, err := scope.published(ctx, shift.LocationID, shift.ShiftDate)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			continue
+		}
+
+		p, err := scope.policy(ctx, shift.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		slot, err := shiftSlot(shift.ShiftDate, shift.StartTime, shift.EndTime, p)
+		if err != nil {
+			return nil, err
+		}
+		slots[shift.LocationID] = append(slots[shift.LocationID], slot)
+		names[shift.LocationID] = shift.Location.Name
+	}
+
+	result := &dto.DoctorMonthStatsDTO{DoctorID: doctor.ID, Year: year, Month: month, Locations: []dto.DoctorLocationStatsDTO{}}
+	var all []scheduler.Slot
+	for locationID, locationSlots := range slots {
+		holidays, err := s.shiftService.publicHolidays(ctx, locationID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for i := range locationSlots {
+			holidays.apply(&locationSlots[i])
+		}
+		all = append(all, locationSlots...)
+
+		summary := scheduler.Summarize(locationSlots)
+		result.Locations = append(result.Locations, dto.DoctorLocationStatsDTO{
+			LocationID: locationID,
+			Location:   names[locationID],
+			Shifts:     summary.Shifts,
+			Hours:      summary.Hours,
+		})
+	}
+	sort.Slice(result.Locations, func(i, j int) bool { return result.Locations[i].LocationID < result.Locations[j].LocationID })
+
+	summary := scheduler.Summarize(all)
+	result.Shifts = summary.Shifts
+	result.Hours = summary.Hours
+	result.WeekendShifts = summary.WeekendShifts
+	result.HolidayShifts = summary.HolidayShifts
+	result.OvernightShifts = summary.OvernightShifts
+
+	holidays, err := s.doctorRepo.GetHolidaysByDoctorIDs(ctx, []int64{doctor.ID}, start, end)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	result.LeaveDays = len(holidays)
+
+	return result, nil
+}
+
+// Değişim talepleri: inbox doktorun karşı taraf olduğu, outbox kendi açtığı talepler
+func (s *DoctorPortalService) Swaps(ctx context.Context, userID int64) (*dto.SwapBoxDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.swapRepo.ExpireDue(ctx, time.Now()); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	swaps, err := s.swapRepo.ListByDoctor(ctx, doctor.ID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := &dto.SwapBoxDTO{Inbox: []dto.SwapResponseDTO{}, Outbox: []dto.SwapResponseDTO{}}
+	for _, swap := range swaps {
+		resp := dto.SwapResponseDTO{}.ToResponseModel(swap)
+		if swap.RequesterID == doctor.ID {
+			result.Outbox = append(result.Outbox, resp)
+		} else {
+			result.Inbox = append(result.Inbox, resp)
+		}
+	}
+	return result, nil
+}
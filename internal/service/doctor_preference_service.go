@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/policy"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/pkg/errorx"
+	"slices"
+	"time"
+)
+
+const (
+	// Bir ayın tercihleri ay başından bu kadar gün öncesine kadar değiştirilebilir
+	preferenceDeadlineDays = 10
+
+	// Doktorun tercihlerine verebileceği en yüksek ağırlık
+	maxPreferenceWeight = 3.0
+)
+
+func (s *DoctorService) currentDoctor(ctx context.Context, userID int64) (*model.Doctor, error) {
+	return doctorForUser(ctx, s.doctorRepo, userID)
+}
+
+// Giriş yapan kullanıcının doktor kaydını döner, doktor değilse 403 döner
+func doctorForUser(ctx context.Context, doctorRepo *repository.DoctorRepository, userID int64) (*model.Doctor, error) {
+	doctor, err := doctorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.WithDetails(errorx.ErrForbidden, "Kullanıcıya bağlı doktor kaydı bulunamadı")
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return doctor, nil
+}
+
+func (s *DoctorService) GetMyPreference(ctx context.Context, userID int64, year int, month int) (*dto.DoctorPreferenceDTO, error) {
+	if month < 1 || month > 12 {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Geçersiz ay")
+	}
+
+	doctor, err := s.currentDoctor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preference, err := s.doctorRepo.GetPreference(ctx, doctor.ID, year, month)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.ErrDatabaseOperation
+		}
+		preference = &model.DoctorPreference{DoctorID: doctor.ID, Year: year, Month: month, Weight: 1}
+	}
+
+	return preferenceResponse(*preference), nil
+}
+
+// Tercihleri kaydeder. Ayın tercih son tarihi geçtiyse değişiklik kabul edilmez.
+func (s *DoctorService) UpdateMyPreference(ctx context.Context, userID int64, year int, month int, req dto.DoctorPreferenceRequest) (*dto.DoctorPreferenceDTO, error) {
+	if month < 1 || month > 12 {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Geçersiz ay")
+	}
+
+	doctor, err := s.currentDoctor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkPreferenceDeadline(year, month); err != nil {
+		return nil, err
+	}
+
+	preference := req.ToDBModel(model.DoctorPreference{DoctorID: doctor.ID, Year: year, Month: month})
+	if err = normalizePreference(&preference); err != nil {
+		return nil, err
+	}
+
+	if err = s.doctorRepo.UpsertPreference(ctx, &preference); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	return preferenceResponse(preference), nil
+}
+
+func (s *DoctorService) DeleteMyPreference(ctx context.Context, userID int64, year int, month int) error {
+	doctor, err := s.currentDoctor(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = checkPreferenceDeadline(year, month); err != nil {
+		return err
+	}
+
+	if err = s.doctorRepo.DeletePreference(ctx, doctor.ID, year, month); err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	return nil
+}
+
+// Ayın tercihlerinin değiştirilebileceği son an
+func preferenceDeadline(year int, month int) time.Time {
+	loc, err := time.LoadLocation(policy.DefaultTimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc).AddDate(0, 0, -preferenceDeadlineDays)
+}
+
+func checkPreferenceDeadline(year int, month int) error {
+	deadline := preferenceDeadline(year, month)
+	if !time.Now().Before(deadline) {
+		return errorx.WithDetails(errorx.ErrDeadlinePassed, fmt.Sprintf("Tercihler en geç %s tarihine kadar değiştirilebilir", deadline.Format("2006-01-02")))
+	}
+	return nil
+}
+
+func preferenceResponse(preference model.DoctorPreference) *dto.DoctorPreferenceDTO {
+	result := dto.DoctorPreferenceDTO{}.ToResponseModel(preference)
+	result.Deadline = preferenceDeadline(preference.Year, preference.Month)
+	result.Editable = time.Now().Before(result.Deadline)
+	return result
+}
+
+// Tercihleri doğrular, tekrarları ayıklar ve ağırlığı varsayılana çeker
+func normalizePreference(p *model.DoctorPreference) error {
+	weekdays := make([]int, 0, len(p.PreferredWeekdays))
+	for _, w := range p.PreferredWeekdays {
+		if w < 0 || w > 6 {
+			return errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("Geçersiz gün: %d", w))
+		}
+		if !slices.Contains(weekdays, w) {
+			weekdays = append(weekdays, w)
+		}
+	}
+	p.PreferredWeekdays = weekdays
+
+	dates := make([]time.Time, 0, len(p.AvoidDates))
+	for _, d := range p.AvoidDates {
+		day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+		if day.Year() != p.Year || int(day.Month()) != p.Month {
+			return errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("%s tercih edilen aya ait değil", day.Format("2006-01-02")))
+		}
+		if !slices.ContainsFunc(dates, day.Equal) {
+			dates = append(dates, day)
+		}
+	}
+	p.AvoidDates = dates
+
+	templates := make([]int64, 0, len(p.PreferredTemplates))
+	for _, id := range p.PreferredTemplates {
+		if id <= 0 {
+			return errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("Geçersiz şablon: %d", id))
+		}
+		if !slices.Contains(templates, id) {
+			templates = append(templates, id)
+		}
+	}
+	p.PreferredTemplates = templates
+
+	if p.Weight == 0 {
+		p.Weight = 1
+	}
+	if p.Weight < 0 || p.Weight > maxPreferenceWeight {
+		return errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("Ağırlık 0 ile %g arasında olmalı", maxPreferenceWeight))
+	}
+
+	return nil
+}
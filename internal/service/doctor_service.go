@@ -11,12 +11,14 @@ import (
 type DoctorService struct {
 	doctorRepo *repository.DoctorRepository
 	userRepo   *repository.UserRepository
+	audit      *AuditService
 }
 
-func NewDoctorService(doctorRepo *repository.DoctorRepository, userRepo *repository.UserRepository) *DoctorService {
+func NewDoctorService(doctorRepo *repository.DoctorRepository, userRepo *repository.UserRepository, audit *AuditService) *DoctorService {
 	return &DoctorService{
 		doctorRepo: doctorRepo,
 		userRepo:   userRepo,
+		audit:      audit,
 	}
 }
 
@@ -86,6 +88,7 @@ func (s *DoctorService) Create(ctx context.Context, req *dto.CreateDoctorDTO) er
 	if err = s.doctorRepo.Create(ctx, &doctor); err != nil {
 		return errorx.ErrDatabaseOperation
 	}
+	s.audit.Record(ctx, model.AuditActionCreate, model.AuditEntityDoctor, doctor.ID, nil, dto.DoctorResponseDTO{}.ToResponseModel(doctor))
 
 	return nil
 }
@@ -120,18 +123,26 @@ func (s *DoctorService) Update(ctx context.Context, id int64, req *dto.CreateDoc
 		return errorx.ErrNotFound
 	}
 
+	before := dto.DoctorResponseDTO{}.ToResponseModel(*doctor)
 	req.ToDBModel(*doctor)
 
 	if err = s.doctorRepo.Update(ctx, doctor); err != nil {
 		return errorx.ErrDatabaseOperation
 	}
+	s.audit.Record(ctx, model.AuditActionUpdate, model.AuditEntityDoctor, doctor.ID, before, dto.DoctorResponseDTO{}.ToResponseModel(*doctor))
 
 	return nil
 }
 
 func (s *DoctorService) Delete(ctx context.Context, id int64) error {
+	var before any
+	if doctor, err := s.doctorRepo.GetByID(ctx, id); err == nil {
+		before = dto.DoctorResponseDTO{}.ToResponseModel(*doctor)
+	}
+
 	if err := s.doctorRepo.Delete(ctx, id); err != nil {
 		return errorx.ErrDatabaseOperation
 	}
+	s.audit.Record(ctx, model.AuditActionDelete, model.AuditEntityDoctor, id, before, nil)
 	return nil
 }
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/internal/scheduler"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/ics"
+	"strings"
+	"time"
+)
+
+// Lokasyon için bir resmi tatil günü
+type publicHoliday struct {
+	name            string
+	weight          float64
+	doctorsRequired *int
+}
+
+// Tarih (2006-01-02) -> resmi tatil
+type publicHolidays map[string]publicHoliday
+
+// Slot resmi tatile denk geliyorsa türünü, ağırlığını ve tatil adını günceller
+func (p publicHolidays) apply(slot *scheduler.Slot) {
+	h, ok := p[slot.Date.Format("2006-01-02")]
+	if !ok {
+		return
+	}
+	slot.Kind = scheduler.PublicHoliday
+	slot.Weight = h.weight
+	slot.Holiday = h.name
+}
+
+// Lokasyonun takvimlerinden aralığa düşen resmi tatiller. Aynı gün birden fazla takvimde
+// varsa yüksek ağırlık ve yüksek doktor ihtiyacı geçerlidir.
+func (s *ShiftService) publicHolidays(ctx context.Context, locationID int64, start time.Time, end time.Time) (publicHolidays, error) {
+	links, err := s.calendarRepo.GetLocationCalendars(ctx, locationID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	if len(links) == 0 {
+		return publicHolidays{}, nil
+	}
+
+	entries, err := s.calendarRepo.GetEntriesForLocation(ctx, locationID, start, end)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	settings := make(map[int64]model.LocationHolidayCalendar, len(links))
+	for _, link := range links {
+		settings[link.CalendarID] = link
+	}
+
+	result := make(publicHolidays)
+	for _, entry := range entries {
+		link := settings[entry.CalendarID]
+		weight := link.Weight
+		if weight <= 0 {
+			weight = publicHolidayWeight
+		}
+
+		for _, day := range holidayDates(entry, start, end) {
+			key := day.Format("2006-01-02")
+			h, ok := result[key]
+			if !ok {
+				result[key] = publicHoliday{name: entry.Name, weight: weight, doctorsRequired: link.DoctorsRequired}
+				continue
+			}
+			h.weight = max(h.weight, weight)
+			if link.DoctorsRequired != nil && (h.doctorsRequired == nil || *link.DoctorsRequired > *h.doctorsRequired) {
+				h.doctorsRequired = link.DoctorsRequired
+			}
+			result[key] = h
+		}
+	}
+	return result, nil
+}
+
+// Kaydın aralığa düşen tarihleri. Tekrarlanan kayıtlar ilk tarihlerinden itibaren her yıl geçerlidir.
+func holidayDates(entry model.PublicHoliday, start time.Time, end time.Time) []time.Time {
+	date := entry.HolidayDate
+	if !entry.Recurring {
+		if !date.Before(start) && date.Before(end) {
+			return []time.Time{date}
+		}
+		return nil
+	}
+
+	var dates []time.Time
+	for year := max(date.Year(), start.Year()); year <= end.Year(); year++ {
+		day := time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+		// 29 Şubat artık olmayan yıllarda atlanır
+		if day.Month() != date.Month() {
+			continue
+		}
+		if !day.Before(start) && day.Before(end) {
+			dates = append(dates, day)
+		}
+	}
+	return dates
+}
+
+type HolidayCalendarService struct {
+	calendarRepo *repository.HolidayCalendarRepository
+	audit        *AuditService
+}
+
+func NewHolidayCalendarService(calendarRepo *repository.HolidayCalendarRepository, audit *AuditService) *HolidayCalendarService {
+	return &HolidayCalendarService{calendarRepo: calendarRepo, audit: audit}
+}
+
+func (s *HolidayCalendarService) List(ctx context.Context) ([]dto.HolidayCalendarDTO, error) {
+	calendars, err := s.calendarRepo.List(ctx)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]dto.HolidayCalendarDTO, len(calendars))
+	for i, calendar := range calendars {
+		result[i] = dto.HolidayCalendarDTO{}.ToResponseModel(calendar)
+	}
+	return result, nil
+}
+
+func (s *HolidayCalendarService) GetByID(ctx context.Context, id int64) (*dto.HolidayCalendarDTO, error) {
+	calendar, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := dto.HolidayCalendarDTO{}.ToResponseModel(*calendar)
+	return &result, nil
+}
+
+func (s *HolidayCalendarService) Create(ctx context.Context, req dto.HolidayCalendarRequest) (*dto.HolidayCalendarDTO, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Takvim adı zorunludur")
+	}
+
+	calendar := req.ToDBModel(model.HolidayCalendar{})
+	if err := s.calendarRepo.Create(ctx, &calendar); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return s.changed(ctx, model.AuditActionCreate, calendar.ID, nil)
+}
+
+func (s *HolidayCalendarService) Update(ctx context.Context, id int64, req dto.HolidayCalendarRequest) (*dto.HolidayCalendarDTO, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Takvim adı zorunludur")
+	}
+
+	calendar, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := dto.HolidayCalendarDTO{}.ToResponseModel(*calendar)
+
+	*calendar = req.ToDBModel(*calendar)
+	if err = s.calendarRepo.Update(ctx, calendar); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return s.changed(ctx, model.AuditActionUpdate, id, before)
+}
+
+func (s *HolidayCalendarService) Delete(ctx context.Context, id int64) error {
+	calendar, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = s.calendarRepo.Delete(ctx, id); err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	s.audit.Record(ctx, model.AuditActionDelete, model.AuditEntityHolidayCalendar, id, dto.HolidayCalendarDTO{}.ToResponseModel(*calendar), nil)
+	return nil
+}
+
+// Takvime elle tatil günü ekler, aynı tarihte kayıt varsa günceller
+func (s *HolidayCalendarService) AddEntry(ctx context.Context, calendarID int64, req dto.PublicHolidayRequest) (*dto.HolidayCalendarDTO, error) {
+	calendar, err := s.load(ctx, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Name) == "" || req.HolidayDate.IsZero() {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Tatil adı ve tarihi zorunludur")
+	}
+
+	entry := req.ToDBModel(model.PublicHoliday{CalendarID: calendarID})
+	entry.HolidayDate = time.Date(entry.HolidayDate.Year(), entry.HolidayDate.Month(), entry.HolidayDate.Day(), 0, 0, 0, 0, time.UTC)
+	if err = s.calendarRepo.UpsertEntries(ctx, []model.PublicHoliday{entry}); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return s.changed(ctx, model.AuditActionUpdate, calendarID, dto.HolidayCalendarDTO{}.ToResponseModel(*calendar))
+}
+
+func (s *HolidayCalendarService) DeleteEntry(ctx context.Context, calendarID int64, entryID int64) error {
+	calendar, err := s.load(ctx, calendarID)
+	if err != nil {
+		return err
+	}
+	if err = s.calendarRepo.DeleteEntry(ctx, calendarID, entryID); err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	_, err = s.changed(ctx, model.AuditActionUpdate, calendarID, dto.HolidayCalendarDTO{}.ToResponseModel(*calendar))
+	return err
+}
+
+// ICS dosyasındaki etkinlikleri takvime aktarır. Birden fazla gün süren etkinlikler
+// her gün için ayrı kayıt olur, aynı tarihteki mevcut kayıtlar güncellenir.
+func (s *HolidayCalendarService) Import(ctx context.Context, calendarID int64, r io.Reader) (*dto.HolidayImportResultDTO, error) {
+	calendar, err := s.load(ctx, calendarID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := ics.Parse(r)
+	if err != nil {
+		return nil, errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("ICS dosyası okunamadı: %v", err))
+	}
+
+	byDate := make(map[string]model.PublicHoliday)
+	for _, event := range events {
+		name := strings.TrimSpace(event.Summary)
+		if name == "" {
+			continue
+		}
+		for _, day := range event.Days() {
+			byDate[day.Format("2006-01-02")] = model.PublicHoliday{
+				CalendarID:  calendarID,
+				UID:         event.UID,
+				Name:        name,
+				HolidayDate: day,
+				Recurring:   event.Yearly,
+			}
+		}
+	}
+
+	entries := make([]model.PublicHoliday, 0, len(byDate))
+	for _, entry := range byDate {
+		entries = append(entries, entry)
+	}
+	if err = s.calendarRepo.UpsertEntries(ctx, entries); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	if _, err = s.changed(ctx, model.AuditActionImport, calendarID, dto.HolidayCalendarDTO{}.ToResponseModel(*calendar)); err != nil {
+		return nil, err
+	}
+
+	return &dto.HolidayImportResultDTO{CalendarID: calendarID, Events: len(events), Days: len(entries)}, nil
+}
+
+func (s *HolidayCalendarService) GetLocationCalendars(ctx context.Context, locationID int64) ([]dto.LocationHolidayCalendarDTO, error) {
+	links, err := s.calendarRepo.GetLocationCalendars(ctx, locationID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]dto.LocationHolidayCalendarDTO, len(links))
+	for i, link := range links {
+		result[i] = dto.LocationHolidayCalendarDTO{}.ToResponseModel(link)
+	}
+	return result, nil
+}
+
+// Takvimi lokasyona bağlar ya da lokasyona özel ayarlarını günceller
+func (s *HolidayCalendarService) SetLocationCalendar(ctx context.Context, locationID int64, calendarID int64, req dto.LocationHolidayCalendarRequest) ([]dto.LocationHolidayCalendarDTO, error) {
+	if _, err := s.load(ctx, calendarID); err != nil {
+		return nil, err
+	}
+	if req.DoctorsRequired != nil && *req.DoctorsRequired < 0 {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Doktor sayısı negatif olamaz")
+	}
+	if req.Weight < 0 {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Ağırlık negatif olamaz")
+	}
+
+	link := req.ToDBModel(model.LocationHolidayCalendar{LocationID: locationID, CalendarID: calendarID})
+	if err := s.calendarRepo.UpsertLocationCalendar(ctx, &link); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return s.GetLocationCalendars(ctx, locationID)
+}
+
+func (s *HolidayCalendarService) DeleteLocationCalendar(ctx context.Context, locationID int64, calendarID int64) error {
+	if err := s.calendarRepo.DeleteLocationCalendar(ctx, locationID, calendarID); err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	return nil
+}
+
+// Takvimin güncel halini okur ve değişikliği önceki haliyle birlikte denetim kaydına yazar
+func (s *HolidayCalendarService) changed(ctx context.Context, action string, id int64, before any) (*dto.HolidayCalendarDTO, error) {
+	after, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, action, model.AuditEntityHolidayCalendar, id, before, after)
+	return after, nil
+}
+
+func (s *HolidayCalendarService) load(ctx context.Context, id int64) (*model.HolidayCalendar, error) {
+	calendar, err := s.calendarRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.ErrNotFound
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return calendar, nil
+}
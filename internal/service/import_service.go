@@ -0,0 +1,624 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/pkg/errorx"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// İçe aktarılabilecek dosyalar. Sıra önemlidir: üyelik ve tatiller aynı içe aktarmada
+// eklenen doktorlara başvurabilir.
+const (
+	ImportFileDoctors     = "doctors"
+	ImportFileMemberships = "memberships"
+	ImportFileHolidays    = "holidays"
+)
+
+const (
+	importMaxRows        = 5000
+	importMaxHolidayDays = 366
+	importMinPassword    = 6
+)
+
+// Sütunlar başlık satırındaki adlarıyla eşleşir, sırası önemli değildir; bilinmeyen sütunlar yok sayılır.
+//
+//	doctors:     email*, title*, specialization*, shift_limit; yeni kullanıcılar için name*, surname*, phone*, password*
+//	memberships: email*, location* (ID ya da ad), effective_from, effective_to
+//	holidays:    email*, location* (ID ya da ad), date*, end_date
+var importColumns = map[string][]string{
+	ImportFileDoctors:     {"email", "title", "specialization"},
+	ImportFileMemberships: {"email", "location"},
+	ImportFileHolidays:    {"email", "location", "date"},
+}
+
+type ImportInput struct {
+	Doctors     io.Reader
+	Memberships io.Reader
+	Holidays    io.Reader
+	SkipInvalid bool // hatalı satırlar atlanır, geçerli satırlar uygulanır
+}
+
+type ImportService struct {
+	importRepo   *repository.ImportRepository
+	userRepo     *repository.UserRepository
+	doctorRepo   *repository.DoctorRepository
+	locationRepo *repository.LocationRepository
+	audit        *AuditService
+}
+
+func NewImportService(importRepo *repository.ImportRepository, userRepo *repository.UserRepository, doctorRepo *repository.DoctorRepository, locationRepo *repository.LocationRepository, audit *AuditService) *ImportService {
+	return &ImportService{
+		importRepo:   importRepo,
+		userRepo:     userRepo,
+		doctorRepo:   doctorRepo,
+		locationRepo: locationRepo,
+		audit:        audit,
+	}
+}
+
+// Dosyaların tüm satırlarını doğrular, ardından kayıtları tek işlemde ekler. Hatalı satır varsa
+// hiçbir şey eklenmez ve her satırın hataları döner; SkipInvalid ile yalnızca geçerli satırlar eklenir.
+func (s *ImportService) Import(ctx context.Context, input ImportInput) (*dto.ImportReportDTO, error) {
+	var files []*importFile
+	for _, f := range []struct {
+		name string
+		r    io.Reader
+	}{{ImportFileDoctors, input.Doctors}, {ImportFileMemberships, input.Memberships}, {ImportFileHolidays, input.Holidays}} {
+		if f.r == nil {
+			continue
+		}
+		file, err := readImportFile(f.name, f.r)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil, errorx.WithDetails(errorx.ErrInvalidRequest, "doctors, memberships ya da holidays dosyalarından en az biri gönderilmelidir")
+	}
+
+	plan, err := s.newImportPlan(ctx, files)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		switch file.name {
+		case ImportFileDoctors:
+			plan.validateDoctors(file)
+		case ImportFileMemberships:
+			plan.validateMemberships(file)
+		case ImportFileHolidays:
+			if err = plan.validateHolidays(ctx, file); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var details []errorx.Detail
+	for _, file := range files {
+		for _, e := range file.errors {
+			field := fmt.Sprintf("%s[%d]", file.name, e.Row)
+			if e.Column != "" {
+				field += "." + e.Column
+			}
+			details = append(details, errorx.Detail{Field: field, Code: "invalid", Message: e.Message})
+		}
+	}
+	if len(details) > 0 && !input.SkipInvalid {
+		return nil, errorx.WithDetailList(errorx.ErrValidation, details...)
+	}
+
+	// Denetim kaydı için hangi kullanıcıların yeni olduğu eklemeden önce belirlenir
+	newUsers := make([]bool, len(plan.batch.Doctors))
+	for i, d := range plan.batch.Doctors {
+		newUsers[i] = d.User.ID == 0
+	}
+	if err = s.importRepo.Apply(ctx, &plan.batch); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	for i, d := range plan.batch.Doctors {
+		if newUsers[i] {
+			s.audit.Record(ctx, model.AuditActionImport, model.AuditEntityUser, d.User.ID, nil, dto.UserResponseDTO{}.ToResponseModel(*d.User))
+		}
+		doctor := *d.Doctor
+		doctor.User = *d.User
+		s.audit.Record(ctx, model.AuditActionImport, model.AuditEntityDoctor, doctor.ID, nil, dto.DoctorResponseDTO{}.ToResponseModel(doctor))
+	}
+
+	report := &dto.ImportReportDTO{Files: make([]dto.ImportFileReportDTO, len(files))}
+	for i, file := range files {
+		report.Files[i] = dto.ImportFileReportDTO{
+			File:     file.name,
+			Rows:     len(file.rows),
+			Imported: len(file.rows) - len(file.invalid),
+			Skipped:  file.errors,
+		}
+	}
+	return report, nil
+}
+
+type importRow struct {
+	line   int
+	fields map[string]string
+}
+
+func (r importRow) get(column string) string {
+	return strings.TrimSpace(r.fields[column])
+}
+
+type importFile struct {
+	name    string
+	rows    []importRow
+	errors  []dto.ImportRowErrorDTO
+	invalid map[int]bool
+}
+
+func (f *importFile) fail(row importRow, column string, message string) {
+	f.errors = append(f.errors, dto.ImportRowErrorDTO{Row: row.line, Column: column, Message: message})
+	f.invalid[row.line] = true
+}
+
+// CSV dosyasını başlık satırına göre okur. Eksik sütun ya da okunamayan dosya tüm dosyayı geçersiz kılar.
+func readImportFile(name string, r io.Reader) (*importFile, error) {
+	fileError := func(code string, message string) error {
+		return errorx.WithDetailList(errorx.ErrValidation, errorx.Detail{Field: name, Code: code, Message: message})
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fileError("empty", "Dosya boş")
+		}
+		return nil, fileError("unreadable", fmt.Sprintf("CSV okunamadı: %v", err))
+	}
+	columns := make([]string, len(header))
+	for i, column := range header {
+		columns[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\uFEFF")))
+	}
+	for _, required := range importColumns[name] {
+		found := false
+		for _, column := range columns {
+			found = found || column == required
+		}
+		if !found {
+			return nil, fileError("missing_column", fmt.Sprintf("%s sütunu eksik", required))
+		}
+	}
+
+	file := &importFile{name: name, invalid: make(map[int]bool)}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fileError("unreadable", fmt.Sprintf("CSV okunamadı: %v", err))
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := importRow{line: line, fields: make(map[string]string, len(columns))}
+		blank := true
+		for i, value := range record {
+			if i < len(columns) && columns[i] != "" {
+				row.fields[columns[i]] = value
+			}
+			blank = blank && strings.TrimSpace(value) == ""
+		}
+		if blank {
+			continue
+		}
+		if len(file.rows) == importMaxRows {
+			return nil, fileError("too_many_rows", fmt.Sprintf("Dosya en fazla %d satır içerebilir", importMaxRows))
+		}
+		file.rows = append(file.rows, row)
+	}
+	return file, nil
+}
+
+// Doğrulama sırasında mevcut kayıtlar ile dosyalardan kabul edilen satırları birlikte tutar.
+// Mevcut ve yeni doktorlar aynı işaretçi üzerinden izlenir.
+type importPlan struct {
+	users       map[string]*model.User   // küçük harf e-posta
+	phones      map[string]bool          // kullanılan telefonlar
+	doctors     map[string]*model.Doctor // küçük harf e-posta
+	rejected    map[string]int           // doctors dosyasında hatalı satırı olan e-posta -> satır
+	locations   map[int64]*model.ShiftLocation
+	names       map[string][]*model.ShiftLocation
+	memberships map[*model.Doctor][]model.DoctorShiftLocation
+	leaves      map[*model.Doctor]map[string]bool
+	doctorRepo  *repository.DoctorRepository
+	batch       repository.ImportBatch
+}
+
+func (s *ImportService) newImportPlan(ctx context.Context, files []*importFile) (*importPlan, error) {
+	var emails, phones []string
+	for _, file := range files {
+		for _, row := range file.rows {
+			if email := normalizeEmail(row.get("email")); email != "" {
+				emails = append(emails, email)
+			}
+			if phone := row.get("phone"); file.name == ImportFileDoctors && phone != "" {
+				phones = append(phones, phone)
+			}
+		}
+	}
+
+	users, err := s.userRepo.GetByEmailsOrPhones(ctx, emails, phones)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	plan := &importPlan{
+		users:       make(map[string]*model.User),
+		phones:      make(map[string]bool),
+		doctors:     make(map[string]*model.Doctor),
+		rejected:    make(map[string]int),
+		locations:   make(map[int64]*model.ShiftLocation),
+		names:       make(map[string][]*model.ShiftLocation),
+		memberships: make(map[*model.Doctor][]model.DoctorShiftLocation),
+		leaves:      make(map[*model.Doctor]map[string]bool),
+		doctorRepo:  s.doctorRepo,
+	}
+	userIDs := make([]int64, 0, len(users))
+	byID := make(map[int64]*model.User, len(users))
+	for i := range users {
+		user := &users[i]
+		plan.users[strings.ToLower(user.Email)] = user
+		if user.Phone != "" {
+			plan.phones[user.Phone] = true
+		}
+		userIDs = append(userIDs, user.ID)
+		byID[user.ID] = user
+	}
+
+	doctors, err := s.doctorRepo.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	doctorIDs := make([]int64, 0, len(doctors))
+	byDoctorID := make(map[int64]*model.Doctor, len(doctors))
+	for i := range doctors {
+		doctor := &doctors[i]
+		doctor.User = *byID[doctor.UserID]
+		plan.doctors[strings.ToLower(doctor.User.Email)] = doctor
+		doctorIDs = append(doctorIDs, doctor.ID)
+		byDoctorID[doctor.ID] = doctor
+	}
+
+	memberships, err := s.doctorRepo.GetMembershipsByDoctorIDs(ctx, doctorIDs)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	for _, m := range memberships {
+		doctor := byDoctorID[m.DoctorID]
+		plan.memberships[doctor] = append(plan.memberships[doctor], m)
+	}
+
+	locations, err := s.locationRepo.List(ctx, true)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	for i := range locations {
+		location := &locations[i]
+		plan.locations[location.ID] = location
+		name := strings.ToLower(strings.TrimSpace(location.Name))
+		plan.names[name] = append(plan.names[name], location)
+	}
+	return plan, nil
+}
+
+func (p *importPlan) validateDoctors(file *importFile) {
+	seen := make(map[string]int)
+	seenPhones := make(map[string]int)
+	for _, row := range file.rows {
+		email := normalizeEmail(row.get("email"))
+		switch {
+		case email == "":
+			file.fail(row, "email", "E-posta zorunludur")
+		case !validEmail(email):
+			file.fail(row, "email", "Geçersiz e-posta")
+		case seen[email] > 0:
+			file.fail(row, "email", fmt.Sprintf("E-posta dosyada %d. satırda da var", seen[email]))
+		}
+		if email != "" && seen[email] == 0 {
+			seen[email] = row.line
+		}
+
+		doctor := &model.Doctor{Title: row.get("title"), Specialization: row.get("specialization")}
+		if doctor.Title == "" {
+			file.fail(row, "title", "Unvan zorunludur")
+		}
+		if doctor.Specialization == "" {
+			file.fail(row, "specialization", "Uzmanlık zorunludur")
+		}
+		if value := row.get("shift_limit"); value != "" {
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 0 {
+				file.fail(row, "shift_limit", "Nöbet sınırı sıfır ya da pozitif bir tam sayı olmalıdır")
+			}
+			doctor.ShiftLimit = limit
+		}
+
+		user := p.users[email]
+		switch {
+		case user != nil && user.DeletedAt != nil:
+			file.fail(row, "email", "E-posta silinmiş bir kullanıcıya ait")
+		case user != nil && user.Role != model.UserRoleDoctor:
+			file.fail(row, "email", "Kullanıcının rolü doctor değil")
+		case user != nil && p.doctors[email] != nil:
+			file.fail(row, "email", "Kullanıcının zaten doktor kaydı var")
+		case user == nil:
+			user = &model.User{
+				Email:   email,
+				Name:    row.get("name"),
+				Surname: row.get("surname"),
+				Phone:   row.get("phone"),
+				Role:    model.UserRoleDoctor,
+				Status:  model.StatusActive,
+			}
+			if user.Name == "" {
+				file.fail(row, "name", "Yeni kullanıcı için ad zorunludur")
+			}
+			if user.Surname == "" {
+				file.fail(row, "surname", "Yeni kullanıcı için soyad zorunludur")
+			}
+			switch {
+			case user.Phone == "":
+				file.fail(row, "phone", "Yeni kullanıcı için telefon zorunludur")
+			case len(user.Phone) > 11 || strings.IndexFunc(user.Phone, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0:
+				file.fail(row, "phone", "Telefon en fazla 11 rakamdan oluşmalıdır")
+			case p.phones[user.Phone]:
+				file.fail(row, "phone", "Telefon başka bir kullanıcıya ait")
+			case seenPhones[user.Phone] > 0:
+				file.fail(row, "phone", fmt.Sprintf("Telefon dosyada %d. satırda da var", seenPhones[user.Phone]))
+			default:
+				seenPhones[user.Phone] = row.line
+			}
+			if password := row.get("password"); len(password) < importMinPassword {
+				file.fail(row, "password", fmt.Sprintf("Yeni kullanıcı için şifre en az %d karakter olmalıdır", importMinPassword))
+			} else if err := user.SetPassword(password); err != nil {
+				file.fail(row, "password", "Şifre işlenemedi")
+			}
+		}
+
+		if file.invalid[row.line] {
+			if email != "" {
+				p.rejected[email] = row.line
+			}
+			continue
+		}
+		doctor.User = *user
+		p.doctors[email] = doctor
+		p.batch.Doctors = append(p.batch.Doctors, repository.ImportDoctor{User: user, Doctor: doctor})
+	}
+}
+
+func (p *importPlan) validateMemberships(file *importFile) {
+	for _, row := range file.rows {
+		doctor := p.doctor(file, row)
+		location := p.location(file, row)
+		from := p.date(file, row, "effective_from")
+		to := p.date(file, row, "effective_to")
+		if from != nil && to != nil && to.Before(*from) {
+			file.fail(row, "effective_to", "Üyelik bitişi başlangıçtan önce olamaz")
+		}
+		if doctor == nil || location == nil || file.invalid[row.line] {
+			continue
+		}
+
+		member := model.DoctorShiftLocation{LocationID: location.ID, EffectiveFrom: from, EffectiveTo: to}
+		for _, existing := range p.memberships[doctor] {
+			if existing.LocationID == location.ID && periodsOverlap(existing.EffectiveFrom, existing.EffectiveTo, from, to) {
+				file.fail(row, "location", "Doktorun bu lokasyonda aynı dönemle çakışan bir üyeliği var")
+				break
+			}
+		}
+		if file.invalid[row.line] {
+			continue
+		}
+		p.memberships[doctor] = append(p.memberships[doctor], member)
+		p.batch.Memberships = append(p.batch.Memberships, repository.ImportMembership{Doctor: doctor, Member: member})
+	}
+}
+
+func (p *importPlan) validateHolidays(ctx context.Context, file *importFile) error {
+	type entry struct {
+		doctor   *model.Doctor
+		location *model.ShiftLocation
+		from, to time.Time
+	}
+	entries := make(map[int]entry)
+	var start, end time.Time
+	var doctorIDs []int64
+	for _, row := range file.rows {
+		doctor := p.doctor(file, row)
+		location := p.location(file, row)
+		from := p.date(file, row, "date")
+		if from == nil && !file.invalid[row.line] {
+			file.fail(row, "date", "Tarih zorunludur")
+		}
+		to := p.date(file, row, "end_date")
+		if to == nil {
+			to = from
+		}
+		if from != nil && to != nil {
+			switch {
+			case to.Before(*from):
+				file.fail(row, "end_date", "Bitiş tarihi başlangıçtan önce olamaz")
+			case to.Sub(*from) >= importMaxHolidayDays*24*time.Hour:
+				file.fail(row, "end_date", fmt.Sprintf("Bir satır en fazla %d günü kapsayabilir", importMaxHolidayDays))
+			}
+		}
+		if doctor == nil || location == nil || file.invalid[row.line] {
+			continue
+		}
+
+		entries[row.line] = entry{doctor: doctor, location: location, from: *from, to: *to}
+		if start.IsZero() || from.Before(start) {
+			start = *from
+		}
+		if to.After(end) {
+			end = *to
+		}
+		if doctor.ID != 0 {
+			doctorIDs = append(doctorIDs, doctor.ID)
+		}
+	}
+
+	// Mevcut izin günleri
+	if len(doctorIDs) > 0 {
+		existing, err := p.doctorRepo.GetHolidaysByDoctorIDs(ctx, doctorIDs, start, end.AddDate(0, 0, 1))
+		if err != nil {
+			return errorx.ErrDatabaseOperation
+		}
+		byID := make(map[int64]*model.Doctor)
+		for _, doctor := range p.doctors {
+			byID[doctor.ID] = doctor
+		}
+		for _, h := range existing {
+			p.take(byID[h.DoctorID], h.HolidayDate)
+		}
+	}
+
+	for _, row := range file.rows {
+		e, ok := entries[row.line]
+		if !ok {
+			continue
+		}
+
+		for day := e.from; !day.After(e.to); day = day.AddDate(0, 0, 1) {
+			if !p.member(e.doctor, e.location.ID, day) {
+				file.fail(row, "location", fmt.Sprintf("Doktor %s tarihinde lokasyonun üyesi değil", day.Format("2006-01-02")))
+				break
+			}
+			if p.leaves[e.doctor][day.Format("2006-01-02")] {
+				file.fail(row, "date", fmt.Sprintf("Doktorun %s tarihinde zaten izin kaydı var", day.Format("2006-01-02")))
+				break
+			}
+		}
+		if file.invalid[row.line] {
+			continue
+		}
+
+		for day := e.from; !day.After(e.to); day = day.AddDate(0, 0, 1) {
+			p.take(e.doctor, day)
+			p.batch.Holidays = append(p.batch.Holidays, repository.ImportHoliday{
+				Doctor:  e.doctor,
+				Holiday: model.Holiday{LocationID: e.location.ID, HolidayDate: day},
+			})
+		}
+	}
+	return nil
+}
+
+// Satırın e-postasındaki mevcut ya da bu içe aktarmada eklenecek doktor
+func (p *importPlan) doctor(file *importFile, row importRow) *model.Doctor {
+	email := normalizeEmail(row.get("email"))
+	if email == "" {
+		file.fail(row, "email", "E-posta zorunludur")
+		return nil
+	}
+	if doctor := p.doctors[email]; doctor != nil {
+		return doctor
+	}
+	if line, ok := p.rejected[email]; ok {
+		file.fail(row, "email", fmt.Sprintf("Doktor doctors dosyasının hatalı %d. satırında", line))
+		return nil
+	}
+	file.fail(row, "email", "Bu e-postaya ait doktor bulunamadı")
+	return nil
+}
+
+// Lokasyon ID'si ya da adı. Ad birden çok lokasyonla eşleşiyorsa ID kullanılmalıdır.
+func (p *importPlan) location(file *importFile, row importRow) *model.ShiftLocation {
+	value := row.get("location")
+	if value == "" {
+		file.fail(row, "location", "Lokasyon zorunludur")
+		return nil
+	}
+
+	var location *model.ShiftLocation
+	if id, err := strconv.ParseInt(value, 10, 64); err == nil {
+		location = p.locations[id]
+	} else if matches := p.names[strings.ToLower(value)]; len(matches) > 1 {
+		file.fail(row, "location", "Bu adda birden çok lokasyon var, ID kullanılmalıdır")
+		return nil
+	} else if len(matches) == 1 {
+		location = matches[0]
+	}
+
+	switch {
+	case location == nil:
+		file.fail(row, "location", "Lokasyon bulunamadı")
+		return nil
+	case location.ArchivedAt != nil:
+		file.fail(row, "location", "Lokasyon arşivlenmiş")
+		return nil
+	}
+	return location
+}
+
+// Boş olmayan tarih sütunu; 2006-01-02 ya da 02.01.2006 biçiminde olabilir
+func (p *importPlan) date(file *importFile, row importRow, column string) *time.Time {
+	value := row.get(column)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range []string{"2006-01-02", "02.01.2006"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	file.fail(row, column, "Tarih YYYY-AA-GG ya da GG.AA.YYYY biçiminde olmalıdır")
+	return nil
+}
+
+func (p *importPlan) member(doctor *model.Doctor, locationID int64, day time.Time) bool {
+	for _, m := range p.memberships[doctor] {
+		if m.LocationID == locationID && periodsOverlap(m.EffectiveFrom, m.EffectiveTo, &day, &day) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *importPlan) take(doctor *model.Doctor, day time.Time) {
+	if p.leaves[doctor] == nil {
+		p.leaves[doctor] = make(map[string]bool)
+	}
+	p.leaves[doctor][day.Format("2006-01-02")] = true
+}
+
+// İki uç dahil, boş sınırı açık iki dönemin kesişip kesişmediği
+func periodsOverlap(aFrom *time.Time, aTo *time.Time, bFrom *time.Time, bTo *time.Time) bool {
+	if aTo != nil && bFrom != nil && bFrom.After(*aTo) {
+		return false
+	}
+	if aFrom != nil && bTo != nil && bTo.Before(*aFrom) {
+		return false
+	}
+	return true
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func validEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
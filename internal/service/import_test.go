@@ -0,0 +1,54 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"shift-scheduling-v2/internal/service"
+	"shift-scheduling-v2/pkg/errorx"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func importError(t *testing.T, input service.ImportInput) *errorx.Error {
+	t.Helper()
+
+	// Dosya düzeyindeki hatalar veritabanına gitmeden döner
+	s := service.NewImportService(nil, nil, nil, nil, nil)
+	report, err := s.Import(context.Background(), input)
+	require.Nil(t, report)
+
+	var e *errorx.Error
+	require.True(t, errors.As(err, &e))
+	return e
+}
+
+func TestImportRequiresAtLeastOneFile(t *testing.T) {
+	e := importError(t, service.ImportInput{SkipInvalid: true})
+	assert.Equal(t, errorx.StatusBadRequest, e.Code)
+}
+
+func TestImportRejectsFileWithMissingColumn(t *testing.T) {
+	e := importError(t, service.ImportInput{
+		Doctors: strings.NewReader("\uFEFFEmail,Title\nali@example.com,Dr.\n"),
+	})
+	assert.Equal(t, errorx.StatusUnprocessableEntity, e.Code)
+	require.Len(t, e.Details, 1)
+	assert.Equal(t, "doctors", e.Details[0].Field)
+	assert.Equal(t, "missing_column", e.Details[0].Code)
+	assert.Contains(t, e.Details[0].Message, "specialization")
+}
+
+func TestImportRejectsEmptyAndMalformedFiles(t *testing.T) {
+	e := importError(t, service.ImportInput{Memberships: strings.NewReader("")})
+	require.Len(t, e.Details, 1)
+	assert.Equal(t, "memberships", e.Details[0].Field)
+	assert.Equal(t, "empty", e.Details[0].Code)
+
+	e = importError(t, service.ImportInput{Holidays: strings.NewReader("email,location,date\n\"ali@example.com,1,2025-03-01\n")})
+	require.Len(t, e.Details, 1)
+	assert.Equal(t, "holidays", e.Details[0].Field)
+	assert.Equal(t, "unreadable", e.Details[0].Code)
+}
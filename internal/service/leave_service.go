@@ -0,0 +1,530 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/leave"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/pkg/errorx"
+	"slices"
+	"time"
+)
+
+// Tek talepte istenebilecek en uzun izin
+const maxLeaveDays = 90
+
+var leaveTypes = []string{model.LeaveTypeAnnual, model.LeaveTypeSick, model.LeaveTypeConference, model.LeaveTypeCompensatory}
+
+type LeaveService struct {
+	leaveRepo     *repository.LeaveRepository
+	shiftRepo     *repository.ShiftRepository
+	doctorRepo    *repository.DoctorRepository
+	shiftService  *ShiftService
+	notifications *NotificationService
+	audit         *AuditService
+}
+
+func NewLeaveService(leaveRepo *repository.LeaveRepository, shiftRepo *repository.ShiftRepository, doctorRepo *repository.DoctorRepository, shiftService *ShiftService, notifications *NotificationService, audit *AuditService) *LeaveService {
+	return &LeaveService{
+		leaveRepo:     leaveRepo,
+		shiftRepo:     shiftRepo,
+		doctorRepo:    doctorRepo,
+		shiftService:  shiftService,
+		notifications: notifications,
+		audit:         audit,
+	}
+}
+
+// Doktorun izin talebi oluşturur. Talep edilen günlerdeki nöbetler talepte işaretlenir.
+func (s *LeaveService) Create(ctx context.Context, userID int64, req dto.LeaveCreateRequest) (*dto.LeaveResponseDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	leave := req.ToDBModel(model.LeaveRequest{DoctorID: doctor.ID, Status: model.LeaveStatusPending})
+	if err = s.normalize(ctx, &leave); err != nil {
+		return nil, err
+	}
+
+	overlap, err := s.leaveRepo.HasOverlap(ctx, doctor.ID, leave.StartDate, leave.EndDate)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	if overlap {
+		return nil, errorx.WithDetails(errorx.ErrDuplicate, "Bu tarihlerle çakışan bekleyen ya da onaylı bir izin talebiniz var")
+	}
+
+	if err = s.checkBalance(ctx, leave); err != nil {
+		return nil, err
+	}
+
+	if leave.ConflictingShiftIDs, err = s.conflicts(ctx, leave); err != nil {
+		return nil, err
+	}
+
+	if err = s.leaveRepo.Create(ctx, &leave); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return s.changed(ctx, model.AuditActionCreate, leave.ID, nil)
+}
+
+func (s *LeaveService) ListMine(ctx context.Context, userID int64) ([]dto.LeaveResponseDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	leaves, err := s.leaveRepo.ListByDoctor(ctx, doctor.ID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return leaveResponses(leaves), nil
+}
+
+func (s *LeaveService) Cancel(ctx context.Context, userID int64, id int64) (*dto.LeaveResponseDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	leave, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if leave.DoctorID != doctor.ID {
+		return nil, errorx.WithDetails(errorx.ErrForbidden, "Bu talep üzerinde işlem yapamazsınız")
+	}
+
+	before := dto.LeaveResponseDTO{}.ToResponseModel(*leave)
+	if err = s.update(ctx, leave, model.LeaveStatusCancelled); err != nil {
+		return nil, err
+	}
+	return s.changed(ctx, model.AuditActionCancel, leave.ID, before)
+}
+
+// Admin için izin talepleri, boş bırakılan filtreler uygulanmaz
+func (s *LeaveService) List(ctx context.Context, status string, locationID int64) ([]dto.LeaveResponseDTO, error) {
+	if status != "" && !slices.Contains([]string{model.LeaveStatusPending, model.LeaveStatusApproved, model.LeaveStatusRejected, model.LeaveStatusCancelled}, status) {
+		return nil, errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("Geçersiz durum: %s", status))
+	}
+
+	leaves, err := s.leaveRepo.List(ctx, status, locationID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return leaveResponses(leaves), nil
+}
+
+// Talebi onaylar ve her gün için tatil kaydı oluşturur. Çakışan nöbetler güncellenip yanıtta döner,
+// nöbetlerin yeniden atanması admine bırakılır.
+func (s *LeaveService) Approve(ctx context.Context, adminID int64, id int64, comment string) (*dto.LeaveResponseDTO, error) {
+	leave, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if leave.Status != model.LeaveStatusPending {
+		return nil, transitionError(leave.Status, model.LeaveStatusApproved)
+	}
+	// Talep oluşturulduktan sonra bakiye değişmiş olabilir
+	if err = s.checkBalance(ctx, *leave); err != nil {
+		return nil, err
+	}
+
+	if leave.ConflictingShiftIDs, err = s.conflicts(ctx, *leave); err != nil {
+		return nil, err
+	}
+
+	before := dto.LeaveResponseDTO{}.ToResponseModel(*leave)
+	now := time.Now()
+	leave.Status = model.LeaveStatusApproved
+	leave.ReviewedBy = adminID
+	leave.ReviewedAt = &now
+	leave.ReviewComment = comment
+
+	// Onaylanan talebin günleri tatil olarak işlenir
+	if err = s.leaveRepo.Approve(ctx, leave); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.WithDetails(errorx.ErrInvalidTransition, "Talep bu arada güncellenmiş")
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	s.notifications.HolidayApproved(ctx, leave.DoctorID, leave.StartDate, leave.EndDate)
+
+	return s.changed(ctx, model.AuditActionApprove, leave.ID, before)
+}
+
+func (s *LeaveService) Reject(ctx context.Context, adminID int64, id int64, comment string) (*dto.LeaveResponseDTO, error) {
+	leave, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := dto.LeaveResponseDTO{}.ToResponseModel(*leave)
+	now := time.Now()
+	leave.ReviewedBy = adminID
+	leave.ReviewedAt = &now
+	leave.ReviewComment = comment
+	if err = s.update(ctx, leave, model.LeaveStatusRejected); err != nil {
+		return nil, err
+	}
+	return s.changed(ctx, model.AuditActionReject, leave.ID, before)
+}
+
+// Doktorun yıl için izin bakiyeleri
+func (s *LeaveService) MyBalance(ctx context.Context, userID int64, year int) (*dto.DoctorLeaveBalanceDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := s.balances(ctx, []model.Doctor{*doctor}, year)
+	if err != nil {
+		return nil, err
+	}
+	return &balances[0], nil
+}
+
+// Lokasyondaki her doktorun yıl için izin bakiyeleri
+func (s *LeaveService) LocationBalances(ctx context.Context, locationID int64, year int) ([]dto.DoctorLeaveBalanceDTO, error) {
+	doctors, err := s.doctorRepo.GetByLocation(ctx, locationID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return s.balances(ctx, doctors, year)
+}
+
+func (s *LeaveService) GetEntitlements(ctx context.Context, doctorID int64) ([]dto.LeaveEntitlementDTO, error) {
+	if _, err := s.doctor(ctx, doctorID); err != nil {
+		return nil, err
+	}
+
+	entitlements, err := s.leaveRepo.GetEntitlements(ctx, []int64{doctorID})
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]dto.LeaveEntitlementDTO, len(entitlements))
+	for i, entitlement := range entitlements {
+		result[i] = dto.LeaveEntitlementDTO{}.ToResponseModel(entitlement)
+	}
+	return result, nil
+}
+
+// Doktorun izin türü için hakkını oluşturur ya da günceller
+func (s *LeaveService) SetEntitlement(ctx context.Context, doctorID int64, req dto.LeaveEntitlementRequest) (*dto.LeaveEntitlementDTO, error) {
+	if _, err := s.doctor(ctx, doctorID); err != nil {
+		return nil, err
+	}
+
+	entitlement := req.ToDBModel(model.LeaveEntitlement{DoctorID: doctorID})
+	if entitlement.Accrual == "" {
+		entitlement.Accrual = leave.AccrualUpfront
+	}
+	if entitlement.StartYear == 0 {
+		entitlement.StartYear = startOfToday().Year()
+	}
+
+	switch {
+	case !slices.Contains(leaveTypes, entitlement.LeaveType):
+		return nil, errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("Geçersiz izin türü: %s", entitlement.LeaveType))
+	case entitlement.Accrual != leave.AccrualUpfront && entitlement.Accrual != leave.AccrualMonthly:
+		return nil, errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("Geçersiz hak ediş yöntemi: %s", entitlement.Accrual))
+	case entitlement.AnnualDays < 0 || entitlement.AnnualDays > 366:
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Yıllık hak 0 ile 366 gün arasında olmalıdır")
+	case entitlement.CarryOverMax < 0:
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Devir sınırı negatif olamaz")
+	}
+
+	if err := s.leaveRepo.UpsertEntitlement(ctx, &entitlement); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := dto.LeaveEntitlementDTO{}.ToResponseModel(entitlement)
+	return &result, nil
+}
+
+// Hakkı siler, izin türü artık bakiyeyle sınırlanmaz
+func (s *LeaveService) DeleteEntitlement(ctx context.Context, doctorID int64, leaveType string) error {
+	if err := s.leaveRepo.DeleteEntitlement(ctx, doctorID, leaveType); err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	return nil
+}
+
+// Talep edilen günler bakiyeyi aşıyorsa her yıl için ayrı detay döner.
+// Talebin kendisi bekleyen günlerden düşülür.
+func (s *LeaveService) checkBalance(ctx context.Context, request model.LeaveRequest) error {
+	ledger, err := s.ledger(ctx, []int64{request.DoctorID}, request.EndDate.Year(), request.ID)
+	if err != nil {
+		return err
+	}
+
+	entitlement, ok := ledger.entitlement(request.DoctorID, request.LeaveType)
+	if !ok {
+		return nil
+	}
+
+	var details []errorx.Detail
+	requested := leave.DaysByYear(request.StartDate, request.EndDate)
+	for _, year := range slices.Sorted(maps.Keys(requested)) {
+		balance := ledger.balance(entitlement, year)
+		if requested[year] > balance.Available {
+			details = append(details, errorx.Detail{
+				Field:   "leave_type",
+				Code:    "insufficient_balance",
+				Message: fmt.Sprintf("%d yılı için %s izin bakiyesi %g gün, talep edilen %g gün", year, request.LeaveType, math.Max(0, balance.Available), requested[year]),
+			})
+		}
+	}
+
+	if len(details) > 0 {
+		return errorx.WithDetailList(errorx.ErrInsufficientBalance, details...)
+	}
+	return nil
+}
+
+func (s *LeaveService) balances(ctx context.Context, doctors []model.Doctor, year int) ([]dto.DoctorLeaveBalanceDTO, error) {
+	if year == 0 {
+		year = startOfToday().Year()
+	}
+
+	doctorIDs := make([]int64, len(doctors))
+	for i, doctor := range doctors {
+		doctorIDs[i] = doctor.ID
+	}
+	ledger, err := s.ledger(ctx, doctorIDs, year, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	byDoctor := make(map[int64][]dto.LeaveBalanceDTO)
+	for _, entitlement := range ledger.entitlements {
+		balance := ledger.balance(entitlement, year)
+		byDoctor[entitlement.DoctorID] = append(byDoctor[entitlement.DoctorID], dto.LeaveBalanceDTO{
+			LeaveEntitlementDTO: dto.LeaveEntitlementDTO{}.ToResponseModel(entitlement),
+			Year:                balance.Year,
+			Accrued:             balance.Accrued,
+			CarriedOver:         balance.CarriedOver,
+			Used:                balance.Used,
+			Pending:             balance.Pending,
+			Available:           balance.Available,
+		})
+	}
+
+	result := make([]dto.DoctorLeaveBalanceDTO, len(doctors))
+	for i, doctor := range doctors {
+		result[i] = dto.DoctorLeaveBalanceDTO{Year: year, Balances: byDoctor[doctor.ID]}.ToResponseModel(doctor)
+	}
+	return result, nil
+}
+
+type leaveKey struct {
+	doctorID  int64
+	leaveType string
+}
+
+// Bakiye hesabı için doktorların hakları, kullanılan ve bekleyen izin günleri
+type leaveLedger struct {
+	entitlements []model.LeaveEntitlement
+	used         map[leaveKey]map[int]float64
+	pending      map[leaveKey]map[int]float64
+	asOf         time.Time
+}
+
+// toYear'a kadar olan kullanımı yükler. excludeID verilirse o talep bekleyenlere sayılmaz.
+func (s *LeaveService) ledger(ctx context.Context, doctorIDs []int64, toYear int, excludeID int64) (*leaveLedger, error) {
+	entitlements, err := s.leaveRepo.GetEntitlements(ctx, doctorIDs)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	usage, err := s.leaveRepo.GetUsage(ctx, doctorIDs, toYear)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	pending, err := s.leaveRepo.GetPending(ctx, doctorIDs)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	ledger := &leaveLedger{
+		entitlements: entitlements,
+		used:         make(map[leaveKey]map[int]float64),
+		pending:      make(map[leaveKey]map[int]float64),
+		asOf:         startOfToday(),
+	}
+	for _, u := range usage {
+		key := leaveKey{u.DoctorID, u.LeaveType}
+		if ledger.used[key] == nil {
+			ledger.used[key] = make(map[int]float64)
+		}
+		ledger.used[key][u.Year] += u.Days
+	}
+	for _, request := range pending {
+		if request.ID == excludeID {
+			continue
+		}
+		key := leaveKey{request.DoctorID, request.LeaveType}
+		if ledger.pending[key] == nil {
+			ledger.pending[key] = make(map[int]float64)
+		}
+		for year, days := range leave.DaysByYear(request.StartDate, request.EndDate) {
+			ledger.pending[key][year] += days
+		}
+	}
+	return ledger, nil
+}
+
+func (l *leaveLedger) entitlement(doctorID int64, leaveType string) (model.LeaveEntitlement, bool) {
+	for _, entitlement := range l.entitlements {
+		if entitlement.DoctorID == doctorID && entitlement.LeaveType == leaveType {
+			return entitlement, true
+		}
+	}
+	return model.LeaveEntitlement{}, false
+}
+
+func (l *leaveLedger) balance(entitlement model.LeaveEntitlement, year int) leave.Balance {
+	key := leaveKey{entitlement.DoctorID, entitlement.LeaveType}
+	rule := leave.Entitlement{
+		AnnualDays:   entitlement.AnnualDays,
+		Accrual:      entitlement.Accrual,
+		CarryOverMax: entitlement.CarryOverMax,
+		StartYear:    entitlement.StartYear,
+	}
+	return rule.Balance(year, l.asOf, l.used[key], l.pending[key][year])
+}
+
+// Tarihleri güne yuvarlar, izin türünü, aralığı ve lokasyonu doğrular
+func (s *LeaveService) normalize(ctx context.Context, leave *model.LeaveRequest) error {
+	if !slices.Contains(leaveTypes, leave.LeaveType) {
+		return errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("Geçersiz izin türü: %s", leave.LeaveType))
+	}
+	if leave.StartDate.IsZero() {
+		return errorx.WithDetails(errorx.ErrValidation, "Başlangıç tarihi zorunludur")
+	}
+	if leave.EndDate.IsZero() {
+		leave.EndDate = leave.StartDate
+	}
+
+	leave.StartDate = time.Date(leave.StartDate.Year(), leave.StartDate.Month(), leave.StartDate.Day(), 0, 0, 0, 0, time.UTC)
+	leave.EndDate = time.Date(leave.EndDate.Year(), leave.EndDate.Month(), leave.EndDate.Day(), 0, 0, 0, 0, time.UTC)
+	if leave.EndDate.Before(leave.StartDate) {
+		return errorx.WithDetails(errorx.ErrValidation, "Bitiş tarihi başlangıçtan önce olamaz")
+	}
+	if days := int(leave.EndDate.Sub(leave.StartDate).Hours()/24) + 1; days > maxLeaveDays {
+		return errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("Tek talepte en fazla %d gün izin istenebilir", maxLeaveDays))
+	}
+	// Hastalık izni geriye dönük girilebilir, diğerleri önceden istenir
+	if leave.LeaveType != model.LeaveTypeSick && leave.StartDate.Before(startOfToday()) {
+		return errorx.WithDetails(errorx.ErrValidation, "Geçmiş tarihler için izin talep edilemez")
+	}
+
+	locations, err := s.doctorRepo.GetLocationIDs(ctx, leave.DoctorID)
+	if err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	switch {
+	case leave.LocationID == 0 && len(locations) == 1:
+		leave.LocationID = locations[0]
+	case leave.LocationID == 0:
+		return errorx.WithDetails(errorx.ErrValidation, "Birden fazla lokasyonunuz var, location_id zorunludur")
+	case !slices.Contains(locations, leave.LocationID):
+		return errorx.WithDetails(errorx.ErrForbidden, "Bu lokasyona kayıtlı değilsiniz")
+	}
+	return nil
+}
+
+// İzin günlerine denk gelen, yayınlanmış aylardaki doktor nöbetleri. Taslak aylardaki
+// nöbetler doktora görünmediğinden çakışma sayılmaz.
+func (s *LeaveService) conflicts(ctx context.Context, leave model.LeaveRequest) ([]int64, error) {
+	shifts, err := s.shiftRepo.GetShiftsByDoctorIDs(ctx, []int64{leave.DoctorID}, leave.StartDate, leave.EndDate.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	scope := newMonthScope(s.shiftService)
+	ids := make([]int64, 0, len(shifts))
+	for _, shift := range shifts {
+		visible, err := scope.published(ctx, shift.LocationID, shift.ShiftDate)
+		if err != nil {
+			return nil, err
+		}
+		if visible {
+			ids = append(ids, shift.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *LeaveService) update(ctx context.Context, leave *model.LeaveRequest, to string) error {
+	if leave.Status != model.LeaveStatusPending {
+		return transitionError(leave.Status, to)
+	}
+
+	from := leave.Status
+	leave.Status = to
+	if err := s.leaveRepo.UpdateStatus(ctx, leave, from); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorx.WithDetails(errorx.ErrInvalidTransition, "Talep bu arada güncellenmiş")
+		}
+		return errorx.ErrDatabaseOperation
+	}
+	return nil
+}
+
+func (s *LeaveService) doctor(ctx context.Context, id int64) (*model.Doctor, error) {
+	doctor, err := s.doctorRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.ErrNotFound
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return doctor, nil
+}
+
+func (s *LeaveService) load(ctx context.Context, id int64) (*model.LeaveRequest, error) {
+	leave, err := s.leaveRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.ErrNotFound
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return leave, nil
+}
+
+func (s *LeaveService) response(ctx context.Context, id int64) (*dto.LeaveResponseDTO, error) {
+	leave, err := s.leaveRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := dto.LeaveResponseDTO{}.ToResponseModel(*leave)
+	return &result, nil
+}
+
+// Talebin güncel halini okur ve değişikliği önceki haliyle birlikte denetim kaydına yazar
+func (s *LeaveService) changed(ctx context.Context, action string, id int64, before any) (*dto.LeaveResponseDTO, error) {
+	after, err := s.response(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, action, model.AuditEntityLeave, id, before, after)
+	return after, nil
+}
+
+func leaveResponses(leaves []model.LeaveRequest) []dto.LeaveResponseDTO {
+	result := make([]dto.LeaveResponseDTO, len(leaves))
+	for i, leave := range leaves {
+		result[i] = dto.LeaveResponseDTO{}.ToResponseModel(leave)
+	}
+	return result
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/policy"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/internal/scheduler"
+	"shift-scheduling-v2/pkg/errorx"
+	"slices"
+	"time"
+)
+
+// Lokasyon politikası ve çözümlenmiş saat dilimi
+type locationPolicy struct {
+	policy.Document
+	loc *time.Location
+}
+
+func newLocationPolicy(doc policy.Document) locationPolicy {
+	return locationPolicy{Document: doc, loc: doc.Location()}
+}
+
+// Günün türünü ve adalet hesabındaki ağırlığını döner. Resmi tatiller lokasyonun
+// takvimine bağlı olduğundan ayrıca publicHolidays.apply ile işaretlenir.
+func (p locationPolicy) dayWeight(day time.Time) (scheduler.DayKind, float64) {
+	if p.IsWeekend(day) {
+		return scheduler.Weekend, weekendWeight
+	}
+	return scheduler.Weekday, weekdayWeight
+}
+
+// Lokasyonun saat dilimine göre bugünün UTC gün başlangıcı
+func (p locationPolicy) today() time.Time {
+	now := time.Now().In(p.loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (p locationPolicy) restRules() scheduler.RestRules {
+	return scheduler.RestRules(p.RestRules)
+}
+
+func (s *ShiftService) policy(ctx context.Context, locationID int64) (locationPolicy, error) {
+	doc, _, err := loadPolicy(ctx, s.locationRepo, locationID)
+	if err != nil {
+		return locationPolicy{}, err
+	}
+	return newLocationPolicy(doc), nil
+}
+
+// Lokasyonun geçerli politikası ve sürümü. Kayıtlı politika yoksa varsayılan belge ve 0 döner.
+func loadPolicy(ctx context.Context, repo *repository.LocationRepository, locationID int64) (policy.Document, int, error) {
+	p, err := repo.GetPolicy(ctx, locationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return policy.Default(), 0, nil
+		}
+		return policy.Document{}, 0, errorx.ErrDatabaseOperation
+	}
+	return p.Document, p.Version, nil
+}
+
+// Belgeyi doğrular ve lokasyonun yeni politika sürümü olarak kaydeder
+func savePolicy(ctx context.Context, repo *repository.LocationRepository, locationID int64, doc policy.Document, userID int64) (*model.LocationPolicy, error) {
+	doc = normalizePolicy(doc)
+	if problems := doc.Validate(); len(problems) > 0 {
+		details := make([]errorx.Detail, len(problems))
+		for i, p := range problems {
+			details[i] = errorx.Detail{Field: p.Field, Code: "invalid", Message: p.Message}
+		}
+		return nil, errorx.WithDetailList(errorx.ErrValidation, details...)
+	}
+
+	p := model.LocationPolicy{LocationID: locationID, Document: doc, CreatedBy: userID}
+	if err := repo.CreatePolicy(ctx, &p); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return &p, nil
+}
+
+// Listeleri sıralar ve boş listeleri JSON'da null yerine [] olarak saklar
+func normalizePolicy(doc policy.Document) policy.Document {
+	doc.Coverage.Weekdays = slices.Clone(doc.Coverage.Weekdays)
+	if doc.Coverage.Weekdays == nil {
+		doc.Coverage.Weekdays = []policy.WeekdayCoverage{}
+	}
+	slices.SortStableFunc(doc.Coverage.Weekdays, func(a, b policy.WeekdayCoverage) int { return a.Weekday - b.Weekday })
+
+	doc.WeekendDays = slices.Clone(doc.WeekendDays)
+	if doc.WeekendDays == nil {
+		doc.WeekendDays = []int{}
+	}
+	slices.Sort(doc.WeekendDays)
+	return doc
+}
+
+func (s *LocationService) GetPolicy(ctx context.Context, locationID int64) (*dto.LocationPolicyDTO, error) {
+	if _, err := s.load(ctx, locationID); err != nil {
+		return nil, err
+	}
+
+	p, err := s.locationRepo.GetPolicy(ctx, locationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.LocationPolicyDTO{}.ToResponseModel(model.LocationPolicy{LocationID: locationID, Document: policy.Default()}), nil
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return dto.LocationPolicyDTO{}.ToResponseModel(*p), nil
+}
+
+// Politikayı yeni sürüm olarak kaydeder, önceki sürümler değişmeden kalır
+func (s *LocationService) UpdatePolicy(ctx context.Context, locationID int64, req dto.LocationPolicyRequest, userID int64) (*dto.LocationPolicyDTO, error) {
+	if _, err := s.load(ctx, locationID); err != nil {
+		return nil, err
+	}
+
+	p, err := savePolicy(ctx, s.locationRepo, locationID, req.ToDBModel(model.LocationPolicy{}).Document, userID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.LocationPolicyDTO{}.ToResponseModel(*p), nil
+}
+
+func (s *LocationService) ListPolicyVersions(ctx context.Context, locationID int64) ([]dto.LocationPolicyDTO, error) {
+	if _, err := s.load(ctx, locationID); err != nil {
+		return nil, err
+	}
+
+	policies, err := s.locationRepo.ListPolicyVersions(ctx, locationID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]dto.LocationPolicyDTO, len(policies))
+	for i, p := range policies {
+		result[i] = *dto.LocationPolicyDTO{}.ToResponseModel(p)
+	}
+	return result, nil
+}
+
+func (s *LocationService) GetPolicyVersion(ctx context.Context, locationID int64, version int) (*dto.LocationPolicyDTO, error) {
+	p, err := s.locationRepo.GetPolicyVersion(ctx, locationID, version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.ErrNotFound
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return dto.LocationPolicyDTO{}.ToResponseModel(*p), nil
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/pkg/errorx"
+	"strings"
+	"time"
+)
+
+type LocationService struct {
+	locationRepo *repository.LocationRepository
+	doctorRepo   *repository.DoctorRepository
+}
+
+func NewLocationService(locationRepo *repository.LocationRepository, doctorRepo *repository.DoctorRepository) *LocationService {
+	return &LocationService{
+		locationRepo: locationRepo,
+		doctorRepo:   doctorRepo,
+	}
+}
+
+func (s *LocationService) List(ctx context.Context, includeArchived bool) ([]dto.LocationDTO, error) {
+	locations, err := s.locationRepo.List(ctx, includeArchived)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]dto.LocationDTO, len(locations))
+	for i, location := range locations {
+		result[i] = dto.LocationDTO{}.ToResponseModel(location)
+	}
+	return result, nil
+}
+
+func (s *LocationService) GetByID(ctx context.Context, id int64) (*dto.LocationDTO, error) {
+	location, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := dto.LocationDTO{}.ToResponseModel(*location)
+	return &result, nil
+}
+
+func (s *LocationService) Create(ctx context.Context, req dto.LocationRequest) (*dto.LocationDTO, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Lokasyon adı zorunludur")
+	}
+
+	location := req.ToDBModel(model.ShiftLocation{})
+	if err := s.locationRepo.Create(ctx, &location); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return s.GetByID(ctx, location.ID)
+}
+
+func (s *LocationService) Update(ctx context.Context, id int64, req dto.LocationRequest) (*dto.LocationDTO, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Lokasyon adı zorunludur")
+	}
+
+	location, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	*location = req.ToDBModel(*location)
+	if err = s.locationRepo.Update(ctx, location); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return s.GetByID(ctx, id)
+}
+
+// Lokasyonu arşivler. Geçmiş nöbetler ve üyelikler korunur, lokasyon yeniden planlanamaz.
+func (s *LocationService) Archive(ctx context.Context, id int64) (*dto.LocationDTO, error) {
+	location, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if location.ArchivedAt != nil {
+		return nil, errorx.WithDetails(errorx.ErrInvalidTransition, "Lokasyon zaten arşivlenmiş")
+	}
+
+	now := time.Now()
+	if err = s.locationRepo.SetArchived(ctx, id, &now); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return s.GetByID(ctx, id)
+}
+
+func (s *LocationService) Restore(ctx context.Context, id int64) (*dto.LocationDTO, error) {
+	location, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if location.ArchivedAt == nil {
+		return nil, errorx.WithDetails(errorx.ErrInvalidTransition, "Lokasyon arşivde değil")
+	}
+
+	if err = s.locationRepo.SetArchived(ctx, id, nil); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return s.GetByID(ctx, id)
+}
+
+// Lokasyonun üyelikleri, day verilirse yalnızca o gün geçerli olanlar
+func (s *LocationService) ListMembers(ctx context.Context, locationID int64, day *time.Time) ([]dto.MembershipDTO, error) {
+	if _, err := s.load(ctx, locationID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.locationRepo.ListMembers(ctx, locationID, day)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]dto.MembershipDTO, len(members))
+	for i, member := range members {
+		result[i] = dto.MembershipDTO{}.ToResponseModel(member)
+	}
+	return result, nil
+}
+
+func (s *LocationService) AddMember(ctx context.Context, locationID int64, req dto.MembershipRequest) (*dto.MembershipDTO, error) {
+	location, err := s.load(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+	if location.ArchivedAt != nil {
+		return nil, errorx.WithDetails(errorx.ErrInvalidRequest, "Arşivlenmiş lokasyona doktor eklenemez")
+	}
+
+	if _, err = s.doctorRepo.GetByID(ctx, req.DoctorID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.WithDetails(errorx.ErrNotFound, "Doktor bulunamadı")
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	member := req.ToDBModel(model.DoctorShiftLocation{DoctorID: req.DoctorID, LocationID: locationID})
+	if err = s.checkMember(ctx, &member); err != nil {
+		return nil, err
+	}
+
+	if err = s.locationRepo.CreateMember(ctx, &member); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return s.member(ctx, locationID, member.ID)
+}
+
+func (s *LocationService) UpdateMember(ctx context.Context, locationID int64, id int64, req dto.MembershipRequest) (*dto.MembershipDTO, error) {
+	member, err := s.loadMember(ctx, locationID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	*member = req.ToDBModel(*member)
+	if err = s.checkMember(ctx, member); err != nil {
+		return nil, err
+	}
+
+	if err = s.locationRepo.UpdateMember(ctx, member); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return s.member(ctx, locationID, id)
+}
+
+// Doktoru lokasyondan çıkarır. Üyelik effectiveTo (boşsa lokasyonun saatine göre bugün) günü sonunda biter,
+// o tarihten sonra başlayacak üyelikler tamamen silinir.
+func (s *LocationService) RemoveMember(ctx context.Context, locationID int64, id int64, effectiveTo *time.Time) error {
+	member, err := s.loadMember(ctx, locationID, id)
+	if err != nil {
+		return err
+	}
+
+	doc, _, err := loadPolicy(ctx, s.locationRepo, locationID)
+	if err != nil {
+		return err
+	}
+
+	end := newLocationPolicy(doc).today()
+	if effectiveTo != nil {
+		end = truncateDay(*effectiveTo)
+	}
+
+	if member.EffectiveFrom != nil && member.EffectiveFrom.After(end) {
+		if err = s.locationRepo.DeleteMember(ctx, id); err != nil {
+			return errorx.ErrDatabaseOperation
+		}
+		return nil
+	}
+	if member.EffectiveTo != nil && !member.EffectiveTo.After(end) {
+		return nil
+	}
+
+	member.EffectiveTo = &end
+	if err = s.locationRepo.UpdateMember(ctx, member); err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	return nil
+}
+
+// Tarihleri güne yuvarlar, aralığı ve aynı lokasyondaki diğer üyeliklerle çakışmayı doğrular
+func (s *LocationService) checkMember(ctx context.Context, member *model.DoctorShiftLocation) error {
+	if member.EffectiveFrom != nil {
+		from := truncateDay(*member.EffectiveFrom)
+		member.EffectiveFrom = &from
+	}
+	if member.EffectiveTo != nil {
+		to := truncateDay(*member.EffectiveTo)
+		member.EffectiveTo = &to
+	}
+	if member.EffectiveFrom != nil && member.EffectiveTo != nil && member.EffectiveTo.Before(*member.EffectiveFrom) {
+		return errorx.WithDetails(errorx.ErrValidation, "Üyelik bitişi başlangıçtan önce olamaz")
+	}
+
+	overlap, err := s.locationRepo.HasOverlappingMember(ctx, *member)
+	if err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	if overlap {
+		return errorx.WithDetails(errorx.ErrDuplicate, "Doktorun bu lokasyonda aynı dönemle çakışan bir üyeliği var")
+	}
+	return nil
+}
+
+func (s *LocationService) member(ctx context.Context, locationID int64, id int64) (*dto.MembershipDTO, error) {
+	member, err := s.loadMember(ctx, locationID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := dto.MembershipDTO{}.ToResponseModel(*member)
+	return &result, nil
+}
+
+func (s *LocationService) loadMember(ctx context.Context, locationID int64, id int64) (*model.DoctorShiftLocation, error) {
+	member, err := s.locationRepo.GetMember(ctx, locationID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.ErrNotFound
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return member, nil
+}
+
+func (s *LocationService) load(ctx context.Context, id int64) (*model.ShiftLocation, error) {
+	location, err := s.locationRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.ErrNotFound
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return location, nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/realtime"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/logger"
+	"shift-scheduling-v2/pkg/notify"
+	"shift-scheduling-v2/pkg/query"
+	"slices"
+	"time"
+)
+
+// Bildirimlerin gönderimi için üst süre, tekrar denemeler dahil
+const notificationTimeout = 2 * time.Minute
+
+type NotificationService struct {
+	repo       *repository.NotificationRepository
+	doctorRepo *repository.DoctorRepository
+	dispatcher *notify.Dispatcher
+}
+
+func NewNotificationService(repo *repository.NotificationRepository, doctorRepo *repository.DoctorRepository, dispatcher *notify.Dispatcher) *NotificationService {
+	return &NotificationService{
+		repo:       repo,
+		doctorRepo: doctorRepo,
+		dispatcher: dispatcher,
+	}
+}
+
+// Bildirimi veritabanına yazan uygulama içi kanal
+type inAppChannel struct {
+	repo *repository.NotificationRepository
+}
+
+func NewInAppChannel(repo *repository.NotificationRepository) notify.Channel {
+	return inAppChannel{repo: repo}
+}
+
+func (c inAppChannel) Name() string {
+	return "in_app"
+}
+
+// Kaydedilen bildirim doktorun açık bağlantılarına da canlı olarak iletilir
+func (c inAppChannel) Send(ctx context.Context, msg notify.Message) error {
+	notification := &model.Notification{
+		Event:    msg.Event,
+		Title:    msg.Title,
+		Message:  msg.Body,
+		Data:     msg.Data,
+		DoctorID: msg.To.DoctorID,
+	}
+	if err := c.repo.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	realtime.Publish(ctx, realtime.Event{
+		Type:      realtime.EventNotification,
+		Audience:  realtime.AudiencePrivate,
+		DoctorIDs: []int64{msg.To.DoctorID},
+		Data:      dto.NotificationDTO{}.ToResponseModel(*notification),
+	})
+	return nil
+}
+
+func (s *NotificationService) GetMine(ctx context.Context, userID int64, unreadOnly bool, p query.Pagination) (*dto.NotificationListDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications, err := s.repo.ListByDoctor(ctx, doctor.ID, unreadOnly, &p)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	unread, err := s.repo.CountUnread(ctx, doctor.ID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := &dto.NotificationListDTO{
+		Items:       make([]dto.NotificationDTO, len(notifications)),
+		UnreadCount: unread,
+		Pagination:  query.GetPaginationResponse(p),
+	}
+	for i, n := range notifications {
+		result.Items[i] = dto.NotificationDTO{}.ToResponseModel(n)
+	}
+	return result, nil
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID int64, id int64) error {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return err
+	}
+
+	found, err := s.repo.MarkRead(ctx, doctor.ID, id)
+	if err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	if !found {
+		return errorx.ErrNotFound
+	}
+	return nil
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID int64) (int64, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.repo.MarkAllRead(ctx, doctor.ID)
+	if err != nil {
+		return 0, errorx.ErrDatabaseOperation
+	}
+	return count, nil
+}
+
+// Lokasyonun aylık nöbet listesi yayınlandığında listede nöbeti olan doktorlara bildirilir
+func (s *NotificationService) SchedulePublished(ctx context.Context, locationID int64, year int, month int, doctorIDs []int64) {
+	s.notify(ctx, doctorIDs, model.EventSchedulePublished,
+		"Nöbet listesi yayınlandı",
+		fmt.Sprintf("%02d/%d nöbet listesi yayınlandı.", month, year),
+		map[string]any{"location_id": locationID, "year": year, "month": month})
+}
+
+// Nöbet eklendiğinde, değiştiğinde ya da silindiğinde ilgili doktora bildirilir
+func (s *NotificationService) ShiftChanged(ctx context.Context, shift model.Shift, action string) {
+	var body string
+	switch action {
+	case "created":
+		body = "%s tarihine nöbet atandınız."
+	case "deleted":
+		body = "%s tarihli nöbetiniz kaldırıldı."
+	default:
+		body = "%s tarihli nöbetiniz güncellendi."
+	}
+
+	data := map[string]any{"location_id": shift.LocationID, "shift_date": shift.ShiftDate.Format("2006-01-02"), "action": action}
+	if shift.ID != 0 {
+		data["shift_id"] = shift.ID
+	}
+
+	s.notify(ctx, []int64{shift.DoctorID}, model.EventShiftChanged,
+		"Nöbet değişikliği",
+		fmt.Sprintf(body, shift.ShiftDate.Format("02.01.2006")),
+		data)
+}
+
+// Değişim talebi oluşturulduğunda karşı tarafa bildirilir
+func (s *NotificationService) SwapRequested(ctx context.Context, swap model.ShiftSwapRequest) {
+	s.notify(ctx, []int64{swap.AcceptorID}, model.EventSwapRequested,
+		"Nöbet değişim talebi",
+		fmt.Sprintf("%s tarihli nöbetiniz için %s tarihli nöbetle değişim talebi aldınız.",
+			swap.RequestShiftDate.Format("02.01.2006"), swap.OfferedShiftDate.Format("02.01.2006")),
+		map[string]any{"swap_id": swap.ID, "status": swap.Status})
+}
+
+// Değişim talebi sonuçlandığında ya da durumu değiştiğinde iki tarafa da bildirilir
+func (s *NotificationService) SwapAnswered(ctx context.Context, swap model.ShiftSwapRequest) {
+	s.notify(ctx, []int64{swap.RequesterID, swap.AcceptorID}, model.EventSwapAnswered,
+		"Nöbet değişim talebi güncellendi",
+		fmt.Sprintf("%s / %s tarihli nöbet değişim talebinin durumu: %s.",
+			swap.OfferedShiftDate.Format("02.01.2006"), swap.RequestShiftDate.Format("02.01.2006"), swap.Status),
+		map[string]any{"swap_id": swap.ID, "status": swap.Status})
+}
+
+// İlandaki nöbet alındığında ya da alım onaylanıp reddedildiğinde ilanı veren ve alan doktora bildirilir
+func (s *NotificationService) OfferClaimed(ctx context.Context, offer model.ShiftOffer, claimerID int64) {
+	s.notify(ctx, []int64{offer.OfferedBy, claimerID}, model.EventOfferClaimed,
+		"Nöbet ilanı güncellendi",
+		fmt.Sprintf("%s tarihli nöbet ilanının durumu: %s.", offer.ShiftDate.Format("02.01.2006"), offer.Status),
+		map[string]any{"offer_id": offer.ID, "status": offer.Status})
+}
+
+// İzin onaylandığında doktora bildirilir
+func (s *NotificationService) HolidayApproved(ctx context.Context, doctorID int64, from time.Time, to time.Time) {
+	s.notify(ctx, []int64{doctorID}, model.EventHolidayApproved,
+		"İzniniz onaylandı",
+		fmt.Sprintf("%s - %s tarihleri arasındaki izniniz onaylandı.", from.Format("02.01.2006"), to.Format("02.01.2006")),
+		map[string]any{"from": from.Format("2006-01-02"), "to": to.Format("2006-01-02")})
+}
+
+// Bildirimleri arka planda tüm kanallara gönderir. İsteği yapan işlem bildirim hatası yüzünden başarısız olmaz.
+// İsteğin context'i handler dönünce geri dönüştürüldüğünden gönderim ondan türetilmez.
+func (s *NotificationService) notify(_ context.Context, doctorIDs []int64, event string, title string, body string, data map[string]any) {
+	if s == nil || len(doctorIDs) == 0 {
+		return
+	}
+
+	ids := slices.Clone(doctorIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+	go func() {
+		defer cancel()
+
+		doctors, err := s.doctorRepo.GetByIDs(ctx, ids)
+		if err != nil && err != sql.ErrNoRows {
+			logger.Error("Bildirim alıcıları okunamadı (%s): %v", event, err)
+			return
+		}
+
+		now := time.Now()
+		for _, doctor := range doctors {
+			msg := notify.Message{
+				To: notify.Recipient{
+					DoctorID: doctor.ID,
+					UserID:   doctor.UserID,
+					Name:     doctor.User.String(),
+					Email:    doctor.User.Email,
+				},
+				Event:     event,
+				Title:     title,
+				Body:      body,
+				Data:      data,
+				CreatedAt: now,
+			}
+			if err := s.dispatcher.Dispatch(ctx, msg); err != nil {
+				logger.Error("Bildirim gönderilemedi (%s, doktor %d): %v", event, doctor.ID, err)
+			}
+		}
+	}()
+}
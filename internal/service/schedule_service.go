@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/realtime"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/internal/snapshot"
+	"shift-scheduling-v2/pkg/errorx"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Yayın işlemlerinin kaynak ve hedef durumları
+var scheduleActions = map[string]struct{ from, to string }{
+	model.ScheduleActionPublish:   {model.ScheduleStateDraft, model.ScheduleStatePublished},
+	model.ScheduleActionUnpublish: {model.ScheduleStatePublished, model.ScheduleStateDraft},
+	model.ScheduleActionLock:      {model.ScheduleStatePublished, model.ScheduleStateLocked},
+	model.ScheduleActionUnlock:    {model.ScheduleStateLocked, model.ScheduleStatePublished},
+}
+
+type ScheduleService struct {
+	shiftRepo     *repository.ShiftRepository
+	scheduleRepo  *repository.ScheduleRepository
+	doctorRepo    *repository.DoctorRepository
+	shiftService  *ShiftService
+	notifications *NotificationService
+}
+
+func NewScheduleService(shiftRepo *repository.ShiftRepository, scheduleRepo *repository.ScheduleRepository, doctorRepo *repository.DoctorRepository, shiftService *ShiftService, notifications *NotificationService) *ScheduleService {
+	return &ScheduleService{
+		shiftRepo:     shiftRepo,
+		scheduleRepo:  scheduleRepo,
+		doctorRepo:    doctorRepo,
+		shiftService:  shiftService,
+		notifications: notifications,
+	}
+}
+
+// Ayın durumu ve nöbetleri. Doktorlar sadece üyesi oldukları lokasyonun yayınlanmış aylarını görür.
+func (s *ScheduleService) Get(ctx context.Context, locationID int64, year int, month int, userID int64, role model.Role) (*dto.ScheduleDTO, error) {
+	if err := validMonth(month); err != nil {
+		return nil, err
+	}
+
+	status, err := s.status(ctx, locationID, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	if role != model.UserRoleAdmin {
+		doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+		if err != nil {
+			return nil, err
+		}
+		locations, err := s.doctorRepo.GetLocationIDs(ctx, doctor.ID)
+		if err != nil {
+			return nil, errorx.ErrDatabaseOperation
+		}
+		if !slices.Contains(locations, locationID) || !isVisible(status.State) {
+			return nil, errorx.WithDetails(errorx.ErrNotFound, "Nöbet listesi henüz yayınlanmadı")
+		}
+	}
+
+	shifts, err := s.shiftRepo.GetShiftsByLocationID(ctx, locationID, int64(month), int64(year))
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return dto.ScheduleDTO{}.ToResponseModel(*status, shifts), nil
+}
+
+// Ayı publish, unpublish, lock ya da unlock işlemiyle bir sonraki duruma geçirir.
+// Kilidi açmak gerekçe ister; yayınlama ayın nöbetlerini yeni sürüm olarak saklar.
+func (s *ScheduleService) Transition(ctx context.Context, locationID int64, year int, month int, action string, req dto.ScheduleTransitionRequest, userID int64) (*dto.ScheduleDTO, error) {
+	if err := validMonth(month); err != nil {
+		return nil, err
+	}
+
+	states, ok := scheduleActions[action]
+	if !ok {
+		return nil, errorx.WithDetails(errorx.ErrInvalidRequest, "Geçersiz işlem")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if action == model.ScheduleActionUnlock && reason == "" {
+		return nil, errorx.WithDetailList(errorx.ErrValidation, errorx.Detail{Field: "reason", Code: "required", Message: "Kilidi açmak için gerekçe zorunludur"})
+	}
+
+	status, err := s.status(ctx, locationID, year, month)
+	if err != nil {
+		return nil, err
+	}
+	if status.State != states.from {
+		return nil, transitionError(status.State, states.to)
+	}
+
+	t := model.ScheduleTransition{
+		LocationID: locationID,
+		Year:       year,
+		Month:      month,
+		Action:     action,
+		FromState:  states.from,
+		ToState:    states.to,
+		Reason:     reason,
+		CreatedBy:  userID,
+	}
+	if err = s.scheduleRepo.Transition(ctx, &t, action == model.ScheduleActionPublish); err != nil {
+		if errors.Is(err, repository.ErrScheduleStateChanged) {
+			return nil, errorx.WithDetails(errorx.ErrInvalidTransition, "Ayın durumu bu arada değişmiş")
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	s.record(ctx, t, status.Version)
+
+	switch action {
+	case model.ScheduleActionPublish:
+		if err = s.announce(ctx, locationID, year, month); err != nil {
+			return nil, err
+		}
+	case model.ScheduleActionUnpublish:
+		realtime.Publish(ctx, realtime.Event{
+			Type:       realtime.EventScheduleWithdrawn,
+			Audience:   realtime.AudienceLocation,
+			LocationID: locationID,
+			Data:       map[string]any{"year": year, "month": month},
+		})
+	}
+
+	return s.Get(ctx, locationID, year, month, userID, model.UserRoleAdmin)
+}
+
+// Ayın nöbetlerini verilen sürümdeki haline döndürür. Durum değişmez; ay yayındaysa
+// geri alınan hal yeni sürüm olarak yayınlanır. Kilitli ayda gerekçe zorunludur.
+func (s *ScheduleService) Rollback(ctx context.Context, locationID int64, year int, month int, req dto.ScheduleRollbackRequest, userID int64) (*dto.ScheduleDTO, error) {
+	if err := validMonth(month); err != nil {
+		return nil, err
+	}
+	if req.Version < 1 {
+		return nil, errorx.WithDetailList(errorx.ErrValidation, errorx.Detail{Field: "version", Code: "required", Message: "Dönülecek sürüm belirtilmeli"})
+	}
+
+	status, err := s.status(ctx, locationID, year, month)
+	if err != nil {
+		return nil, err
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if status.State == model.ScheduleStateLocked && reason == "" {
+		return nil, errorx.WithDetails(errorx.ErrScheduleLocked, "Kilitli ayı geri almak için gerekçe zorunludur")
+	}
+
+	t := model.ScheduleTransition{
+		LocationID:    locationID,
+		Year:          year,
+		Month:         month,
+		Action:        model.ScheduleActionRollback,
+		FromState:     status.State,
+		ToState:       status.State,
+		SourceVersion: req.Version,
+		Reason:        reason,
+		CreatedBy:     userID,
+	}
+	if err = s.scheduleRepo.Rollback(ctx, &t); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, errorx.WithDetails(errorx.ErrNotFound, "Sürüm bulunamadı")
+		case errors.Is(err, repository.ErrScheduleStateChanged):
+			return nil, errorx.WithDetails(errorx.ErrInvalidTransition, "Ayın durumu bu arada değişmiş")
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	s.record(ctx, t, status.Version)
+
+	day := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	if err = s.shiftService.refreshCoverage(ctx, locationID, day); err != nil {
+		return nil, err
+	}
+	if isVisible(status.State) {
+		if err = s.announce(ctx, locationID, year, month); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.Get(ctx, locationID, year, month, userID, model.UserRoleAdmin)
+}
+
+func (s *ScheduleService) ListVersions(ctx context.Context, locationID int64, year int, month int) ([]dto.ScheduleSnapshotDTO, error) {
+	snapshots, err := s.scheduleRepo.ListSnapshots(ctx, locationID, year, month)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]dto.ScheduleSnapshotDTO, len(snapshots))
+	for i, snap := range snapshots {
+		result[i] = dto.ScheduleSnapshotDTO{}.ToResponseModel(snap)
+	}
+	return result, nil
+}
+
+func (s *ScheduleService) GetVersion(ctx context.Context, locationID int64, year int, month int, version int) (*dto.ScheduleSnapshotDTO, error) {
+	snap, err := s.snapshot(ctx, locationID, year, month, version)
+	if err != nil {
+		return nil, err
+	}
+
+	result := dto.ScheduleSnapshotDTO{}.ToResponseModel(*snap)
+	return &result, nil
+}
+
+// from sürümünden to sürümüne yapılan değişiklikler. to 0 ise güncel nöbetlerle karşılaştırılır.
+func (s *ScheduleService) Diff(ctx context.Context, locationID int64, year int, month int, from int, to int) (*dto.ScheduleDiffDTO, error) {
+	before, err := s.snapshot(ctx, locationID, year, month, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var after []model.DraftShift
+	if to == 0 {
+		shifts, err := s.shiftRepo.GetShiftsByLocationID(ctx, locationID, int64(month), int64(year))
+		if err != nil {
+			return nil, errorx.ErrDatabaseOperation
+		}
+		for _, shift := range shifts {
+			after = append(after, model.DraftShift{
+				DoctorID:   shift.DoctorID,
+				ShiftDate:  shift.ShiftDate,
+				StartTime:  shift.StartTime,
+				EndTime:    shift.EndTime,
+				TemplateID: shift.TemplateID,
+			})
+		}
+	} else {
+		snap, err := s.snapshot(ctx, locationID, year, month, to)
+		if err != nil {
+			return nil, err
+		}
+		after = snap.Shifts
+	}
+
+	diff := snapshot.Diff(before.Shifts, after)
+	changes := make([]dto.ScheduleChangeDTO, len(diff))
+	for i, c := range diff {
+		changes[i] = dto.ScheduleChangeDTO(c)
+	}
+	return &dto.ScheduleDiffDTO{
+		LocationID:  locationID,
+		Year:        year,
+		Month:       month,
+		FromVersion: from,
+		ToVersion:   to,
+		Changes:     changes,
+	}, nil
+}
+
+func (s *ScheduleService) ListTransitions(ctx context.Context, locationID int64, year int, month int) ([]dto.ScheduleTransitionDTO, error) {
+	transitions, err := s.scheduleRepo.ListTransitions(ctx, locationID, year, month)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]dto.ScheduleTransitionDTO, len(transitions))
+	for i, t := range transitions {
+		result[i] = dto.ScheduleTransitionDTO{}.ToResponseModel(t)
+	}
+	return result, nil
+}
+
+// Ayın durum kaydı. Kayıt yoksa ay taslak sayılır.
+func (s *ScheduleService) status(ctx context.Context, locationID int64, year int, month int) (*model.ShiftsStatus, error) {
+	status, err := s.shiftRepo.GetShiftStatus(ctx, year, month, int(locationID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &model.ShiftsStatus{Year: year, Month: month, LocationID: locationID, State: model.ScheduleStateDraft}, nil
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return status, nil
+}
+
+func (s *ScheduleService) snapshot(ctx context.Context, locationID int64, year int, month int, version int) (*model.ScheduleSnapshot, error) {
+	snap, err := s.scheduleRepo.GetSnapshot(ctx, locationID, year, month, version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.WithDetails(errorx.ErrNotFound, "Sürüm bulunamadı")
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return snap, nil
+}
+
+// Geçişi ayın önceki ve sonraki durumuyla denetim kaydına yazar
+func (s *ScheduleService) record(ctx context.Context, t model.ScheduleTransition, fromVersion int) {
+	before := monthAudit{Year: t.Year, Month: t.Month, State: t.FromState, Version: fromVersion}
+	after := monthAudit{Year: t.Year, Month: t.Month, State: t.ToState, Version: t.Version, Reason: t.Reason}
+	s.shiftService.audit.Record(ctx, t.Action, model.AuditEntitySchedule, t.LocationID, before, after)
+}
+
+// Ayın nöbeti olan doktorlara yayın bildirimi gönderir
+func (s *ScheduleService) announce(ctx context.Context, locationID int64, year int, month int) error {
+	shifts, err := s.shiftRepo.GetShiftsByLocationID(ctx, locationID, int64(month), int64(year))
+	if err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	s.notifications.SchedulePublished(ctx, locationID, year, month, shiftDoctorIDs(shifts))
+	publishSchedule(ctx, locationID, year, month)
+	return nil
+}
+
+func validMonth(month int) error {
+	if month < 1 || month > 12 {
+		return errorx.WithDetails(errorx.ErrValidation, "Geçersiz ay")
+	}
+	return nil
+}
+
+// Taslak aylar sadece adminlere görünür
+func isVisible(state string) bool {
+	return state != "" && state != model.ScheduleStateDraft
+}
+
+func sameMonth(a model.Shift, b model.Shift) bool {
+	return a.LocationID == b.LocationID && a.ShiftDate.Year() == b.ShiftDate.Year() && a.ShiftDate.Month() == b.ShiftDate.Month()
+}
+
+// Ayın yayın durumu. Durum kaydı olmayan aylar taslaktır.
+func (s *ShiftService) monthState(ctx context.Context, locationID int64, day time.Time) (string, error) {
+	status, err := s.shiftRepo.GetShiftStatus(ctx, day.Year(), int(day.Month()), int(locationID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ScheduleStateDraft, nil
+		}
+		return "", errorx.ErrDatabaseOperation
+	}
+	return status.State, nil
+}
+
+// Elle yapılan değişikliğin ayın durumuna uygunluğunu kontrol eder ve durumu döner.
+// Kilitli aylar sadece gerekçeyle değiştirilebilir.
+func (s *ShiftService) checkEditable(ctx context.Context, locationID int64, day time.Time, overrideReason string) (string, error) {
+	state, err := s.monthState(ctx, locationID, day)
+	if err != nil {
+		return "", err
+	}
+	if state == model.ScheduleStateLocked && strings.TrimSpace(overrideReason) == "" {
+		return "", errorx.WithDetails(errorx.ErrScheduleLocked, "Kilitli ayda değişiklik için gerekçe (override_reason) zorunludur")
+	}
+	return state, nil
+}
+
+// Kilitli aydaki değişikliği ayın geçmişine yazar
+func (s *ShiftService) recordOverride(ctx context.Context, locationID int64, day time.Time, state string, userID int64, overrideReason string) error {
+	if state != model.ScheduleStateLocked {
+		return nil
+	}
+
+	status, err := s.shiftRepo.GetShiftStatus(ctx, day.Year(), int(day.Month()), int(locationID))
+	if err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	err = s.scheduleRepo.RecordTransition(ctx, &model.ScheduleTransition{
+		LocationID: locationID,
+		Year:       status.Year,
+		Month:      status.Month,
+		Action:     model.ScheduleActionOverride,
+		FromState:  state,
+		ToState:    state,
+		Version:    status.Version,
+		Reason:     strings.TrimSpace(overrideReason),
+		CreatedBy:  userID,
+	})
+	if err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	return nil
+}
+
+// Değişim ve ilanlar sadece yayınlanmış (kilitlenmemiş) aylarda yapılabilir
+func (s *ShiftService) requirePublished(ctx context.Context, locationID int64, days ...time.Time) error {
+	for _, day := range days {
+		state, err := s.monthState(ctx, locationID, day)
+		if err != nil {
+			return err
+		}
+		if state != model.ScheduleStatePublished {
+			return errorx.WithDetails(errorx.ErrInvalidRequest, "Nöbet listesi yayında olmayan aylarda değişiklik yapılamaz")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/xlsx"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Dışa aktarma biçimleri
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+	ExportFormatPDF  = "pdf"
+)
+
+// Tabloda hafta sonu ve resmi tatil sütunlarının dolgu renkleri
+const (
+	weekendFill = "FFE7E6E6"
+	holidayFill = "FFFCE4D6"
+)
+
+var (
+	monthNames   = [...]string{"Ocak", "Şubat", "Mart", "Nisan", "Mayıs", "Haziran", "Temmuz", "Ağustos", "Eylül", "Ekim", "Kasım", "Aralık"}
+	weekdayNames = [...]string{"Paz", "Pzt", "Sal", "Çar", "Per", "Cum", "Cmt"}
+)
+
+// Lokasyonun ayını dosya olarak üretir. CSV her nöbet için bir satırdır; XLSX doktorları satır,
+// günleri sütun olarak gösterir, hafta sonu ve resmi tatilleri boyar ve doktor başına toplam verir.
+// PDF panoya asılacak aylık takvim görünümüdür.
+func (s *ShiftService) ExportMonth(ctx context.Context, locationID int64, year int, month int, format string) (*dto.ExportFile, error) {
+	if err := validMonth(month); err != nil {
+		return nil, err
+	}
+	if format != ExportFormatCSV && format != ExportFormatXLSX && format != ExportFormatPDF {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "format csv, xlsx ya da pdf olmalıdır")
+	}
+
+	location, err := s.locationRepo.GetByID(ctx, locationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.WithDetails(errorx.ErrNotFound, "Lokasyon bulunamadı")
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	shifts, err := s.shiftRepo.GetShiftsByLocationID(ctx, locationID, int64(month), int64(year))
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	sort.SliceStable(shifts, func(i, j int) bool {
+		a, b := shifts[i], shifts[j]
+		if !a.ShiftDate.Equal(b.ShiftDate) {
+			return a.ShiftDate.Before(b.ShiftDate)
+		}
+		if a.StartTime != b.StartTime {
+			return a.StartTime < b.StartTime
+		}
+		return doctorSortKey(a.Doctor) < doctorSortKey(b.Doctor)
+	})
+
+	file := &dto.ExportFile{Filename: exportFilename(location.Name, year, month, format)}
+	switch format {
+	case ExportFormatCSV:
+		file.ContentType = "text/csv; charset=utf-8"
+		file.Body, err = shiftsCSV(shifts)
+	case ExportFormatXLSX:
+		file.ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		file.Body, err = s.shiftsXLSX(ctx, *location, year, month, shifts)
+	case ExportFormatPDF:
+		file.ContentType = "application/pdf"
+		file.Body, err = s.shiftsPDF(ctx, *location, year, month, shifts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Excel'in Türkçe karakterleri doğru açması için dosya UTF-8 BOM ile başlar
+func shiftsCSV(shifts []model.Shift) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\uFEFF")
+
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"date", "doctor", "title", "specialization", "start", "end"})
+	for _, shift := range shifts {
+		_ = w.Write([]string{
+			shift.ShiftDate.Format("2006-01-02"),
+			strings.TrimSpace(shift.Doctor.User.Name + " " + shift.Doctor.User.Surname),
+			shift.Doctor.Title,
+			shift.Doctor.Specialization,
+			shift.StartTime,
+			shift.EndTime,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, errorx.ErrInternal
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *ShiftService) shiftsXLSX(ctx context.Context, location model.ShiftLocation, year int, month int, shifts []model.Shift) ([]byte, error) {
+	p, err := s.policy(ctx, location.ID)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	holidays, err := s.publicHolidays(ctx, location.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ay içinde üye olan doktorlar nöbeti olmasa da listelenir
+	memberships, err := s.doctorRepo.GetMemberships(ctx, location.ID, start, end)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	doctors := make(map[int64]model.Doctor)
+	for _, m := range memberships {
+		doctors[m.DoctorID] = m.Doctor
+	}
+	for _, shift := range shifts {
+		doctors[shift.DoctorID] = shift.Doctor
+	}
+	order := make([]model.Doctor, 0, len(doctors))
+	for _, doctor := range doctors {
+		order = append(order, doctor)
+	}
+	sort.Slice(order, func(i, j int) bool { return doctorSortKey(order[i]) < doctorSortKey(order[j]) })
+
+	days := end.AddDate(0, 0, -1).Day()
+	fills := make([]string, days)
+	header := []xlsx.Cell{{Value: "Doktor", Style: xlsx.Style{Bold: true}}, {Value: "Unvan", Style: xlsx.Style{Bold: true}}}
+	for d := 0; d < days; d++ {
+		day := start.AddDate(0, 0, d)
+		if _, ok := holidays[day.Format("2006-01-02")]; ok {
+			fills[d] = holidayFill
+		} else if p.IsWeekend(day) {
+			fills[d] = weekendFill
+		}
+		header = append(header, xlsx.Cell{Value: fmt.Sprintf("%d %s", d+1, weekdayNames[day.Weekday()]), Style: xlsx.Style{Bold: true, Fill: fills[d]}})
+	}
+	header = append(header, xlsx.Cell{Value: "Nöbet", Style: xlsx.Style{Bold: true}}, xlsx.Cell{Value: "Saat", Style: xlsx.Style{Bold: true}})
+
+	title := fmt.Sprintf("%s - %s %d", location.Name, monthNames[month-1], year)
+	rows := [][]xlsx.Cell{{{Value: title, Style: xlsx.Style{Bold: true}}}, header}
+
+	byDoctor := make(map[int64][][]string)
+	perDay := make([]int, days)
+	count := make(map[int64]int)
+	hours := make(map[int64]float64)
+	for _, shift := range shifts {
+		slot, err := shiftSlot(shift.ShiftDate, shift.StartTime, shift.EndTime, p)
+		if err != nil {
+			return nil, err
+		}
+		if byDoctor[shift.DoctorID] == nil {
+			byDoctor[shift.DoctorID] = make([][]string, days)
+		}
+		d := shift.ShiftDate.Day() - 1
+		byDoctor[shift.DoctorID][d] = append(byDoctor[shift.DoctorID][d], shiftLabel(shift))
+		perDay[d]++
+		count[shift.DoctorID]++
+		hours[shift.DoctorID] += slot.End.Sub(slot.Start).Hours()
+	}
+
+	var totalHours float64
+	for _, doctor := range order {
+		row := []xlsx.Cell{{Value: strings.TrimSpace(doctor.User.Name + " " + doctor.User.Surname)}, {Value: doctor.Title}}
+		for d := 0; d < days; d++ {
+			cell := xlsx.Cell{Style: xlsx.Style{Fill: fills[d]}}
+			if labels := byDoctor[doctor.ID]; labels != nil && len(labels[d]) > 0 {
+				cell.Value = strings.Join(labels[d], ", ")
+			}
+			row = append(row, cell)
+		}
+		row = append(row, xlsx.Cell{Value: count[doctor.ID]}, xlsx.Cell{Value: roundHours(hours[doctor.ID])})
+		rows = append(rows, row)
+		totalHours += hours[doctor.ID]
+	}
+
+	totals := []xlsx.Cell{{Value: "Toplam", Style: xlsx.Style{Bold: true}}, {}}
+	for d := 0; d < days; d++ {
+		totals = append(totals, xlsx.Cell{Value: perDay[d], Style: xlsx.Style{Bold: true, Fill: fills[d]}})
+	}
+	totals = append(totals, xlsx.Cell{Value: len(shifts), Style: xlsx.Style{Bold: true}}, xlsx.Cell{Value: roundHours(totalHours), Style: xlsx.Style{Bold: true}})
+	rows = append(rows, totals, nil)
+
+	// Açıklama ve ayın resmi tatilleri
+	rows = append(rows,
+		[]xlsx.Cell{{Value: "Hafta sonu", Style: xlsx.Style{Fill: weekendFill}}, {Value: "Resmi tatil", Style: xlsx.Style{Fill: holidayFill}}},
+	)
+	keys := make([]string, 0, len(holidays))
+	for key := range holidays {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		day, _ := time.Parse("2006-01-02", key)
+		rows = append(rows, []xlsx.Cell{{Value: day.Format("02.01.2006")}, {Value: holidays[key].name}})
+	}
+
+	widths := []float64{24, 12}
+	for d := 0; d < days; d++ {
+		widths = append(widths, 11)
+	}
+	widths = append(widths, 8, 8)
+
+	var buf bytes.Buffer
+	err = xlsx.Write(&buf, xlsx.Sheet{
+		Name:      fmt.Sprintf("%s %d", monthNames[month-1], year),
+		Rows:      rows,
+		ColWidths: widths,
+		FreezeRow: 2,
+		FreezeCol: 2,
+	})
+	if err != nil {
+		return nil, errorx.ErrInternal
+	}
+	return buf.Bytes(), nil
+}
+
+// Tablodaki hücre metni: şablon adı, şablonsuz nöbetlerde saat aralığı
+func shiftLabel(shift model.Shift) string {
+	if shift.Template != nil && shift.Template.Name != "" {
+		return shift.Template.Name
+	}
+	return shift.StartTime + "-" + shift.EndTime
+}
+
+func doctorSortKey(doctor model.Doctor) string {
+	return strings.ToLower(doctor.User.Surname + " " + doctor.User.Name)
+}
+
+func roundHours(hours float64) float64 {
+	return math.Round(hours*10) / 10
+}
+
+// "Acil Servis", 2025, 3 -> "acil-servis-2025-03.csv". Türkçe karakterler ASCII karşılıklarına çevrilir.
+func exportFilename(location string, year int, month int, ext string) string {
+	replacer := strings.NewReplacer("ç", "c", "Ç", "c", "ğ", "g", "Ğ", "g", "ı", "i", "İ", "i", "ö", "o", "Ö", "o", "ş", "s", "Ş", "s", "ü", "u", "Ü", "u")
+
+	var b strings.Builder
+	dash := false
+	for _, r := range replacer.Replace(location) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(unicode.ToLower(r))
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		slug = "nobet-listesi"
+	}
+	return fmt.Sprintf("%s-%d-%02d.%s", slug, year, month, ext)
+}
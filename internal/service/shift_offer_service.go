@@ -0,0 +1,446 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/realtime"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/pkg/errorx"
+	"slices"
+	"time"
+)
+
+// İlanın geçebileceği durumlar
+var offerTransitions = map[string][]string{
+	model.OfferStatusOpen:    {model.OfferStatusClaimed, model.OfferStatusTransferred, model.OfferStatusCancelled, model.OfferStatusExpired},
+	model.OfferStatusClaimed: {model.OfferStatusTransferred, model.OfferStatusOpen, model.OfferStatusCancelled, model.OfferStatusExpired},
+}
+
+type ShiftOfferService struct {
+	offerRepo     *repository.ShiftOfferRepository
+	swapRepo      *repository.ShiftSwapRepository
+	shiftRepo     *repository.ShiftRepository
+	doctorRepo    *repository.DoctorRepository
+	shiftService  *ShiftService
+	notifications *NotificationService
+}
+
+func NewShiftOfferService(offerRepo *repository.ShiftOfferRepository, swapRepo *repository.ShiftSwapRepository, shiftRepo *repository.ShiftRepository, doctorRepo *repository.DoctorRepository, shiftService *ShiftService, notifications *NotificationService) *ShiftOfferService {
+	return &ShiftOfferService{
+		offerRepo:     offerRepo,
+		swapRepo:      swapRepo,
+		shiftRepo:     shiftRepo,
+		doctorRepo:    doctorRepo,
+		shiftService:  shiftService,
+		notifications: notifications,
+	}
+}
+
+// Doktorun kendi nöbetini lokasyon panosuna ilan olarak çıkarır
+func (s *ShiftOfferService) Create(ctx context.Context, userID int64, req dto.OfferCreateRequest) (*dto.OfferResponseDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	shift, err := s.shiftRepo.GetShiftByID(ctx, req.ShiftID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.WithDetails(errorx.ErrNotFound, "Nöbet bulunamadı")
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	if shift.DoctorID != doctor.ID {
+		return nil, errorx.WithDetails(errorx.ErrForbidden, "Sadece kendi nöbetinizi ilana çıkarabilirsiniz")
+	}
+	p, err := s.shiftService.policy(ctx, shift.LocationID)
+	if err != nil {
+		return nil, err
+	}
+	if !shift.ShiftDate.After(p.today()) {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Geçmiş ya da bugünkü nöbetler ilana çıkarılamaz")
+	}
+	if err = s.shiftService.requirePublished(ctx, shift.LocationID, shift.ShiftDate); err != nil {
+		return nil, err
+	}
+
+	active, err := s.offerRepo.HasActiveForShift(ctx, shift.ID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	if active {
+		return nil, errorx.WithDetails(errorx.ErrDuplicate, "Nöbet zaten ilanda")
+	}
+	swapping, err := s.swapRepo.HasOpenForShift(ctx, shift.ID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	if swapping {
+		return nil, errorx.WithDetails(errorx.ErrDuplicate, "Nöbet için sonuçlanmamış bir değişim talebi var")
+	}
+
+	offer := req.ToDBModel(model.ShiftOffer{
+		LocationID: shift.LocationID,
+		ShiftDate:  shift.ShiftDate,
+		OfferedBy:  doctor.ID,
+		Status:     model.OfferStatusOpen,
+	})
+	if err = s.offerRepo.Create(ctx, &offer); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	return s.changed(ctx, offer.ID)
+}
+
+// İlanı alır. İlk geçerli talep kazanır; lokasyon onay istemiyorsa nöbet hemen devredilir.
+func (s *ShiftOfferService) Claim(ctx context.Context, userID int64, id int64) (*dto.OfferResponseDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	offer, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if offer.OfferedBy == doctor.ID {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Kendi ilanınızı alamazsınız")
+	}
+
+	locations, err := s.doctorRepo.GetLocationIDs(ctx, doctor.ID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	if !slices.Contains(locations, offer.LocationID) {
+		return nil, errorx.WithDetails(errorx.ErrForbidden, "Bu lokasyonda nöbet tutamazsınız")
+	}
+
+	if err = s.checkClaimer(ctx, offer, doctor.ID); err != nil {
+		return nil, err
+	}
+
+	p, err := s.shiftService.policy(ctx, offer.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	offer.ClaimedBy = doctor.ID
+	offer.ClaimedAt = &now
+
+	to := model.OfferStatusTransferred
+	if p.OfferRequiresApproval {
+		to = model.OfferStatusClaimed
+	}
+	if err = s.apply(ctx, offer, to); err != nil {
+		return nil, err
+	}
+	s.notifications.OfferClaimed(ctx, *offer, doctor.ID)
+
+	return s.changed(ctx, offer.ID)
+}
+
+// İlanı veren doktor ilanı geri çeker
+func (s *ShiftOfferService) Cancel(ctx context.Context, userID int64, id int64) (*dto.OfferResponseDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	offer, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if offer.OfferedBy != doctor.ID {
+		return nil, errorx.WithDetails(errorx.ErrForbidden, "Bu ilan üzerinde işlem yapamazsınız")
+	}
+
+	if err = s.apply(ctx, offer, model.OfferStatusCancelled); err != nil {
+		return nil, err
+	}
+	return s.changed(ctx, offer.ID)
+}
+
+// Admin alınan ilanı onaylar, uygunluk yeniden kontrol edilip nöbet devredilir
+func (s *ShiftOfferService) Approve(ctx context.Context, adminID int64, id int64) (*dto.OfferResponseDTO, error) {
+	offer, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if offer.Status != model.OfferStatusClaimed {
+		return nil, transitionError(offer.Status, model.OfferStatusTransferred)
+	}
+
+	if err = s.checkClaimer(ctx, offer, offer.ClaimedBy); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	offer.ReviewedBy = adminID
+	offer.ReviewedAt = &now
+	if err = s.apply(ctx, offer, model.OfferStatusTransferred); err != nil {
+		return nil, err
+	}
+	s.notifications.OfferClaimed(ctx, *offer, offer.ClaimedBy)
+
+	return s.changed(ctx, offer.ID)
+}
+
+// Admin alınan ilanı reddeder, ilan diğer doktorlar için tekrar açılır
+func (s *ShiftOfferService) Deny(ctx context.Context, adminID int64, id int64) (*dto.OfferResponseDTO, error) {
+	offer, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if offer.Status != model.OfferStatusClaimed {
+		return nil, transitionError(offer.Status, model.OfferStatusOpen)
+	}
+
+	now := time.Now()
+	claimer := offer.ClaimedBy
+	offer.ClaimedBy = 0
+	offer.ClaimedAt = nil
+	offer.ReviewedBy = adminID
+	offer.ReviewedAt = &now
+	if err = s.apply(ctx, offer, model.OfferStatusOpen); err != nil {
+		return nil, err
+	}
+	s.notifications.OfferClaimed(ctx, *offer, claimer)
+	return s.changed(ctx, offer.ID)
+}
+
+// Doktorun verdiği ya da aldığı ilanlar
+func (s *ShiftOfferService) ListMine(ctx context.Context, userID int64) ([]dto.OfferResponseDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.offerRepo.ExpireDue(ctx, time.Now()); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	offers, err := s.offerRepo.ListByDoctor(ctx, doctor.ID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]dto.OfferResponseDTO, len(offers))
+	for i, offer := range offers {
+		result[i] = dto.OfferResponseDTO{}.ToResponseModel(offer)
+	}
+	return result, nil
+}
+
+// Doktorun lokasyonlarındaki açık ilanlar ve doktorun her birini alıp alamayacağı
+func (s *ShiftOfferService) Board(ctx context.Context, userID int64) ([]dto.OfferBoardDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := s.doctorRepo.GetLocationIDs(ctx, doctor.ID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	if len(locations) == 0 {
+		return []dto.OfferBoardDTO{}, nil
+	}
+
+	if err = s.offerRepo.ExpireDue(ctx, time.Now()); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	offers, err := s.offerRepo.List(ctx, []string{model.OfferStatusOpen}, locations)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]dto.OfferBoardDTO, 0, len(offers))
+	for _, offer := range offers {
+		if offer.OfferedBy == doctor.ID {
+			continue
+		}
+
+		reasons, err := s.eligibility(ctx, &offer, doctor.ID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, dto.OfferBoardDTO{
+			OfferResponseDTO: dto.OfferResponseDTO{}.ToResponseModel(offer),
+			Eligible:         len(reasons) == 0,
+			Reasons:          reasons,
+		})
+	}
+	return result, nil
+}
+
+// Admin için ilan listesi, boş bırakılan filtreler uygulanmaz
+func (s *ShiftOfferService) List(ctx context.Context, status string, locationID int64) ([]dto.OfferResponseDTO, error) {
+	var statuses []string
+	if status != "" {
+		if status != model.OfferStatusOpen && !slices.Contains(offerTransitions[model.OfferStatusOpen], status) {
+			return nil, errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("Geçersiz durum: %s", status))
+		}
+		statuses = []string{status}
+	}
+	var locations []int64
+	if locationID != 0 {
+		locations = []int64{locationID}
+	}
+
+	if err := s.offerRepo.ExpireDue(ctx, time.Now()); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	offers, err := s.offerRepo.List(ctx, statuses, locations)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]dto.OfferResponseDTO, len(offers))
+	for i, offer := range offers {
+		result[i] = dto.OfferResponseDTO{}.ToResponseModel(offer)
+	}
+	return result, nil
+}
+
+// Lokasyondaki doktorların ilandaki nöbeti alıp alamayacağını listeler
+func (s *ShiftOfferService) Candidates(ctx context.Context, id int64) ([]dto.OfferCandidateDTO, error) {
+	offer, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	doctors, err := s.doctorRepo.GetByLocation(ctx, offer.LocationID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]dto.OfferCandidateDTO, 0, len(doctors))
+	for _, doctor := range doctors {
+		if doctor.ID == offer.OfferedBy {
+			continue
+		}
+
+		reasons, err := s.eligibility(ctx, offer, doctor.ID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, dto.OfferCandidateDTO{
+			DoctorID: doctor.ID,
+			Name:     doctor.User.String(),
+			Eligible: len(reasons) == 0,
+			Reasons:  reasons,
+		})
+	}
+	return result, nil
+}
+
+// Doktor nöbeti aldığında ihlal edilecek kuralların adlarını döner, boşsa doktor uygundur
+func (s *ShiftOfferService) eligibility(ctx context.Context, offer *model.ShiftOffer, doctorID int64) ([]string, error) {
+	shift := offer.Shift
+	shift.DoctorID = doctorID
+
+	violations, err := s.shiftService.shiftViolations(ctx, shift, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var reasons []string
+	for _, v := range violations {
+		if !slices.Contains(reasons, v.Constraint) {
+			reasons = append(reasons, v.Constraint)
+		}
+	}
+	return reasons, nil
+}
+
+// Nöbeti alacak doktoru tatil, dinlenme kuralları ve aylık limite göre kontrol eder
+func (s *ShiftOfferService) checkClaimer(ctx context.Context, offer *model.ShiftOffer, doctorID int64) error {
+	shift := offer.Shift
+	shift.DoctorID = doctorID
+
+	violations, err := s.shiftService.shiftViolations(ctx, shift, true)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return ruleViolationError(violations)
+	}
+	return nil
+}
+
+// İlanı verilen duruma geçirir; devir gerektiren geçişlerde nöbet aynı transaction içinde el değiştirir
+func (s *ShiftOfferService) apply(ctx context.Context, offer *model.ShiftOffer, to string) error {
+	from := offer.Status
+	if !slices.Contains(offerTransitions[from], to) {
+		return transitionError(from, to)
+	}
+	if to == model.OfferStatusTransferred {
+		if err := s.shiftService.requirePublished(ctx, offer.LocationID, offer.ShiftDate); err != nil {
+			return err
+		}
+	}
+
+	offer.Status = to
+	if to != model.OfferStatusClaimed && to != model.OfferStatusOpen {
+		now := time.Now()
+		offer.ResolvedAt = &now
+	}
+
+	if err := s.offerRepo.Transition(ctx, offer, from, to == model.OfferStatusTransferred); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return errorx.WithDetails(errorx.ErrInvalidTransition, "İlan bu arada alınmış ya da kapanmış")
+		case errors.Is(err, repository.ErrOfferShiftChanged):
+			return errorx.WithDetails(errorx.ErrInvalidTransition, "Nöbet ilan verildikten sonra değişmiş")
+		}
+		return errorx.ErrDatabaseOperation
+	}
+
+	if to == model.OfferStatusTransferred {
+		shift := offer.Shift
+		shift.DoctorID = offer.ClaimedBy
+		s.shiftService.recordShift(ctx, model.AuditActionTransfer, &offer.Shift, &shift)
+		publishShift(ctx, realtime.EventShiftUpdated, shift, true)
+	}
+	return nil
+}
+
+// İlanı süresi dolanları işaretledikten sonra okur
+func (s *ShiftOfferService) load(ctx context.Context, id int64) (*model.ShiftOffer, error) {
+	if err := s.offerRepo.ExpireDue(ctx, time.Now()); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	offer, err := s.offerRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.ErrNotFound
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return offer, nil
+}
+
+// Güncel ilanı okur ve lokasyon panosunu izleyen istemcilere iletir
+func (s *ShiftOfferService) changed(ctx context.Context, id int64) (*dto.OfferResponseDTO, error) {
+	offer, err := s.offerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := dto.OfferResponseDTO{}.ToResponseModel(*offer)
+	realtime.Publish(ctx, realtime.Event{
+		Type:       realtime.EventOfferChanged,
+		Audience:   realtime.AudienceLocation,
+		LocationID: offer.LocationID,
+		Data:       result,
+	})
+	return &result, nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/pkg/errorx"
+	"shift-scheduling-v2/pkg/pdf"
+	"shift-scheduling-v2/pkg/xlsx"
+	"time"
+)
+
+// A4 yatay sayfa düzeni (pt)
+const (
+	pdfMargin       = 28.0
+	pdfGridTop      = 72.0
+	pdfHeaderHeight = 16.0
+	pdfFooterHeight = 96.0
+	pdfLineHeight   = 8.5
+	pdfFontSize     = 7.0
+)
+
+var (
+	pdfWeekendFill = pdf.Color{R: 0.906, G: 0.902, B: 0.902}
+	pdfHolidayFill = pdf.Color{R: 0.988, G: 0.894, B: 0.839}
+	pdfOutsideFill = pdf.Color{R: 0.965, G: 0.965, B: 0.965}
+	pdfGridColor   = pdf.Color{R: 0.6, G: 0.6, B: 0.6}
+	pdfMutedColor  = pdf.Color{R: 0.35, G: 0.35, B: 0.35}
+	pdfDraftColor  = pdf.Color{R: 0.75, G: 0.1, B: 0.1}
+
+	pdfWeekdayNames = [...]string{"Pazartesi", "Salı", "Çarşamba", "Perşembe", "Cuma", "Cumartesi", "Pazar"}
+)
+
+// Takvimde nöbetin önüne yazılan kısa kod ve açıklaması
+type pdfLegendEntry struct {
+	code  string
+	label string
+}
+
+// Ayı haftalar satır, günler (pazartesiden başlayarak) sütun olacak şekilde tek sayfaya çizer.
+// Her günde nöbetçi doktorlar şablon koduyla listelenir; altta şablon açıklaması ve başhekim
+// imza alanı, üstte yayın sürümü ve tarihi bulunur.
+func (s *ShiftService) shiftsPDF(ctx context.Context, location model.ShiftLocation, year int, month int, shifts []model.Shift) ([]byte, error) {
+	p, err := s.policy(ctx, location.ID)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	holidays, err := s.publicHolidays(ctx, location.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := s.shiftRepo.GetShiftStatus(ctx, year, month, int(location.ID))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.ErrDatabaseOperation
+		}
+		status = &model.ShiftsStatus{Year: year, Month: month, LocationID: location.ID, State: model.ScheduleStateDraft}
+	}
+
+	templates, err := s.shiftRepo.GetTemplatesByLocation(ctx, location.ID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	legend, codes := pdfLegend(templates, shifts)
+
+	now := time.Now().In(p.loc)
+	doc := pdf.New(pdf.A4Height, pdf.A4Width)
+	doc.Title = fmt.Sprintf("%s - %s %d Nöbet Çizelgesi", location.Name, monthNames[month-1], year)
+	doc.Created = now
+	page := doc.AddPage()
+	width, height := doc.Width(), doc.Height()
+
+	// Başlık ve yayın bilgisi
+	page.Text(pdfMargin, 44, 15, true, pdf.Black, pdf.Fit(doc.Title, 15, true, width-2*pdfMargin-180))
+	page.TextRight(width-pdfMargin, 44, 8, false, pdfMutedColor, "Oluşturulma: "+now.Format("02.01.2006 15:04"))
+	publication, color := pdfPublication(*status, p.loc)
+	page.Text(pdfMargin, 60, 9, status.State == model.ScheduleStateDraft, color, publication)
+
+	// Takvim ızgarası
+	cellWidth := (width - 2*pdfMargin) / 7
+	for i, name := range pdfWeekdayNames {
+		x := pdfMargin + float64(i)*cellWidth
+		page.Rect(x, pdfGridTop, cellWidth, pdfHeaderHeight, &pdfWeekendFill, 0.5, pdfGridColor)
+		page.TextCenter(x, pdfGridTop+11, cellWidth, 8, true, pdf.Black, name)
+	}
+
+	offset := (int(start.Weekday()) + 6) % 7
+	days := end.AddDate(0, 0, -1).Day()
+	weeks := (offset + days + 6) / 7
+	gridBottom := height - pdfMargin - pdfFooterHeight
+	cellHeight := (gridBottom - pdfGridTop - pdfHeaderHeight) / float64(weeks)
+	capacity := int(math.Floor((cellHeight - 14) / pdfLineHeight))
+
+	byDay := make([][]model.Shift, days)
+	for _, shift := range shifts {
+		d := shift.ShiftDate.Day() - 1
+		byDay[d] = append(byDay[d], shift)
+	}
+
+	for cell := 0; cell < weeks*7; cell++ {
+		x := pdfMargin + float64(cell%7)*cellWidth
+		y := pdfGridTop + pdfHeaderHeight + float64(cell/7)*cellHeight
+		d := cell - offset
+		if d < 0 || d >= days {
+			page.Rect(x, y, cellWidth, cellHeight, &pdfOutsideFill, 0.5, pdfGridColor)
+			continue
+		}
+
+		day := start.AddDate(0, 0, d)
+		holiday, isHoliday := holidays[day.Format("2006-01-02")]
+		var fill *pdf.Color
+		if isHoliday {
+			fill = &pdfHolidayFill
+		} else if p.IsWeekend(day) {
+			fill = &pdfWeekendFill
+		}
+		page.Rect(x, y, cellWidth, cellHeight, fill, 0.5, pdfGridColor)
+
+		number := fmt.Sprint(d + 1)
+		page.Text(x+4, y+11, 9, true, pdf.Black, number)
+		if isHoliday {
+			left := x + 8 + pdf.TextWidth(number, 9, true)
+			page.TextRight(x+cellWidth-4, y+10, 6, false, pdfMutedColor, pdf.Fit(holiday.name, 6, false, x+cellWidth-4-left))
+		}
+
+		lines := byDay[d]
+		more := 0
+		if len(lines) > capacity {
+			more = len(lines) - capacity + 1
+			lines = lines[:capacity-1]
+		}
+		for i, shift := range lines {
+			ly := y + 21 + float64(i)*pdfLineHeight
+			code := codes[pdfLegendKey(shift)]
+			page.Text(x+4, ly, pdfFontSize, true, pdf.Black, code)
+			name := doctorName(shift.Doctor)
+			page.Text(x+16, ly, pdfFontSize, false, pdf.Black, pdf.Fit(name, pdfFontSize, false, cellWidth-20))
+		}
+		if more > 0 {
+			page.Text(x+4, y+21+float64(len(lines))*pdfLineHeight, pdfFontSize, false, pdfMutedColor, fmt.Sprintf("+%d nöbet daha", more))
+		}
+	}
+
+	// Şablon açıklaması: üç sütun, sonunda gün renkleri
+	top := gridBottom + 16
+	page.Text(pdfMargin, top, 8, true, pdf.Black, "Nöbet şablonları")
+	const legendColumn = 165.0
+	rows := 6
+	for i, entry := range legend {
+		x := pdfMargin + float64(i/rows)*legendColumn
+		y := top + 12 + float64(i%rows)*10
+		page.Text(x, y, 7.5, true, pdf.Black, entry.code)
+		page.Text(x+12, y, 7.5, false, pdf.Black, pdf.Fit(entry.label, 7.5, false, legendColumn-18))
+	}
+	swatch := pdfMargin + float64((len(legend)+rows-1)/rows)*legendColumn
+	page.Rect(swatch, top+5, 9, 7, &pdfWeekendFill, 0.5, pdfGridColor)
+	page.Text(swatch+13, top+12, 7.5, false, pdf.Black, "Hafta sonu")
+	page.Rect(swatch, top+15, 9, 7, &pdfHolidayFill, 0.5, pdfGridColor)
+	page.Text(swatch+13, top+22, 7.5, false, pdf.Black, "Resmi tatil")
+
+	// Başhekim onayı
+	const signatureWidth = 210.0
+	sx := width - pdfMargin - signatureWidth
+	page.Rect(sx, top-8, signatureWidth, pdfFooterHeight-12, nil, 0.5, pdfGridColor)
+	page.TextCenter(sx, top+4, signatureWidth, 9, true, pdf.Black, "Başhekim Onayı")
+	for i, label := range []string{"Adı Soyadı", "Tarih", "İmza"} {
+		y := top + 26 + float64(i)*20
+		page.Text(sx+8, y, 8, false, pdfMutedColor, label+":")
+		page.Line(sx+60, y+1, sx+signatureWidth-10, y+1, 0.5, pdfGridColor)
+	}
+
+	var buf bytes.Buffer
+	if err = doc.Write(&buf); err != nil {
+		return nil, errorx.ErrInternal
+	}
+	return buf.Bytes(), nil
+}
+
+// Yayın satırı. Hiç yayınlanmamış aylar taslak olarak işaretlenir.
+func pdfPublication(status model.ShiftsStatus, loc *time.Location) (string, pdf.Color) {
+	if status.Version == 0 || status.PublishedAt == nil {
+		return "TASLAK - bu çizelge henüz yayınlanmamıştır", pdfDraftColor
+	}
+
+	text := fmt.Sprintf("Yayın sürümü %d, yayınlanma: %s", status.Version, status.PublishedAt.In(loc).Format("02.01.2006 15:04"))
+	switch status.State {
+	case model.ScheduleStateLocked:
+		text += " (kilitli)"
+	case model.ScheduleStateDraft:
+		return text + " - yeni sürüm hazırlanıyor, bu çıktı taslaktır", pdfDraftColor
+	}
+	return text, pdf.Black
+}
+
+// Lokasyonun şablonlarına ve şablonsuz nöbetlerin saat aralıklarına sırayla A, B, C... kodu verir
+func pdfLegend(templates []model.ShiftTemplate, shifts []model.Shift) ([]pdfLegendEntry, map[string]string) {
+	var legend []pdfLegendEntry
+	codes := make(map[string]string)
+	add := func(key string, label string) {
+		if _, ok := codes[key]; ok {
+			return
+		}
+		code := xlsx.ColumnName(len(legend))
+		codes[key] = code
+		legend = append(legend, pdfLegendEntry{code: code, label: label})
+	}
+
+	for _, t := range templates {
+		add(fmt.Sprintf("template:%d", t.ID), fmt.Sprintf("%s (%s-%s)", t.Name, t.StartTime, t.EndTime))
+	}
+	for _, shift := range shifts {
+		key := pdfLegendKey(shift)
+		if shift.Template != nil && shift.Template.ID != 0 {
+			add(key, fmt.Sprintf("%s (%s-%s)", shift.Template.Name, shift.Template.StartTime, shift.Template.EndTime))
+		} else {
+			add(key, fmt.Sprintf("Şablonsuz (%s-%s)", shift.StartTime, shift.EndTime))
+		}
+	}
+	return legend, codes
+}
+
+func pdfLegendKey(shift model.Shift) string {
+	if shift.Template != nil && shift.Template.ID != 0 {
+		return fmt.Sprintf("template:%d", shift.Template.ID)
+	}
+	return "time:" + shift.StartTime + "-" + shift.EndTime
+}
@@ -2,18 +2,63 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"shift-scheduling-v2/internal/dto"
 	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/policy"
+	"shift-scheduling-v2/internal/realtime"
 	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/internal/scheduler"
 	"shift-scheduling-v2/pkg/errorx"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 )
 
+const (
+	// Adalet hesabında kullanılan gün ağırlıkları
+	weekdayWeight       = 1.0
+	weekendWeight       = 1.5
+	publicHolidayWeight = 2.0
+
+	// Doktor tercihlerinin amaç fonksiyonundaki ağırlığı
+	preferenceWeight = 1.0
+
+	// Adalet hesabına dahil edilen geçmiş ay sayısı
+	fairnessHistoryMonths = 12
+
+	// Resmi tatil nöbetlerinin yıllar arasında dengelenmesi için okunan geçmiş yıl sayısı
+	publicHolidayHistoryYears = 3
+
+	// Dinlenme kuralları için dönemin öncesinden ve sonrasından okunan gün sayısı
+	restRuleLookaround = 14
+)
+
 type ShiftService struct {
-	shiftRepo *repository.ShiftRepository
+	shiftRepo     *repository.ShiftRepository
+	doctorRepo    *repository.DoctorRepository
+	locationRepo  *repository.LocationRepository
+	calendarRepo  *repository.HolidayCalendarRepository
+	scheduleRepo  *repository.ScheduleRepository
+	notifications *NotificationService
+	audit         *AuditService
 }
 
-func NewShiftService(shiftRepo *repository.ShiftRepository) *ShiftService {
-	return &ShiftService{shiftRepo: shiftRepo}
+func NewShiftService(shiftRepo *repository.ShiftRepository, doctorRepo *repository.DoctorRepository, locationRepo *repository.LocationRepository, calendarRepo *repository.HolidayCalendarRepository, scheduleRepo *repository.ScheduleRepository, notifications *NotificationService, audit *AuditService) *ShiftService {
+	return &ShiftService{
+		shiftRepo:     shiftRepo,
+		doctorRepo:    doctorRepo,
+		locationRepo:  locationRepo,
+		calendarRepo:  calendarRepo,
+		scheduleRepo:  scheduleRepo,
+		notifications: notifications,
+		audit:         audit,
+	}
 }
 
 func (s *ShiftService) ResetShiftsForMonth(ctx context.Context, year int, month int, locationID int) error {
@@ -24,15 +69,25 @@ func (s *ShiftService) ResetShiftsForMonth(ctx context.Context, year int, month
 	if !shiftStatus.Done {
 		return errorx.ErrNotFound
 	}
+	if shiftStatus.State != model.ScheduleStateDraft {
+		return errorx.WithDetails(errorx.ErrInvalidRequest, "Yayınlanmış ayın nöbetleri sıfırlanamaz, önce yayından kaldırın")
+	}
 
-	shiftStatus.Done = false
-	if err = s.shiftRepo.UpdateShiftStatus(ctx, shiftStatus); err != nil {
+	shifts, err := s.shiftRepo.GetShiftsByLocationID(ctx, int64(locationID), int64(month), int64(year))
+	if err != nil {
 		return err
 	}
 
-	if err = s.shiftRepo.DeleteShiftsForMonth(ctx, year, month, locationID); err != nil {
+	shiftStatus.Done = false
+	shiftStatus.CoveredShifts = 0
+	shiftStatus.Coverage = coveragePercent(0, shiftStatus.RequiredShifts)
+	if err = s.shiftRepo.ResetMonth(ctx, shiftStatus); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorx.WithDetails(errorx.ErrInvalidTransition, "Ay bu arada güncellenmiş")
+		}
 		return err
 	}
+	s.audit.Record(ctx, model.AuditActionReset, model.AuditEntitySchedule, int64(locationID), newMonthAudit(year, month, shifts), nil)
 
 	return nil
 }
@@ -55,16 +110,80 @@ func (s *ShiftService) MarkShiftStatusAsDone(ctx context.Context, year int, mont
 	return s.shiftRepo.UpdateShiftStatus(ctx, shiftStatus)
 }
 
-func (s *ShiftService) CreateShift(ctx context.Context, shift model.Shift) error {
-	return s.shiftRepo.Create(ctx, shift)
+// Kilitli aylarda overrideReason zorunludur ve değişiklik ayın geçmişine yazılır
+func (s *ShiftService) CreateShift(ctx context.Context, shift model.Shift, userID int64, overrideReason string) error {
+	if err := s.applyTemplate(ctx, &shift); err != nil {
+		return err
+	}
+
+	state, err := s.checkEditable(ctx, shift.LocationID, shift.ShiftDate, overrideReason)
+	if err != nil {
+		return err
+	}
+
+	if err = s.checkShiftRules(ctx, shift); err != nil {
+		return err
+	}
+
+	if err = s.shiftRepo.Create(ctx, &shift); err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	if err = s.refreshCoverage(ctx, shift.LocationID, shift.ShiftDate); err != nil {
+		return err
+	}
+	if err = s.recordOverride(ctx, shift.LocationID, shift.ShiftDate, state, userID, overrideReason); err != nil {
+		return err
+	}
+	s.recordShift(ctx, model.AuditActionCreate, nil, &shift)
+
+	visible := isVisible(state)
+	if visible {
+		s.notifications.ShiftChanged(ctx, shift, "created")
+	}
+	publishShift(ctx, realtime.EventShiftCreated, shift, visible)
+	return nil
 }
 
 func (s *ShiftService) GetShiftByDate(ctx context.Context, date time.Time) (*model.Shift, error) {
 	return s.shiftRepo.GetShiftByDate(ctx, date)
 }
 
-func (s *ShiftService) GetTodayShifts(ctx context.Context, date time.Time) ([]model.Shift, error) {
-	return s.shiftRepo.GetTodayShifts(ctx, date)
+// Bugünün nöbetleri. "Bugün" her lokasyon için kendi politikasındaki saat dilimine göre belirlenir.
+func (s *ShiftService) GetTodayShifts(ctx context.Context) ([]model.Shift, error) {
+	locations, err := s.shiftRepo.GetShiftLocations(ctx)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	today := make(map[int64]string, len(locations))
+	var dates []time.Time
+	for _, location := range locations {
+		p, err := s.policy(ctx, location.ID)
+		if err != nil {
+			return nil, err
+		}
+		day := p.today()
+		today[location.ID] = day.Format("2006-01-02")
+		if !slices.ContainsFunc(dates, day.Equal) {
+			dates = append(dates, day)
+		}
+	}
+	if len(dates) == 0 {
+		return nil, nil
+	}
+
+	shifts, err := s.shiftRepo.GetShiftsByDates(ctx, dates)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]model.Shift, 0, len(shifts))
+	for _, shift := range shifts {
+		if today[shift.LocationID] == shift.ShiftDate.Format("2006-01-02") {
+			result = append(result, shift)
+		}
+	}
+	return result, nil
 }
 
 func (s *ShiftService) GetAllShiftsWithDetails(ctx context.Context) ([]model.Shift, error) {
@@ -83,12 +202,395 @@ func (s *ShiftService) GetShiftByID(ctx context.Context, id int64) (*model.Shift
 	return s.shiftRepo.GetShiftByID(ctx, id)
 }
 
-func (s *ShiftService) DeleteShift(ctx context.Context, id int64) error {
-	return s.shiftRepo.DeleteShift(ctx, id)
+func (s *ShiftService) DeleteShift(ctx context.Context, id int64, userID int64, overrideReason string) error {
+	shift, err := s.shiftRepo.GetShiftByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorx.ErrNotFound
+		}
+		return errorx.ErrDatabaseOperation
+	}
+
+	state, err := s.checkEditable(ctx, shift.LocationID, shift.ShiftDate, overrideReason)
+	if err != nil {
+		return err
+	}
+
+	if err = s.shiftRepo.DeleteShift(ctx, id); err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	if err = s.refreshCoverage(ctx, shift.LocationID, shift.ShiftDate); err != nil {
+		return err
+	}
+	if err = s.recordOverride(ctx, shift.LocationID, shift.ShiftDate, state, userID, overrideReason); err != nil {
+		return err
+	}
+	s.recordShift(ctx, model.AuditActionDelete, shift, nil)
+
+	visible := isVisible(state)
+	if visible {
+		s.notifications.ShiftChanged(ctx, *shift, "deleted")
+	}
+	publishShift(ctx, realtime.EventShiftDeleted, *shift, visible)
+	return nil
+}
+
+// Nöbet başka bir aya taşınıyorsa iki ayın da durumu kontrol edilir
+func (s *ShiftService) UpdateShift(ctx context.Context, shift model.Shift, userID int64, overrideReason string) error {
+	if err := s.applyTemplate(ctx, &shift); err != nil {
+		return err
+	}
+
+	previous, err := s.shiftRepo.GetShiftByID(ctx, shift.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorx.ErrNotFound
+		}
+		return errorx.ErrDatabaseOperation
+	}
+
+	previousState, err := s.checkEditable(ctx, previous.LocationID, previous.ShiftDate, overrideReason)
+	if err != nil {
+		return err
+	}
+	state, err := s.checkEditable(ctx, shift.LocationID, shift.ShiftDate, overrideReason)
+	if err != nil {
+		return err
+	}
+
+	if err = s.checkShiftRules(ctx, shift, shift.ID); err != nil {
+		return err
+	}
+
+	if err = s.shiftRepo.UpdateShift(ctx, shift); err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+
+	if err = s.refreshCoverage(ctx, previous.LocationID, previous.ShiftDate); err != nil {
+		return err
+	}
+	if err = s.refreshCoverage(ctx, shift.LocationID, shift.ShiftDate); err != nil {
+		return err
+	}
+
+	if err = s.recordOverride(ctx, previous.LocationID, previous.ShiftDate, previousState, userID, overrideReason); err != nil {
+		return err
+	}
+	if !sameMonth(*previous, shift) {
+		if err = s.recordOverride(ctx, shift.LocationID, shift.ShiftDate, state, userID, overrideReason); err != nil {
+			return err
+		}
+	}
+	s.recordShift(ctx, model.AuditActionUpdate, previous, &shift)
+
+	// Nöbet başka doktora verildiyse eski doktor için nöbet kaldırılmış olur
+	previousVisible, visible := isVisible(previousState), isVisible(state)
+	switch {
+	case previous.DoctorID != shift.DoctorID || previousVisible != visible:
+		if previousVisible {
+			s.notifications.ShiftChanged(ctx, *previous, "deleted")
+		}
+		if visible {
+			s.notifications.ShiftChanged(ctx, shift, "created")
+		}
+	case visible:
+		s.notifications.ShiftChanged(ctx, shift, "updated")
+	}
+
+	// Lokasyon ya da görünürlük değiştiyse eski ayı izleyen istemciler için nöbet silinmiş olur
+	if previous.LocationID != shift.LocationID || previousVisible != visible {
+		publishShift(ctx, realtime.EventShiftDeleted, *previous, previousVisible)
+		publishShift(ctx, realtime.EventShiftCreated, shift, visible)
+	} else {
+		publishShift(ctx, realtime.EventShiftUpdated, shift, visible)
+	}
+	return nil
 }
 
-func (s *ShiftService) UpdateShift(ctx context.Context, shift model.Shift) error {
-	return s.shiftRepo.UpdateShift(ctx, shift)
+func (s *ShiftService) GetRestRules(ctx context.Context, locationID int64) (*dto.RestRulesDTO, error) {
+	p, err := s.policy(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.RestRulesDTO{}.ToResponseModel(locationID, p.RestRules), nil
+}
+
+// Dinlenme kurallarını değiştirerek lokasyon politikasının yeni sürümünü oluşturur
+func (s *ShiftService) UpdateRestRules(ctx context.Context, locationID int64, req dto.RestRulesDTO, userID int64) (*dto.RestRulesDTO, error) {
+	doc, err := s.editablePolicy(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.RestRules = req.ToDBModel(doc.RestRules)
+	saved, err := savePolicy(ctx, s.locationRepo, locationID, doc, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.RestRulesDTO{}.ToResponseModel(locationID, saved.Document.RestRules), nil
+}
+
+// Güncellenecek lokasyonun geçerli politika belgesi
+func (s *ShiftService) editablePolicy(ctx context.Context, locationID int64) (policy.Document, error) {
+	if _, err := s.locationRepo.GetByID(ctx, locationID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return policy.Document{}, errorx.WithDetails(errorx.ErrNotFound, "Lokasyon bulunamadı")
+		}
+		return policy.Document{}, errorx.ErrDatabaseOperation
+	}
+
+	doc, _, err := loadPolicy(ctx, s.locationRepo, locationID)
+	return doc, err
+}
+
+// Elle yapılan nöbet değişikliğini çift atama ve lokasyonun dinlenme kurallarına göre kontrol eder.
+// excludeIDs, kontrol sırasında yok sayılacak nöbetlerdir (ör. güncellenen nöbetin eski hali).
+func (s *ShiftService) checkShiftRules(ctx context.Context, shift model.Shift, excludeIDs ...int64) error {
+	violations, err := s.shiftViolations(ctx, shift, false, excludeIDs...)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return ruleViolationError(violations)
+	}
+	return nil
+}
+
+// Nöbeti doktorun diğer nöbetlerine karşı doğrular ve ihlalleri döner.
+// strict true ise doktorun tatilleri ve aylık nöbet limiti de kontrol edilir.
+func (s *ShiftService) shiftViolations(ctx context.Context, shift model.Shift, strict bool, excludeIDs ...int64) ([]scheduler.Violation, error) {
+	p, err := s.policy(ctx, shift.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	slot, err := shiftSlot(shift.ShiftDate, shift.StartTime, shift.EndTime, p)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dinlenme kuralları için çevre günler, limit için ayın tamamı okunur
+	monthStart := time.Date(slot.Date.Year(), slot.Date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	from := slot.Date.AddDate(0, 0, -restRuleLookaround)
+	if monthStart.Before(from) {
+		from = monthStart
+	}
+	to := slot.Date.AddDate(0, 0, restRuleLookaround+1)
+	if monthEnd := monthStart.AddDate(0, 1, 0); monthEnd.After(to) {
+		to = monthEnd
+	}
+
+	others, err := s.shiftRepo.GetShiftsByDoctorIDs(ctx, []int64{shift.DoctorID}, from, to)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	doctor := scheduler.Doctor{ID: shift.DoctorID}
+	input := scheduler.Input{Holidays: make(map[int64][]time.Time)}
+	for _, other := range others {
+		if slices.Contains(excludeIDs, other.ID) {
+			continue
+		}
+		otherSlot, err := shiftSlot(other.ShiftDate, other.StartTime, other.EndTime, p)
+		if err != nil {
+			return nil, err
+		}
+		input.Existing = append(input.Existing, scheduler.Assignment{DoctorID: other.DoctorID, Slot: otherSlot})
+	}
+
+	hard := append([]scheduler.HardConstraint{scheduler.NoDoubleBooking{}}, p.restRules().Constraints()...)
+	if strict {
+		m, err := s.doctorRepo.GetByID(ctx, shift.DoctorID)
+		if err != nil {
+			return nil, errorx.WithDetails(errorx.ErrNotFound, "Doktor bulunamadı")
+		}
+		doctor.ShiftLimit = p.ShiftLimit(m.ShiftLimit)
+
+		holidays, err := s.doctorRepo.GetHolidaysByDoctorIDs(ctx, []int64{shift.DoctorID}, slot.Date, slot.Date.AddDate(0, 0, 1))
+		if err != nil {
+			return nil, errorx.ErrDatabaseOperation
+		}
+		for _, holiday := range holidays {
+			input.Holidays[holiday.DoctorID] = append(input.Holidays[holiday.DoctorID], holiday.HolidayDate)
+		}
+
+		hard = append([]scheduler.HardConstraint{scheduler.NotOnHoliday{}, scheduler.WithinShiftLimit{}}, hard...)
+	}
+	input.Doctors = []scheduler.Doctor{doctor}
+
+	return scheduler.NewValidator(hard...).Validate(input, []scheduler.Assignment{{DoctorID: shift.DoctorID, Slot: slot}}), nil
+}
+
+// Kural ihlallerini ihlal edilen kuralın adını taşıyan 409 hatasına çevirir
+func ruleViolationError(violations []scheduler.Violation) error {
+	details := make([]errorx.Detail, len(violations))
+	for i, v := range violations {
+		details[i] = errorx.Detail{
+			Field:   v.Slot.Date.Format("2006-01-02"),
+			Code:    v.Constraint,
+			Message: fmt.Sprintf("Doktor %d için %s kuralı ihlal ediliyor", v.DoctorID, v.Constraint),
+		}
+	}
+	return errorx.WithDetailList(errorx.ErrRuleViolation, details...)
+}
+
+func (s *ShiftService) GetCoverage(ctx context.Context, locationID int64) (*dto.CoverageDTO, error) {
+	p, err := s.policy(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.shiftRepo.GetCoverageOverrides(ctx, locationID, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	return dto.CoverageDTO{}.ToResponseModel(locationID, p.Coverage, overrides), nil
+}
+
+// Lokasyonun haftalık gereksinimlerini değiştirerek politikanın yeni sürümünü oluşturur.
+// Listede olmayan günler için politikadaki varsayılan kullanılır.
+func (s *ShiftService) UpdateCoverage(ctx context.Context, locationID int64, req dto.CoverageUpdateRequest, userID int64) (*dto.CoverageDTO, error) {
+	doc, err := s.editablePolicy(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Default != nil {
+		doc.Coverage.Default = *req.Default
+	}
+	doc.Coverage.Weekdays = make([]policy.WeekdayCoverage, len(req.Weekdays))
+	for i, w := range req.Weekdays {
+		doc.Coverage.Weekdays[i] = w.ToDBModel(policy.WeekdayCoverage{})
+	}
+
+	if _, err = savePolicy(ctx, s.locationRepo, locationID, doc, userID); err != nil {
+		return nil, err
+	}
+
+	return s.GetCoverage(ctx, locationID)
+}
+
+func (s *ShiftService) UpsertCoverageOverride(ctx context.Context, locationID int64, req dto.CoverageOverrideDTO) (*dto.CoverageOverrideDTO, error) {
+	if req.DoctorsRequired < 0 {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Doktor sayısı negatif olamaz")
+	}
+
+	override := req.ToDBModel(model.CoverageOverride{LocationID: locationID})
+	if err := s.shiftRepo.UpsertCoverageOverride(ctx, &override); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := dto.CoverageOverrideDTO{}.ToResponseModel(override)
+	return &result, nil
+}
+
+func (s *ShiftService) DeleteCoverageOverride(ctx context.Context, locationID int64, date time.Time) error {
+	if err := s.shiftRepo.DeleteCoverageOverride(ctx, locationID, date); err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	return nil
+}
+
+// Lokasyonun günlük doktor ihtiyacı. Gün bazlı tanım resmi tatil ayarının, resmi tatil ayarı
+// da politikadaki haftalık tanımın yerine geçer. İhtiyaç her nöbet şablonu için ayrı ayrı uygulanır.
+type coverageSpec struct {
+	policy    locationPolicy
+	dates     map[string]int
+	holidays  publicHolidays
+	templates []model.ShiftTemplate
+}
+
+func (c coverageSpec) required(day time.Time) int {
+	if n, ok := c.dates[day.Format("2006-01-02")]; ok {
+		return n
+	}
+	if h, ok := c.holidays[day.Format("2006-01-02")]; ok && h.doctorsRequired != nil {
+		return *h.doctorsRequired
+	}
+	return c.policy.Required(day.Weekday())
+}
+
+// Gün için doldurulması gereken toplam nöbet sayısı.
+// Şablon tanımlı değilse gün tek bir varsayılan nöbet bloğundan oluşur.
+func (c coverageSpec) slotsPerDay(day time.Time) int {
+	return c.required(day) * max(1, len(c.templates))
+}
+
+// Aralıktaki toplam nöbet ihtiyacı
+func (c coverageSpec) total(start time.Time, end time.Time) int {
+	total := 0
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		total += c.slotsPerDay(day)
+	}
+	return total
+}
+
+func (s *ShiftService) coverage(ctx context.Context, locationID int64, start time.Time, end time.Time) (coverageSpec, error) {
+	spec := coverageSpec{dates: make(map[string]int)}
+
+	var err error
+	if spec.policy, err = s.policy(ctx, locationID); err != nil {
+		return spec, err
+	}
+
+	overrides, err := s.shiftRepo.GetCoverageOverrides(ctx, locationID, start, end)
+	if err != nil {
+		return spec, errorx.ErrDatabaseOperation
+	}
+	for _, o := range overrides {
+		spec.dates[o.OverrideDate.Format("2006-01-02")] = o.DoctorsRequired
+	}
+
+	if spec.holidays, err = s.publicHolidays(ctx, locationID, start, end); err != nil {
+		return spec, err
+	}
+
+	if spec.templates, err = s.shiftRepo.GetTemplatesByLocation(ctx, locationID); err != nil {
+		return spec, errorx.ErrDatabaseOperation
+	}
+
+	return spec, nil
+}
+
+// Elle yapılan değişiklikten sonra günün ait olduğu ayın kapsamasını yeniden hesaplar.
+// Ay için durum kaydı yoksa bir şey yapılmaz.
+func (s *ShiftService) refreshCoverage(ctx context.Context, locationID int64, day time.Time) error {
+	start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	spec, err := s.coverage(ctx, locationID, start, end)
+	if err != nil {
+		return err
+	}
+
+	counts, err := s.shiftRepo.CountShiftsByDate(ctx, locationID, start, end)
+	if err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+
+	status := model.ShiftsStatus{Year: start.Year(), Month: int(start.Month()), LocationID: locationID}
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		required := spec.slotsPerDay(d)
+		status.RequiredShifts += required
+		status.CoveredShifts += min(counts[d.Format("2006-01-02")], required)
+	}
+	status.Coverage = coveragePercent(status.CoveredShifts, status.RequiredShifts)
+
+	if err = s.shiftRepo.UpdateShiftStatusCoverage(ctx, status); err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	return nil
+}
+
+// Doldurulan nöbetlerin gerekenlere yüzdesel oranı. Hiç nöbet gerekmiyorsa kapsama tamdır.
+func coveragePercent(covered int, required int) float64 {
+	if required <= 0 {
+		return 100
+	}
+	return math.Round(float64(covered)/float64(required)*10000) / 100
 }
 
 func (s *ShiftService) GetShiftsStatus(ctx context.Context) ([]model.ShiftsStatus, error) {
@@ -100,9 +602,676 @@ func (s *ShiftService) GetShiftLocations(ctx context.Context) ([]model.ShiftLoca
 }
 
 func (s *ShiftService) GetDoctorsByLocation(ctx context.Context, locationID int64) ([]model.Doctor, error) {
-	return s.shiftRepo.GetDoctorsByLocation(ctx, locationID)
+	return s.doctorRepo.GetByLocation(ctx, locationID)
 }
 
-func (s *ShiftService) AssignShiftsForMonth(ctx context.Context, doctors []model.Doctor, locationID int, startOfMonth time.Time, endOfMonth time.Time) error {
-	return s.shiftRepo.AssignShiftsForMonth(ctx, doctors, locationID, startOfMonth, endOfMonth)
+// Lokasyonun ayını planlama motoruyla doldurur ve sonucu tek transaction içinde kaydeder.
+// Doldurulamayan günler olsa da yapılan atamalar kaydedilir, eksik kalan günler
+// uyarı olarak döner ve ayın kapsama oranına yansır.
+// Ay taslak olarak kalır, doktorlar yayınlandıktan sonra görür.
+func (s *ShiftService) AutoAssignShifts(ctx context.Context, year int, month int, locationID int64) (*dto.AutoAssignResultDTO, error) {
+	plan, err := s.planMonth(ctx, year, month, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := model.ShiftsStatus{
+		Year:           year,
+		Month:          month,
+		LocationID:     locationID,
+		RequiredShifts: plan.required,
+		CoveredShifts:  plan.covered(),
+		Coverage:       coveragePercent(plan.covered(), plan.required),
+	}
+	shifts := planShifts(plan.Plan, locationID)
+	if err = s.shiftRepo.AssignShiftsForMonth(ctx, shifts, status); err != nil {
+		if errors.Is(err, repository.ErrMonthAssigned) {
+			return nil, errorx.WithDetails(errorx.ErrInvalidTransition, "Bu ay için nöbetler bu arada atanmış")
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	s.audit.Record(ctx, model.AuditActionAssign, model.AuditEntitySchedule, locationID, nil, newMonthAudit(year, month, shifts))
+
+	result := autoAssignResult(plan, locationID, year, month)
+	result.Warnings = planWarnings(plan.Plan)
+	return result, nil
+}
+
+// Atamayı hiçbir şey yazmadan çalıştırır. saveDraft true ise sonuç taslak olarak saklanır.
+func (s *ShiftService) PreviewShifts(ctx context.Context, year int, month int, locationID int64, saveDraft bool, userID int64) (*dto.AutoAssignResultDTO, error) {
+	plan, err := s.planMonth(ctx, year, month, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	shifts := planShifts(plan.Plan, locationID)
+
+	result := autoAssignResult(plan, locationID, year, month)
+	result.DryRun = true
+	result.Warnings = planWarnings(plan.Plan)
+	result.Shifts = make([]dto.ShiftResponse, len(shifts))
+	for i, shift := range shifts {
+		result.Shifts[i] = dto.ShiftResponse{}.ToResponseModel(shift)
+	}
+
+	if !saveDraft {
+		return result, nil
+	}
+
+	draft := model.ScheduleDraft{
+		LocationID: locationID,
+		Year:       year,
+		Month:      month,
+		Status:     model.DraftStatusDraft,
+		Complete:   len(plan.Unassigned) == 0,
+		Required:   make(map[string]int),
+		CreatedBy:  userID,
+	}
+	for _, a := range plan.Assignments {
+		draft.Required[a.Slot.Date.Format("2006-01-02")]++
+	}
+	for _, u := range plan.Unassigned {
+		draft.Required[u.Slot.Date.Format("2006-01-02")]++
+	}
+	for _, shift := range shifts {
+		draft.Shifts = append(draft.Shifts, model.DraftShift{
+			DoctorID:   shift.DoctorID,
+			ShiftDate:  shift.ShiftDate,
+			StartTime:  shift.StartTime,
+			EndTime:    shift.EndTime,
+			TemplateID: shift.TemplateID,
+		})
+	}
+	if draft.Report, err = json.Marshal(result); err != nil {
+		return nil, errorx.ErrInternal
+	}
+
+	if err = s.shiftRepo.CreateDraft(ctx, &draft); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	result.DraftID = draft.ID
+
+	return result, nil
+}
+
+func (s *ShiftService) GetDraft(ctx context.Context, id int64) (*dto.ScheduleDraftDTO, error) {
+	draft, err := s.shiftRepo.GetDraftByID(ctx, id)
+	if err != nil {
+		return nil, errorx.ErrNotFound
+	}
+
+	return dto.ScheduleDraftDTO{}.ToResponseModel(*draft), nil
+}
+
+// Taslağı değiştirmeden kaydeder. Taslak oluşturulduktan sonra eklenen tatil ya da
+// nöbetlerle çakışan atama ya da gereksinimi artan gün varsa hiçbir şey yazılmaz ve
+// çakışmalar döner. Eksik taslaklar otomatik atamadaki gibi kapsama oranıyla kaydedilir.
+func (s *ShiftService) CommitDraft(ctx context.Context, id int64) error {
+	draft, err := s.shiftRepo.GetDraftByID(ctx, id)
+	if err != nil {
+		return errorx.ErrNotFound
+	}
+	if draft.Status != model.DraftStatusDraft {
+		return errorx.WithDetails(errorx.ErrInvalidRequest, "Taslak zaten kaydedilmiş")
+	}
+
+	shiftStatus, err := s.shiftRepo.GetShiftStatus(ctx, draft.Year, draft.Month, int(draft.LocationID))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return errorx.ErrDatabaseOperation
+	}
+	if shiftStatus != nil && shiftStatus.Done {
+		return errorx.WithDetails(errorx.ErrInvalidRequest, "Bu ay için nöbetler zaten atanmış")
+	}
+	if shiftStatus != nil && shiftStatus.State != model.ScheduleStateDraft {
+		return errorx.WithDetails(errorx.ErrInvalidRequest, "Yayınlanmış aya taslak kaydedilemez")
+	}
+
+	startOfMonth := time.Date(draft.Year, time.Month(draft.Month), 1, 0, 0, 0, 0, time.UTC)
+	endOfMonth := startOfMonth.AddDate(0, 1, 0)
+
+	doctors, periods, err := s.locationDoctors(ctx, draft.LocationID, startOfMonth, endOfMonth)
+	if err != nil {
+		return err
+	}
+
+	spec, err := s.coverage(ctx, draft.LocationID, startOfMonth, endOfMonth)
+	if err != nil {
+		return err
+	}
+
+	input, err := s.buildSchedulerInput(ctx, doctors, periods, draft.LocationID, startOfMonth, endOfMonth, spec)
+	if err != nil {
+		return err
+	}
+
+	proposed := make([]scheduler.Assignment, len(draft.Shifts))
+	for i, ds := range draft.Shifts {
+		slot, err := shiftSlot(ds.ShiftDate, ds.StartTime, ds.EndTime, spec.policy)
+		if err != nil {
+			return err
+		}
+		proposed[i] = scheduler.Assignment{DoctorID: ds.DoctorID, Slot: slot}
+	}
+
+	var details []errorx.Detail
+	for _, v := range scheduler.NewEngine().WithHard(spec.policy.restRules().Constraints()...).Validate(*input, proposed) {
+		details = append(details, errorx.Detail{
+			Field:   v.Slot.Date.Format("2006-01-02"),
+			Code:    v.Constraint,
+			Message: fmt.Sprintf("Doktor %d için atama artık geçerli değil", v.DoctorID),
+		})
+	}
+
+	// Taslak oluşturulduktan sonra artırılan gereksinimler. Elle girilmiş nöbetlerle
+	// karşılanan kısım düşülür. Gereksinimi kaydedilmemiş eski taslaklarda gün için
+	// taslaktaki nöbet sayısı esas alınır.
+	manual, err := s.shiftRepo.CountShiftsByDate(ctx, draft.LocationID, startOfMonth, endOfMonth)
+	if err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	perDay := make(map[string]int)
+	for _, ds := range draft.Shifts {
+		perDay[ds.ShiftDate.Format("2006-01-02")]++
+	}
+	for day := startOfMonth; day.Before(endOfMonth); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		planned, ok := draft.Required[key]
+		if !ok {
+			planned = perDay[key]
+		}
+		if required := max(0, spec.slotsPerDay(day)-manual[key]); planned < required {
+			details = append(details, errorx.Detail{
+				Field:   key,
+				Code:    "coverage_changed",
+				Message: fmt.Sprintf("Gün için %d nöbet gerekiyor, taslak %d nöbet için hazırlanmış", required, planned),
+			})
+		}
+	}
+
+	if len(details) > 0 {
+		return errorx.WithDetailList(errorx.ErrStaleDraft, details...)
+	}
+
+	status := model.ShiftsStatus{Year: draft.Year, Month: draft.Month, LocationID: draft.LocationID}
+	for day := startOfMonth; day.Before(endOfMonth); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		required := spec.slotsPerDay(day)
+		status.RequiredShifts += required
+		status.CoveredShifts += min(manual[key]+perDay[key], required)
+	}
+	status.Coverage = coveragePercent(status.CoveredShifts, status.RequiredShifts)
+
+	if err = s.shiftRepo.CommitDraft(ctx, id, status); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return errorx.WithDetails(errorx.ErrInvalidRequest, "Taslak zaten kaydedilmiş")
+		case errors.Is(err, repository.ErrMonthAssigned):
+			return errorx.WithDetails(errorx.ErrInvalidTransition, "Bu ay için nöbetler bu arada atanmış")
+		}
+		return errorx.ErrDatabaseOperation
+	}
+
+	shifts := make([]model.Shift, len(draft.Shifts))
+	for i, ds := range draft.Shifts {
+		shifts[i] = ds.ToShift(draft.LocationID)
+	}
+	after := newMonthAudit(draft.Year, draft.Month, shifts)
+	after.DraftID = draft.ID
+	s.audit.Record(ctx, model.AuditActionAssign, model.AuditEntitySchedule, draft.LocationID, nil, after)
+
+	return nil
+}
+
+// Nöbet değişikliğini lokasyonu izleyen istemcilere iletir. Yayınlanmamış aylardaki
+// değişiklikler (visible false) sadece adminlere gider.
+func publishShift(ctx context.Context, eventType string, shift model.Shift, visible bool) {
+	ev := realtime.Event{
+		Type:       eventType,
+		Audience:   realtime.AudienceLocation,
+		LocationID: shift.LocationID,
+		DoctorIDs:  []int64{shift.DoctorID},
+		Data:       dto.ShiftResponse{}.ToResponseModel(shift),
+	}
+	if !visible {
+		ev.Audience = realtime.AudienceParticipants
+		ev.DoctorIDs = nil
+	}
+	realtime.Publish(ctx, ev)
+}
+
+func publishSchedule(ctx context.Context, locationID int64, year int, month int) {
+	realtime.Publish(ctx, realtime.Event{
+		Type:       realtime.EventSchedulePublished,
+		Audience:   realtime.AudienceLocation,
+		LocationID: locationID,
+		Data:       map[string]any{"year": year, "month": month},
+	})
+}
+
+// Nöbet değişikliğini denetim kaydına yazar. Ekleme için before, silme için after nil'dir.
+func (s *ShiftService) recordShift(ctx context.Context, action string, before *model.Shift, after *model.Shift) {
+	var old, current any
+	var id int64
+	if before != nil {
+		old, id = dto.ShiftResponse{}.ToResponseModel(*before), before.ID
+	}
+	if after != nil {
+		current, id = dto.ShiftResponse{}.ToResponseModel(*after), after.ID
+	}
+	s.audit.Record(ctx, action, model.AuditEntityShift, id, old, current)
+}
+
+// Ay bazındaki işlemlerin denetim kaydındaki hali. Kayıt lokasyon üzerinden tutulur.
+type monthAudit struct {
+	Year    int                 `json:"year"`
+	Month   int                 `json:"month"`
+	State   string              `json:"state,omitempty"`
+	Version int                 `json:"version,omitempty"`
+	DraftID int64               `json:"draft_id,omitempty"`
+	Reason  string              `json:"reason,omitempty"`
+	Shifts  []dto.ShiftResponse `json:"shifts,omitempty"`
+}
+
+func newMonthAudit(year int, month int, shifts []model.Shift) monthAudit {
+	m := monthAudit{Year: year, Month: month}
+	for _, shift := range shifts {
+		m.Shifts = append(m.Shifts, dto.ShiftResponse{}.ToResponseModel(shift))
+	}
+	return m
+}
+
+func shiftDoctorIDs(shifts []model.Shift) []int64 {
+	ids := make([]int64, len(shifts))
+	for i, shift := range shifts {
+		ids[i] = shift.DoctorID
+	}
+	return ids
+}
+
+// Ayın planı. Motor yalnızca elle girilmiş nöbetlerden sonra kalan slotları doldurur,
+// required ise elle girilenler dahil ayın toplam nöbet ihtiyacıdır.
+type monthPlan struct {
+	*scheduler.Plan
+	preferences map[int64]scheduler.PreferenceStat
+	required    int
+}
+
+// İhtiyacın elle girilmiş ve planlanan nöbetlerle karşılanan kısmı.
+// Slotlar günün karşılanmamış ihtiyacı kadar açıldığından boş kalan slotlar dışında her şey karşılanmıştır.
+func (p *monthPlan) covered() int {
+	return p.required - len(p.Unassigned)
+}
+
+// Ay için durum kontrollerini yapar ve planlama motorunu çalıştırır
+func (s *ShiftService) planMonth(ctx context.Context, year int, month int, locationID int64) (*monthPlan, error) {
+	if month < 1 || month > 12 {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Geçersiz ay")
+	}
+
+	shiftStatus, err := s.shiftRepo.GetShiftStatus(ctx, year, month, int(locationID))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	if shiftStatus != nil && shiftStatus.Done {
+		return nil, errorx.WithDetails(errorx.ErrInvalidRequest, "Bu ay için nöbetler zaten atanmış")
+	}
+	if shiftStatus != nil && shiftStatus.State != model.ScheduleStateDraft {
+		return nil, errorx.WithDetails(errorx.ErrInvalidRequest, "Yayınlanmış ay yeniden planlanamaz")
+	}
+
+	location, err := s.locationRepo.GetByID(ctx, locationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.WithDetails(errorx.ErrNotFound, "Lokasyon bulunamadı")
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	if location.ArchivedAt != nil {
+		return nil, errorx.WithDetails(errorx.ErrInvalidRequest, "Arşivlenmiş lokasyon için nöbet planlanamaz")
+	}
+
+	startOfMonth := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	endOfMonth := startOfMonth.AddDate(0, 1, 0)
+
+	doctors, periods, err := s.locationDoctors(ctx, locationID, startOfMonth, endOfMonth)
+	if err != nil {
+		return nil, err
+	}
+	if len(doctors) == 0 {
+		return nil, errorx.WithDetails(errorx.ErrNotFound, "Lokasyona bağlı doktor bulunamadı")
+	}
+
+	spec, err := s.coverage(ctx, locationID, startOfMonth, endOfMonth)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := s.buildSchedulerInput(ctx, doctors, periods, locationID, startOfMonth, endOfMonth, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences, err := s.preferences(ctx, doctors, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := scheduler.NewEngine().
+		WithHard(spec.policy.restRules().Constraints()...).
+		WithSoft(preferences, preferenceWeight).
+		Solve(*input)
+	return &monthPlan{
+		Plan:        &plan,
+		preferences: preferences.Report(plan.Assignments),
+		required:    spec.total(startOfMonth, endOfMonth),
+	}, nil
+}
+
+// Aralığın herhangi bir gününde lokasyona üye olan doktorlar ve üyelik dönemleri.
+// Dönemi tüm aralığı kapsayan doktorlar için dönem listesi boş bırakılır.
+func (s *ShiftService) locationDoctors(ctx context.Context, locationID int64, start time.Time, end time.Time) ([]model.Doctor, map[int64][]scheduler.Period, error) {
+	memberships, err := s.doctorRepo.GetMemberships(ctx, locationID, start, end)
+	if err != nil {
+		return nil, nil, errorx.ErrDatabaseOperation
+	}
+
+	var doctors []model.Doctor
+	periods := make(map[int64][]scheduler.Period)
+	always := make(map[int64]bool)
+	for _, m := range memberships {
+		if _, ok := periods[m.DoctorID]; !ok {
+			doctors = append(doctors, m.Doctor)
+			periods[m.DoctorID] = nil
+		}
+
+		period := scheduler.Period{}
+		if m.EffectiveFrom != nil && m.EffectiveFrom.After(start) {
+			period.From = *m.EffectiveFrom
+		}
+		if m.EffectiveTo != nil && m.EffectiveTo.Before(end.AddDate(0, 0, -1)) {
+			period.To = *m.EffectiveTo
+		}
+		if period.From.IsZero() && period.To.IsZero() {
+			always[m.DoctorID] = true
+		}
+		periods[m.DoctorID] = append(periods[m.DoctorID], period)
+	}
+
+	for doctorID := range always {
+		delete(periods, doctorID)
+	}
+	return doctors, periods, nil
+}
+
+// Doktorların ay için girdiği tercihleri planlama motorunun soft kısıtına dönüştürür
+func (s *ShiftService) preferences(ctx context.Context, doctors []model.Doctor, year int, month int) (scheduler.Preferences, error) {
+	doctorIDs := make([]int64, len(doctors))
+	for i, doctor := range doctors {
+		doctorIDs[i] = doctor.ID
+	}
+
+	rows, err := s.doctorRepo.GetPreferencesByDoctorIDs(ctx, doctorIDs, year, month)
+	if err != nil {
+		return scheduler.Preferences{}, errorx.ErrDatabaseOperation
+	}
+
+	result := scheduler.Preferences{ByDoctor: make(map[int64]scheduler.Preference, len(rows))}
+	for _, row := range rows {
+		p := scheduler.Preference{
+			Avoid:     row.AvoidDates,
+			Templates: row.PreferredTemplates,
+			Weight:    row.Weight,
+		}
+		for _, w := range row.PreferredWeekdays {
+			p.Weekdays = append(p.Weekdays, time.Weekday(w))
+		}
+		result.ByDoctor[row.DoctorID] = p
+	}
+
+	return result, nil
+}
+
+func planShifts(plan *scheduler.Plan, locationID int64) []model.Shift {
+	shifts := make([]model.Shift, len(plan.Assignments))
+	for i, a := range plan.Assignments {
+		shifts[i] = model.Shift{
+			DoctorID:   a.DoctorID,
+			LocationID: locationID,
+			ShiftDate:  a.Slot.Date,
+			StartTime:  a.Slot.Start.Format("15:04"),
+			EndTime:    a.Slot.End.Format("15:04"),
+			TemplateID: a.Slot.Template,
+		}
+	}
+	return shifts
+}
+
+// Önizlemede gösterilecek uyarılar: gevşetilerek yapılan ve yapılamayan atamalar
+func planWarnings(plan *scheduler.Plan) []errorx.Detail {
+	var warnings []errorx.Detail
+	for _, r := range plan.Relaxed {
+		warnings = append(warnings, errorx.Detail{
+			Field:   r.Slot.Date.Format("2006-01-02"),
+			Code:    "relaxed_constraint",
+			Message: "Kısıtlar gevşetilerek atandı: " + strings.Join(r.Relaxations, ", "),
+		})
+	}
+	return append(warnings, unassignedDetails(plan.Unassigned)...)
+}
+
+// Doldurulamayan her gün için ayrı bir detay üretir
+func unassignedDetails(unfilled []scheduler.Unfilled) []errorx.Detail {
+	details := make([]errorx.Detail, len(unfilled))
+	for i, u := range unfilled {
+		reasons := make([]string, 0, len(u.Blockers))
+		for name, count := range u.Blockers {
+			reasons = append(reasons, fmt.Sprintf("%s: %d", name, count))
+		}
+		sort.Strings(reasons)
+
+		details[i] = errorx.Detail{
+			Field:   u.Slot.Date.Format("2006-01-02"),
+			Code:    "no_eligible_doctor",
+			Message: fmt.Sprintf("Uygun doktor bulunamadı (%s)", strings.Join(reasons, ", ")),
+		}
+	}
+	return details
+}
+
+// Doktorları, tatilleri ve mevcut nöbetleri planlama motorunun girdisine dönüştürür.
+// Her gün ve her nöbet şablonu için lokasyonun o gün gerektirdiği sayıda slot oluşturulur.
+func (s *ShiftService) buildSchedulerInput(ctx context.Context, doctors []model.Doctor, periods map[int64][]scheduler.Period, locationID int64, start time.Time, end time.Time, spec coverageSpec) (*scheduler.Input, error) {
+	historyStart := start.AddDate(-publicHolidayHistoryYears, 0, 0)
+	calendar, err := s.publicHolidays(ctx, locationID, historyStart, end.AddDate(0, 0, restRuleLookaround))
+	if err != nil {
+		return nil, err
+	}
+
+	doctorIDs := make([]int64, len(doctors))
+	input := &scheduler.Input{Holidays: make(map[int64][]time.Time)}
+	for i, doctor := range doctors {
+		doctorIDs[i] = doctor.ID
+		input.Doctors = append(input.Doctors, scheduler.Doctor{ID: doctor.ID, ShiftLimit: spec.policy.ShiftLimit(doctor.ShiftLimit), Periods: periods[doctor.ID]})
+	}
+
+	holidays, err := s.doctorRepo.GetHolidaysByDoctorIDs(ctx, doctorIDs, start, end)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	for _, holiday := range holidays {
+		input.Holidays[holiday.DoctorID] = append(input.Holidays[holiday.DoctorID], holiday.HolidayDate)
+	}
+
+	// Dönem sınırındaki dinlenme kuralları için önceki ve sonraki günler de okunur
+	existing, err := s.shiftRepo.GetShiftsByDoctorIDs(ctx, doctorIDs, start.AddDate(0, 0, -restRuleLookaround), end.AddDate(0, 0, restRuleLookaround))
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	for _, shift := range existing {
+		slot, err := shiftSlot(shift.ShiftDate, shift.StartTime, shift.EndTime, spec.policy)
+		if err != nil {
+			return nil, err
+		}
+		calendar.apply(&slot)
+		input.Existing = append(input.Existing, scheduler.Assignment{DoctorID: shift.DoctorID, Slot: slot})
+	}
+
+	// Önceki ayların yükü. Resmi tatil nöbetleri yıllar arasında dengelenmek için daha uzun dönemden okunur.
+	past, err := s.shiftRepo.GetShiftsByDoctorIDs(ctx, doctorIDs, historyStart, start)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	fairnessStart := start.AddDate(0, -fairnessHistoryMonths, 0)
+	input.History = make(map[int64]scheduler.History)
+	for _, shift := range past {
+		slot := scheduler.Slot{Date: shift.ShiftDate}
+		slot.Kind, slot.Weight = spec.policy.dayWeight(shift.ShiftDate)
+		calendar.apply(&slot)
+
+		h := input.History[shift.DoctorID]
+		if slot.Kind == scheduler.PublicHoliday {
+			h.PublicHoliday++
+			if !slices.Contains(h.Holidays, slot.Holiday) {
+				h.Holidays = append(h.Holidays, slot.Holiday)
+			}
+		}
+		if !shift.ShiftDate.Before(fairnessStart) {
+			h.Shifts++
+			h.Weighted += slot.Weight
+			if slot.Kind == scheduler.Weekend {
+				h.Weekend++
+			}
+		}
+		input.History[shift.DoctorID] = h
+	}
+
+	// Şablon tanımlı değilse politikadaki varsayılan nöbet bloğu kullanılır
+	templates := spec.templates
+	if len(templates) == 0 {
+		templates = []model.ShiftTemplate{{}}
+	}
+
+	// Lokasyonda elle girilmiş nöbetler ihtiyaçtan düşülür
+	manual, err := s.shiftRepo.CountShiftsByTemplate(ctx, locationID, start, end)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		open := openSlots(spec.required(day), templates, manual[day.Format("2006-01-02")])
+		for i, template := range templates {
+			slot, err := shiftSlot(day, template.StartTime, template.EndTime, spec.policy)
+			if err != nil {
+				return nil, err
+			}
+			slot.Template = template.ID
+			calendar.apply(&slot)
+			for j := 0; j < open[i]; j++ {
+				input.Slots = append(input.Slots, slot)
+			}
+		}
+	}
+
+	return input, nil
+}
+
+// Günün şablon başına açılacak slot sayıları. Elle girilmiş nöbetler önce kendi şablonlarından,
+// hiçbir şablona denk gelmeyen ya da şablonunun ihtiyacını aşanlar kalan ihtiyaçtan sırayla düşülür.
+func openSlots(required int, templates []model.ShiftTemplate, manual map[int64]int) []int {
+	open := make([]int, len(templates))
+	unmatched := 0
+	for _, n := range manual {
+		unmatched += n
+	}
+	for i, template := range templates {
+		n := min(manual[template.ID], required)
+		open[i] = required - n
+		unmatched -= n
+	}
+
+	for i := range open {
+		n := min(unmatched, open[i])
+		open[i] -= n
+		unmatched -= n
+	}
+	return open
+}
+
+// Nöbet tarihini ve HH:MM formatındaki saatleri lokasyonun saat diliminde zaman aralığına çevirir.
+// Saatler boşsa politikadaki varsayılan nöbet bloğu kullanılır, bitiş başlangıçtan küçük ya da eşitse ertesi güne sarkar.
+func shiftSlot(date time.Time, startTime string, endTime string, p locationPolicy) (scheduler.Slot, error) {
+	if startTime == "" {
+		startTime = p.DefaultShift.Start
+	}
+
+	startClock, err := time.Parse("15:04", startTime)
+	if err != nil {
+		return scheduler.Slot{}, errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("Geçersiz başlangıç saati: %s", startTime))
+	}
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	start := time.Date(day.Year(), day.Month(), day.Day(), startClock.Hour(), startClock.Minute(), 0, 0, p.loc)
+
+	end := start.Add(time.Duration(p.DefaultShift.Hours) * time.Hour)
+	if endTime != "" {
+		endClock, err := time.Parse("15:04", endTime)
+		if err != nil {
+			return scheduler.Slot{}, errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("Geçersiz bitiş saati: %s", endTime))
+		}
+		end = time.Date(day.Year(), day.Month(), day.Day(), endClock.Hour(), endClock.Minute(), 0, 0, p.loc)
+		if !end.After(start) {
+			end = end.AddDate(0, 0, 1)
+		}
+	}
+
+	kind, weight := p.dayWeight(day)
+
+	return scheduler.Slot{Date: day, Start: start, End: end, Kind: kind, Weight: weight}, nil
+}
+
+// Planı ve doktor istatistiklerini API yanıtına çevirir
+func autoAssignResult(p *monthPlan, locationID int64, year int, month int) *dto.AutoAssignResultDTO {
+	result := &dto.AutoAssignResultDTO{
+		LocationID:    locationID,
+		Year:          year,
+		Month:         month,
+		AssignedCount: len(p.Assignments),
+		RequiredCount: p.required,
+		Coverage:      coveragePercent(p.covered(), p.required),
+		Score:         p.Score,
+		Redistributed: make([]dto.RedistributedDayDTO, len(p.Relaxed)),
+		Doctors:       make([]dto.DoctorShiftTallyDTO, len(p.Stats)),
+	}
+
+	for i, r := range p.Relaxed {
+		result.Redistributed[i] = dto.RedistributedDayDTO{
+			Date:        r.Slot.Date,
+			DoctorID:    r.DoctorID,
+			Step:        r.Step,
+			Relaxations: r.Relaxations,
+		}
+	}
+
+	for i, stat := range p.Stats {
+		result.Doctors[i] = dto.DoctorShiftTallyDTO{
+			DoctorID:             stat.DoctorID,
+			Shifts:               stat.Shifts,
+			WeekendShifts:        stat.Weekend,
+			HolidayShifts:        stat.PublicHoliday,
+			WeightedLoad:         stat.Weighted,
+			Hours:                stat.Hours,
+			HistoricalShifts:     stat.History.Shifts,
+			HistoricalLoad:       stat.History.Weighted,
+			Deviation:            stat.Deviation,
+			WeightedDeviation:    stat.WeightedDeviation,
+			PreferencesRequested: p.preferences[stat.DoctorID].Requested,
+			PreferencesSatisfied: p.preferences[stat.DoctorID].Satisfied,
+		}
+		result.MeanShifts += float64(stat.Shifts)
+	}
+	if len(p.Stats) > 0 {
+		result.MeanShifts /= float64(len(p.Stats))
+	}
+
+	return result
 }
@@ -0,0 +1,433 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/policy"
+	"shift-scheduling-v2/internal/realtime"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/internal/scheduler"
+	"shift-scheduling-v2/pkg/errorx"
+	"slices"
+	"time"
+)
+
+// Değişim talebinin geçebileceği durumlar
+var swapTransitions = map[string][]string{
+	model.SwapStatusPending:  {model.SwapStatusAccepted, model.SwapStatusApproved, model.SwapStatusRejected, model.SwapStatusCancelled, model.SwapStatusExpired},
+	model.SwapStatusAccepted: {model.SwapStatusApproved, model.SwapStatusRejected, model.SwapStatusCancelled, model.SwapStatusExpired},
+}
+
+type ShiftSwapService struct {
+	swapRepo      *repository.ShiftSwapRepository
+	shiftRepo     *repository.ShiftRepository
+	doctorRepo    *repository.DoctorRepository
+	shiftService  *ShiftService
+	notifications *NotificationService
+}
+
+func NewShiftSwapService(swapRepo *repository.ShiftSwapRepository, shiftRepo *repository.ShiftRepository, doctorRepo *repository.DoctorRepository, shiftService *ShiftService, notifications *NotificationService) *ShiftSwapService {
+	return &ShiftSwapService{
+		swapRepo:      swapRepo,
+		shiftRepo:     shiftRepo,
+		doctorRepo:    doctorRepo,
+		shiftService:  shiftService,
+		notifications: notifications,
+	}
+}
+
+// Doktorun kendi nöbetini meslektaşının nöbetiyle değiştirme talebi oluşturur
+func (s *ShiftSwapService) Create(ctx context.Context, userID int64, req dto.SwapCreateRequest) (*dto.SwapResponseDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	offered, err := s.shift(ctx, req.OfferedShiftID)
+	if err != nil {
+		return nil, err
+	}
+	requested, err := s.shift(ctx, req.RequestedShiftID)
+	if err != nil {
+		return nil, err
+	}
+
+	if offered.DoctorID != doctor.ID {
+		return nil, errorx.WithDetails(errorx.ErrForbidden, "Sadece kendi nöbetinizi değiştirebilirsiniz")
+	}
+	if requested.DoctorID == doctor.ID {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "İstenen nöbet zaten size ait")
+	}
+	if offered.LocationID != requested.LocationID {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Nöbetler aynı lokasyonda olmalı")
+	}
+	p, err := s.shiftService.policy(ctx, offered.LocationID)
+	if err != nil {
+		return nil, err
+	}
+	today := p.today()
+	if !offered.ShiftDate.After(today) || !requested.ShiftDate.After(today) {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Geçmiş ya da bugünkü nöbetler değiştirilemez")
+	}
+	if err = s.shiftService.requirePublished(ctx, offered.LocationID, offered.ShiftDate, requested.ShiftDate); err != nil {
+		return nil, err
+	}
+
+	for _, id := range []int64{offered.ID, requested.ID} {
+		open, err := s.swapRepo.HasOpenForShift(ctx, id)
+		if err != nil {
+			return nil, errorx.ErrDatabaseOperation
+		}
+		if open {
+			return nil, errorx.WithDetails(errorx.ErrDuplicate, fmt.Sprintf("%d numaralı nöbet için sonuçlanmamış bir talep var", id))
+		}
+	}
+
+	if err = s.checkSwap(ctx, *offered, *requested); err != nil {
+		return nil, err
+	}
+
+	swap := req.ToDBModel(model.ShiftSwapRequest{
+		LocationID:       offered.LocationID,
+		RequesterID:      doctor.ID,
+		OfferedShiftDate: offered.ShiftDate,
+		AcceptorID:       requested.DoctorID,
+		RequestShiftDate: requested.ShiftDate,
+		Status:           model.SwapStatusPending,
+	})
+	if err = s.swapRepo.Create(ctx, &swap); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	s.notifications.SwapRequested(ctx, swap)
+
+	return s.changed(ctx, swap.ID)
+}
+
+// Karşı taraf talebi kabul eder. Lokasyon admin onayı istemiyorsa değişim hemen uygulanır.
+func (s *ShiftSwapService) Accept(ctx context.Context, userID int64, id int64, comment string) (*dto.SwapResponseDTO, error) {
+	swap, err := s.participant(ctx, userID, id, func(swap *model.ShiftSwapRequest, doctorID int64) bool { return swap.AcceptorID == doctorID })
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.shiftService.policy(ctx, swap.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	swap.AcceptorComment = comment
+	swap.MutualAgreement = true
+
+	// Onay gerekmiyorsa kabul edilen değişim doğrudan uygulanır
+	if p.SwapRequiresApproval {
+		err = s.update(ctx, swap, model.SwapStatusAccepted)
+	} else {
+		err = s.execute(ctx, swap, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.notifications.SwapAnswered(ctx, *swap)
+
+	return s.changed(ctx, swap.ID)
+}
+
+func (s *ShiftSwapService) Reject(ctx context.Context, userID int64, id int64, comment string) (*dto.SwapResponseDTO, error) {
+	swap, err := s.participant(ctx, userID, id, func(swap *model.ShiftSwapRequest, doctorID int64) bool { return swap.AcceptorID == doctorID })
+	if err != nil {
+		return nil, err
+	}
+	if swap.Status != model.SwapStatusPending {
+		return nil, transitionError(swap.Status, model.SwapStatusRejected)
+	}
+
+	swap.AcceptorComment = comment
+	if err = s.update(ctx, swap, model.SwapStatusRejected); err != nil {
+		return nil, err
+	}
+	s.notifications.SwapAnswered(ctx, *swap)
+
+	return s.changed(ctx, swap.ID)
+}
+
+func (s *ShiftSwapService) Cancel(ctx context.Context, userID int64, id int64) (*dto.SwapResponseDTO, error) {
+	swap, err := s.participant(ctx, userID, id, func(swap *model.ShiftSwapRequest, doctorID int64) bool { return swap.RequesterID == doctorID })
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.update(ctx, swap, model.SwapStatusCancelled); err != nil {
+		return nil, err
+	}
+	s.notifications.SwapAnswered(ctx, *swap)
+
+	return s.changed(ctx, swap.ID)
+}
+
+// Admin kabul edilmiş talebi onaylar, kurallar yeniden kontrol edilip değişim uygulanır
+func (s *ShiftSwapService) Approve(ctx context.Context, adminID int64, id int64) (*dto.SwapResponseDTO, error) {
+	swap, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.execute(ctx, swap, adminID); err != nil {
+		return nil, err
+	}
+	s.notifications.SwapAnswered(ctx, *swap)
+
+	return s.changed(ctx, swap.ID)
+}
+
+// Admin kabul edilmiş talebi reddeder
+func (s *ShiftSwapService) Deny(ctx context.Context, adminID int64, id int64) (*dto.SwapResponseDTO, error) {
+	swap, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if swap.Status != model.SwapStatusAccepted {
+		return nil, transitionError(swap.Status, model.SwapStatusRejected)
+	}
+
+	now := time.Now()
+	swap.ReviewedBy = adminID
+	swap.ReviewedAt = &now
+	if err = s.update(ctx, swap, model.SwapStatusRejected); err != nil {
+		return nil, err
+	}
+	s.notifications.SwapAnswered(ctx, *swap)
+
+	return s.changed(ctx, swap.ID)
+}
+
+// Doktorun taraf olduğu talepler
+func (s *ShiftSwapService) ListMine(ctx context.Context, userID int64) ([]dto.SwapResponseDTO, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.swapRepo.ExpireDue(ctx, time.Now()); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	swaps, err := s.swapRepo.ListByDoctor(ctx, doctor.ID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return swapResponses(swaps), nil
+}
+
+func (s *ShiftSwapService) List(ctx context.Context, status string, locationID int64) ([]dto.SwapResponseDTO, error) {
+	if status != "" && status != model.SwapStatusPending && !slices.Contains(swapTransitions[model.SwapStatusPending], status) {
+		return nil, errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("Geçersiz durum: %s", status))
+	}
+
+	if err := s.swapRepo.ExpireDue(ctx, time.Now()); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	swaps, err := s.swapRepo.List(ctx, status, locationID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return swapResponses(swaps), nil
+}
+
+// Kuralları iki doktor için yeniden kontrol eder ve nöbetleri tek transaction içinde değiştirir
+func (s *ShiftSwapService) execute(ctx context.Context, swap *model.ShiftSwapRequest, adminID int64) error {
+	// Admin sadece kabul edilmiş talebi onaylayabilir, onaysız lokasyonda kabul ve onay aynı anda gerçekleşir
+	from := swap.Status
+	if adminID != 0 && from != model.SwapStatusAccepted {
+		return transitionError(from, model.SwapStatusApproved)
+	}
+	if err := s.transition(swap, model.SwapStatusApproved); err != nil {
+		return err
+	}
+
+	offered, err := s.shift(ctx, swap.OfferedShiftID)
+	if err != nil {
+		return err
+	}
+	requested, err := s.shift(ctx, swap.RequestedShiftID)
+	if err != nil {
+		return err
+	}
+	if offered.DoctorID != swap.RequesterID || requested.DoctorID != swap.AcceptorID {
+		return errorx.WithDetails(errorx.ErrInvalidTransition, "Nöbetler talep oluşturulduktan sonra değişmiş")
+	}
+	if err = s.shiftService.requirePublished(ctx, swap.LocationID, offered.ShiftDate, requested.ShiftDate); err != nil {
+		return err
+	}
+
+	if err = s.checkSwap(ctx, *offered, *requested); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	swap.ResolvedAt = &now
+	if adminID != 0 {
+		swap.ReviewedBy = adminID
+		swap.ReviewedAt = &now
+	}
+
+	if err = s.swapRepo.ExecuteSwap(ctx, swap, from); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return errorx.WithDetails(errorx.ErrInvalidTransition, "Talep bu arada güncellenmiş")
+		case errors.Is(err, repository.ErrSwapShiftsChanged):
+			return errorx.WithDetails(errorx.ErrInvalidTransition, "Nöbetler talep oluşturulduktan sonra değişmiş")
+		}
+		return errorx.ErrDatabaseOperation
+	}
+
+	offeredAfter, requestedAfter := *offered, *requested
+	offeredAfter.DoctorID, requestedAfter.DoctorID = requested.DoctorID, offered.DoctorID
+	s.shiftService.recordShift(ctx, model.AuditActionSwap, offered, &offeredAfter)
+	s.shiftService.recordShift(ctx, model.AuditActionSwap, requested, &requestedAfter)
+	return nil
+}
+
+// Her iki doktorun yeni nöbetini tatil, dinlenme kuralları ve aylık limite göre kontrol eder
+func (s *ShiftSwapService) checkSwap(ctx context.Context, offered model.Shift, requested model.Shift) error {
+	requesterGets := requested
+	requesterGets.DoctorID = offered.DoctorID
+	acceptorGets := offered
+	acceptorGets.DoctorID = requested.DoctorID
+
+	var violations []scheduler.Violation
+	for _, c := range []struct {
+		shift   model.Shift
+		exclude int64
+	}{
+		{requesterGets, offered.ID},
+		{acceptorGets, requested.ID},
+	} {
+		v, err := s.shiftService.shiftViolations(ctx, c.shift, true, c.exclude)
+		if err != nil {
+			return err
+		}
+		violations = append(violations, v...)
+	}
+
+	if len(violations) > 0 {
+		return ruleViolationError(violations)
+	}
+	return nil
+}
+
+// Talebi verilen duruma geçirir ve kaydeder
+func (s *ShiftSwapService) update(ctx context.Context, swap *model.ShiftSwapRequest, to string) error {
+	from := swap.Status
+	if err := s.transition(swap, to); err != nil {
+		return err
+	}
+
+	if to != model.SwapStatusAccepted {
+		now := time.Now()
+		swap.ResolvedAt = &now
+	}
+
+	if err := s.swapRepo.UpdateStatus(ctx, swap, from); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorx.WithDetails(errorx.ErrInvalidTransition, "Talep bu arada güncellenmiş")
+		}
+		return errorx.ErrDatabaseOperation
+	}
+	return nil
+}
+
+func (s *ShiftSwapService) transition(swap *model.ShiftSwapRequest, to string) error {
+	if !slices.Contains(swapTransitions[swap.Status], to) {
+		return transitionError(swap.Status, to)
+	}
+	swap.Status = to
+	return nil
+}
+
+// Talebi süresi dolanları işaretledikten sonra okur
+func (s *ShiftSwapService) load(ctx context.Context, id int64) (*model.ShiftSwapRequest, error) {
+	if err := s.swapRepo.ExpireDue(ctx, time.Now()); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	swap, err := s.swapRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.ErrNotFound
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return swap, nil
+}
+
+// Talebi okur ve kullanıcının talepteki rolünü kontrol eder
+func (s *ShiftSwapService) participant(ctx context.Context, userID int64, id int64, allowed func(*model.ShiftSwapRequest, int64) bool) (*model.ShiftSwapRequest, error) {
+	doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	swap, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed(swap, doctor.ID) {
+		return nil, errorx.WithDetails(errorx.ErrForbidden, "Bu talep üzerinde işlem yapamazsınız")
+	}
+	return swap, nil
+}
+
+func (s *ShiftSwapService) shift(ctx context.Context, id int64) (*model.Shift, error) {
+	shift, err := s.shiftRepo.GetShiftByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.WithDetails(errorx.ErrNotFound, fmt.Sprintf("%d numaralı nöbet bulunamadı", id))
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+	return shift, nil
+}
+
+// Güncel talebi okur ve iki tarafı ile adminlere canlı olarak iletir
+func (s *ShiftSwapService) changed(ctx context.Context, id int64) (*dto.SwapResponseDTO, error) {
+	swap, err := s.swapRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := dto.SwapResponseDTO{}.ToResponseModel(*swap)
+	realtime.Publish(ctx, realtime.Event{
+		Type:       realtime.EventSwapChanged,
+		Audience:   realtime.AudienceParticipants,
+		LocationID: swap.LocationID,
+		DoctorIDs:  []int64{swap.RequesterID, swap.AcceptorID},
+		Data:       result,
+	})
+	return &result, nil
+}
+
+func swapResponses(swaps []model.ShiftSwapRequest) []dto.SwapResponseDTO {
+	result := make([]dto.SwapResponseDTO, len(swaps))
+	for i, swap := range swaps {
+		result[i] = dto.SwapResponseDTO{}.ToResponseModel(swap)
+	}
+	return result
+}
+
+func transitionError(from string, to string) error {
+	return errorx.WithDetails(errorx.ErrInvalidTransition, fmt.Sprintf("%s -> %s", from, to))
+}
+
+// Lokasyondan bağımsız işlemler için varsayılan saat dilimine göre bugünün UTC gün başlangıcı
+func startOfToday() time.Time {
+	now := time.Now()
+	if loc, err := time.LoadLocation(policy.DefaultTimeZone); err == nil {
+		now = now.In(loc)
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/pkg/errorx"
+	"sort"
+	"time"
+)
+
+func (s *ShiftService) GetTemplates(ctx context.Context, locationID int64) ([]dto.ShiftTemplateDTO, error) {
+	templates, err := s.shiftRepo.GetTemplatesByLocation(ctx, locationID)
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := make([]dto.ShiftTemplateDTO, len(templates))
+	for i, template := range templates {
+		result[i] = dto.ShiftTemplateDTO{}.ToResponseModel(template)
+	}
+	return result, nil
+}
+
+func (s *ShiftService) CreateTemplate(ctx context.Context, locationID int64, req dto.ShiftTemplateRequest) (*dto.ShiftTemplateDTO, error) {
+	template := req.ToDBModel(model.ShiftTemplate{LocationID: locationID})
+	if err := setTemplateDuration(&template); err != nil {
+		return nil, err
+	}
+
+	if err := s.shiftRepo.CreateTemplate(ctx, &template); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := dto.ShiftTemplateDTO{}.ToResponseModel(template)
+	return &result, nil
+}
+
+// Şablonu günceller. Daha önce bu şablonla oluşturulmuş nöbetlerin saatleri değişmez.
+func (s *ShiftService) UpdateTemplate(ctx context.Context, id int64, req dto.ShiftTemplateRequest) (*dto.ShiftTemplateDTO, error) {
+	template, err := s.shiftRepo.GetTemplateByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorx.ErrNotFound
+		}
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	*template = req.ToDBModel(*template)
+	if err = setTemplateDuration(template); err != nil {
+		return nil, err
+	}
+
+	if err = s.shiftRepo.UpdateTemplate(ctx, template); err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	result := dto.ShiftTemplateDTO{}.ToResponseModel(*template)
+	return &result, nil
+}
+
+func (s *ShiftService) DeleteTemplate(ctx context.Context, id int64) error {
+	if _, err := s.shiftRepo.GetTemplateByID(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorx.ErrNotFound
+		}
+		return errorx.ErrDatabaseOperation
+	}
+
+	if err := s.shiftRepo.DeleteTemplate(ctx, id); err != nil {
+		return errorx.ErrDatabaseOperation
+	}
+	return nil
+}
+
+// Lokasyonun verilen ayda fiilen çalışılan saatlerini doktor bazında toplar.
+// Süreler lokasyonun saat dilimine göre hesaplanır, gece yarısını geçen nöbetler doğru sayılır.
+func (s *ShiftService) GetWorkedHours(ctx context.Context, locationID int64, year int, month int) (*dto.WorkedHoursDTO, error) {
+	if month < 1 || month > 12 {
+		return nil, errorx.WithDetails(errorx.ErrValidation, "Geçersiz ay")
+	}
+
+	shifts, err := s.shiftRepo.GetShiftsByLocationID(ctx, locationID, int64(month), int64(year))
+	if err != nil {
+		return nil, errorx.ErrDatabaseOperation
+	}
+
+	p, err := s.policy(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.WorkedHoursDTO{LocationID: locationID, Year: year, Month: month}
+	byDoctor := make(map[int64]*dto.DoctorHoursDTO)
+	for _, shift := range shifts {
+		slot, err := shiftSlot(shift.ShiftDate, shift.StartTime, shift.EndTime, p)
+		if err != nil {
+			return nil, err
+		}
+
+		entry, ok := byDoctor[shift.DoctorID]
+		if !ok {
+			entry = &dto.DoctorHoursDTO{
+				DoctorID:      shift.DoctorID,
+				DoctorName:    shift.Doctor.User.Name,
+				DoctorSurname: shift.Doctor.User.Surname,
+			}
+			byDoctor[shift.DoctorID] = entry
+		}
+
+		hours := slot.End.Sub(slot.Start).Hours()
+		entry.Shifts++
+		entry.Hours += hours
+		result.TotalHours += hours
+	}
+
+	result.Doctors = make([]dto.DoctorHoursDTO, 0, len(byDoctor))
+	for _, entry := range byDoctor {
+		result.Doctors = append(result.Doctors, *entry)
+	}
+	sort.Slice(result.Doctors, func(i, j int) bool { return result.Doctors[i].DoctorID < result.Doctors[j].DoctorID })
+
+	return result, nil
+}
+
+// Nöbetin saatlerini şablondan alır. Şablon başka bir lokasyona aitse hata döner.
+func (s *ShiftService) applyTemplate(ctx context.Context, shift *model.Shift) error {
+	if shift.TemplateID == 0 {
+		return nil
+	}
+
+	template, err := s.shiftRepo.GetTemplateByID(ctx, shift.TemplateID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorx.WithDetails(errorx.ErrNotFound, "Nöbet şablonu bulunamadı")
+		}
+		return errorx.ErrDatabaseOperation
+	}
+	if template.LocationID != shift.LocationID {
+		return errorx.WithDetails(errorx.ErrValidation, "Nöbet şablonu bu lokasyona ait değil")
+	}
+
+	shift.StartTime = template.StartTime
+	shift.EndTime = template.EndTime
+	return nil
+}
+
+// Şablonun saatlerini doğrular ve süresini hesaplar.
+// Bitiş başlangıçtan küçük ya da eşitse nöbet ertesi güne sarkar, eşitse 24 saattir.
+func setTemplateDuration(template *model.ShiftTemplate) error {
+	start, err := time.Parse("15:04", template.StartTime)
+	if err != nil {
+		return errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("Geçersiz başlangıç saati: %s", template.StartTime))
+	}
+	end, err := time.Parse("15:04", template.EndTime)
+	if err != nil {
+		return errorx.WithDetails(errorx.ErrValidation, fmt.Sprintf("Geçersiz bitiş saati: %s", template.EndTime))
+	}
+	if template.Name == "" {
+		return errorx.WithDetails(errorx.ErrValidation, "Şablon adı boş olamaz")
+	}
+
+	duration := end.Sub(start)
+	if duration <= 0 {
+		duration += 24 * time.Hour
+	}
+	template.DurationMinutes = int(duration.Minutes())
+	return nil
+}
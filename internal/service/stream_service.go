@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/realtime"
+	"shift-scheduling-v2/internal/repository"
+	"shift-scheduling-v2/pkg/errorx"
+)
+
+type StreamService struct {
+	doctorRepo *repository.DoctorRepository
+}
+
+func NewStreamService(doctorRepo *repository.DoctorRepository) *StreamService {
+	return &StreamService{doctorRepo: doctorRepo}
+}
+
+// Kullanıcının rolüne ve lokasyonlarına göre filtrelenmiş olay aboneliği açar.
+// locationID 0 değilse sadece o lokasyonun olayları gelir.
+func (s *StreamService) Subscribe(ctx context.Context, userID int64, role model.Role, locationID int64) (*realtime.Subscription, error) {
+	filter := realtime.Filter{Location: locationID}
+
+	switch role {
+	case model.UserRoleAdmin:
+		filter.Admin = true
+	case model.UserRoleDoctor:
+		doctor, err := doctorForUser(ctx, s.doctorRepo, userID)
+		if err != nil {
+			return nil, err
+		}
+		locations, err := s.doctorRepo.GetLocationIDs(ctx, doctor.ID)
+		if err != nil {
+			return nil, errorx.ErrDatabaseOperation
+		}
+		filter.DoctorID = doctor.ID
+		filter.Locations = locations
+	default:
+		return nil, errorx.WithDetails(errorx.ErrForbidden, "Canlı akış sadece doktor ve adminler için")
+	}
+
+	return realtime.Subscribe(filter), nil
+}
@@ -3,17 +3,20 @@ package service
 import (
 	"context"
 	"shift-scheduling-v2/internal/dto"
+	"shift-scheduling-v2/internal/model"
 	"shift-scheduling-v2/internal/repository"
 	"shift-scheduling-v2/pkg/errorx"
 )
 
 type UserService struct {
 	userRepo *repository.UserRepository
+	audit    *AuditService
 }
 
-func NewUserService(userRepo *repository.UserRepository) *UserService {
+func NewUserService(userRepo *repository.UserRepository, audit *AuditService) *UserService {
 	return &UserService{
 		userRepo: userRepo,
+		audit:    audit,
 	}
 }
 
@@ -57,18 +60,26 @@ func (s *UserService) Update(ctx context.Context, id int64, req *dto.UserCreateD
 		}
 	}
 
+	before := dto.UserResponseDTO{}.ToResponseModel(*user)
 	req.ToDBModel(*user)
 
 	if err = s.userRepo.Update(ctx, user); err != nil {
 		return errorx.ErrDatabaseOperation
 	}
+	s.audit.Record(ctx, model.AuditActionUpdate, model.AuditEntityUser, user.ID, before, dto.UserResponseDTO{}.ToResponseModel(*user))
 
 	return nil
 }
 
 func (s *UserService) Delete(ctx context.Context, id int64) error {
+	var before any
+	if user, err := s.userRepo.GetByID(ctx, id); err == nil {
+		before = dto.UserResponseDTO{}.ToResponseModel(*user)
+	}
+
 	if err := s.userRepo.Delete(ctx, id); err != nil {
 		return errorx.ErrDatabaseOperation
 	}
+	s.audit.Record(ctx, model.AuditActionDelete, model.AuditEntityUser, id, before, nil)
 	return nil
 }
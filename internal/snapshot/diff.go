@@ -0,0 +1,115 @@
+package snapshot
+
+import (
+	"shift-scheduling-v2/internal/model"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Değişiklik türleri
+const (
+	ChangeAdded      = "added"
+	ChangeRemoved    = "removed"
+	ChangeReassigned = "reassigned"
+)
+
+// İki nöbet listesi arasındaki tek bir fark
+type Change struct {
+	Type         string    `json:"type"`
+	Date         time.Time `json:"date"`
+	StartTime    string    `json:"start_time"`
+	EndTime      string    `json:"end_time"`
+	TemplateID   int64     `json:"template_id,omitempty"`
+	FromDoctorID int64     `json:"from_doctor_id,omitempty"`
+	ToDoctorID   int64     `json:"to_doctor_id,omitempty"`
+}
+
+// Aynı gün, saat ve şablondaki nöbetler tek bir slot sayılır
+type slot struct {
+	date       string
+	start      string
+	end        string
+	templateID int64
+}
+
+// from listesinden to listesine geçişteki farkları döner. Aynı slotta doktoru değişen
+// nöbetler yeniden atama, eşi olmayanlar ekleme ya da çıkarma olarak raporlanır.
+func Diff(from []model.DraftShift, to []model.DraftShift) []Change {
+	before := group(from)
+	after := group(to)
+
+	keys := make([]slot, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.date != b.date {
+			return a.date < b.date
+		}
+		if a.start != b.start {
+			return a.start < b.start
+		}
+		if a.end != b.end {
+			return a.end < b.end
+		}
+		return a.templateID < b.templateID
+	})
+
+	var changes []Change
+	for _, key := range keys {
+		removed, added := subtract(before[key], after[key]), subtract(after[key], before[key])
+		date, _ := time.Parse("2006-01-02", key.date)
+		change := Change{Date: date, StartTime: key.start, EndTime: key.end, TemplateID: key.templateID}
+
+		n := min(len(removed), len(added))
+		for i := 0; i < n; i++ {
+			c := change
+			c.Type, c.FromDoctorID, c.ToDoctorID = ChangeReassigned, removed[i], added[i]
+			changes = append(changes, c)
+		}
+		for _, doctorID := range removed[n:] {
+			c := change
+			c.Type, c.FromDoctorID = ChangeRemoved, doctorID
+			changes = append(changes, c)
+		}
+		for _, doctorID := range added[n:] {
+			c := change
+			c.Type, c.ToDoctorID = ChangeAdded, doctorID
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+func group(shifts []model.DraftShift) map[slot][]int64 {
+	result := make(map[slot][]int64)
+	for _, s := range shifts {
+		key := slot{date: s.ShiftDate.Format("2006-01-02"), start: s.StartTime, end: s.EndTime, templateID: s.TemplateID}
+		result[key] = append(result[key], s.DoctorID)
+	}
+	for key := range result {
+		slices.Sort(result[key])
+	}
+	return result
+}
+
+// a'da olup b'de olmayan doktorlar. Aynı doktor slotta birden fazla kez olabilir.
+func subtract(a []int64, b []int64) []int64 {
+	rest := slices.Clone(b)
+	var result []int64
+	for _, id := range a {
+		if i := slices.Index(rest, id); i >= 0 {
+			rest = slices.Delete(rest, i, i+1)
+			continue
+		}
+		result = append(result, id)
+	}
+	return result
+}
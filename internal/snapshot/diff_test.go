@@ -0,0 +1,69 @@
+package snapshot_test
+
+import (
+	"shift-scheduling-v2/internal/model"
+	"shift-scheduling-v2/internal/snapshot"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func draftShift(doctorID int64, day int, start string, end string) model.DraftShift {
+	return model.DraftShift{
+		DoctorID:  doctorID,
+		ShiftDate: time.Date(2025, 3, day, 0, 0, 0, 0, time.UTC),
+		StartTime: start,
+		EndTime:   end,
+	}
+}
+
+func TestSnapshotDiffIdenticalListsHaveNoChanges(t *testing.T) {
+	shifts := []model.DraftShift{draftShift(1, 3, "08:00", "08:00"), draftShift(2, 4, "08:00", "08:00")}
+	reordered := []model.DraftShift{shifts[1], shifts[0]}
+
+	assert.Empty(t, snapshot.Diff(shifts, reordered))
+}
+
+func TestSnapshotDiffReportsReassignedAddedAndRemoved(t *testing.T) {
+	from := []model.DraftShift{
+		draftShift(1, 3, "08:00", "08:00"),
+		draftShift(2, 4, "08:00", "20:00"),
+		draftShift(3, 4, "08:00", "20:00"),
+		draftShift(4, 5, "08:00", "08:00"),
+	}
+	to := []model.DraftShift{
+		draftShift(5, 3, "08:00", "08:00"), // 1 -> 5
+		draftShift(2, 4, "08:00", "20:00"),
+		draftShift(3, 4, "08:00", "20:00"),
+		draftShift(6, 4, "20:00", "08:00"), // yeni slot
+	}
+
+	changes := snapshot.Diff(from, to)
+	require.Len(t, changes, 3)
+
+	assert.Equal(t, snapshot.ChangeReassigned, changes[0].Type)
+	assert.Equal(t, 3, changes[0].Date.Day())
+	assert.Equal(t, int64(1), changes[0].FromDoctorID)
+	assert.Equal(t, int64(5), changes[0].ToDoctorID)
+
+	assert.Equal(t, snapshot.ChangeAdded, changes[1].Type)
+	assert.Equal(t, "20:00", changes[1].StartTime)
+	assert.Equal(t, int64(6), changes[1].ToDoctorID)
+	assert.Zero(t, changes[1].FromDoctorID)
+
+	assert.Equal(t, snapshot.ChangeRemoved, changes[2].Type)
+	assert.Equal(t, 5, changes[2].Date.Day())
+	assert.Equal(t, int64(4), changes[2].FromDoctorID)
+}
+
+func TestSnapshotDiffCountsDuplicateDoctorsInSlot(t *testing.T) {
+	from := []model.DraftShift{draftShift(1, 3, "08:00", "08:00"), draftShift(1, 3, "08:00", "08:00")}
+	to := []model.DraftShift{draftShift(1, 3, "08:00", "08:00")}
+
+	changes := snapshot.Diff(from, to)
+	require.Len(t, changes, 1)
+	assert.Equal(t, snapshot.ChangeRemoved, changes[0].Type)
+	assert.Equal(t, int64(1), changes[0].FromDoctorID)
+}
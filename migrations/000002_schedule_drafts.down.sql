@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_schedule_drafts_updated_at ON schedule_drafts;
+DROP TABLE IF EXISTS schedule_drafts;
@@ -0,0 +1,23 @@
+-- Create schedule_drafts table
+CREATE TABLE schedule_drafts (
+    id BIGSERIAL PRIMARY KEY,
+    location_id BIGINT NOT NULL REFERENCES shift_locations(id),
+    year INTEGER NOT NULL,
+    month INTEGER NOT NULL CHECK (month BETWEEN 1 AND 12),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    shifts JSONB NOT NULL DEFAULT '[]',
+    report JSONB,
+    complete BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by BIGINT REFERENCES users(id),
+    committed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_schedule_drafts_location_month ON schedule_drafts(location_id, year, month) WHERE deleted_at IS NULL;
+
+CREATE TRIGGER update_schedule_drafts_updated_at
+    BEFORE UPDATE ON schedule_drafts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_location_rest_rules_updated_at ON location_rest_rules;
+DROP TABLE IF EXISTS location_rest_rules;
@@ -0,0 +1,16 @@
+-- Create location_rest_rules table
+CREATE TABLE location_rest_rules (
+    id BIGSERIAL PRIMARY KEY,
+    location_id BIGINT NOT NULL UNIQUE REFERENCES shift_locations(id),
+    min_rest_hours INTEGER NOT NULL DEFAULT 0 CHECK (min_rest_hours >= 0),
+    max_consecutive_days INTEGER NOT NULL DEFAULT 0 CHECK (max_consecutive_days >= 0),
+    max_shifts_per_week INTEGER NOT NULL DEFAULT 0 CHECK (max_shifts_per_week >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TRIGGER update_location_rest_rules_updated_at
+    BEFORE UPDATE ON location_rest_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS update_coverage_overrides_updated_at ON coverage_overrides;
+DROP TRIGGER IF EXISTS update_coverage_requirements_updated_at ON coverage_requirements;
+
+ALTER TABLE shifts_status
+    DROP COLUMN IF EXISTS coverage,
+    DROP COLUMN IF EXISTS covered_shifts,
+    DROP COLUMN IF EXISTS required_shifts;
+
+DROP TABLE IF EXISTS coverage_overrides;
+DROP TABLE IF EXISTS coverage_requirements;
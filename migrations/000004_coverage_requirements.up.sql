@@ -0,0 +1,39 @@
+-- Create coverage_requirements table
+CREATE TABLE coverage_requirements (
+    id BIGSERIAL PRIMARY KEY,
+    location_id BIGINT NOT NULL REFERENCES shift_locations(id),
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6), -- 0: Pazar
+    doctors_required INTEGER NOT NULL CHECK (doctors_required >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (location_id, weekday)
+);
+
+-- Create coverage_overrides table
+CREATE TABLE coverage_overrides (
+    id BIGSERIAL PRIMARY KEY,
+    location_id BIGINT NOT NULL REFERENCES shift_locations(id),
+    override_date DATE NOT NULL,
+    doctors_required INTEGER NOT NULL CHECK (doctors_required >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (location_id, override_date)
+);
+
+-- Add coverage columns to shifts_status
+ALTER TABLE shifts_status
+    ADD COLUMN required_shifts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN covered_shifts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN coverage NUMERIC(5, 2) NOT NULL DEFAULT 0;
+
+CREATE TRIGGER update_coverage_requirements_updated_at
+    BEFORE UPDATE ON coverage_requirements
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_coverage_overrides_updated_at
+    BEFORE UPDATE ON coverage_overrides
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS update_shift_templates_updated_at ON shift_templates;
+
+DROP INDEX IF EXISTS idx_shifts_template_id;
+ALTER TABLE shifts DROP COLUMN IF EXISTS template_id;
+
+DROP INDEX IF EXISTS idx_shift_templates_location_name;
+DROP TABLE IF EXISTS shift_templates;
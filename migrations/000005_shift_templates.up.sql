@@ -0,0 +1,24 @@
+-- Create shift_templates table
+CREATE TABLE shift_templates (
+    id BIGSERIAL PRIMARY KEY,
+    location_id BIGINT NOT NULL REFERENCES shift_locations(id),
+    name VARCHAR(100) NOT NULL,
+    start_time VARCHAR(5) NOT NULL, -- Format: HH:MM
+    end_time VARCHAR(5) NOT NULL,   -- Format: HH:MM, başlangıçtan küçük ya da eşitse ertesi gün
+    duration_minutes INTEGER NOT NULL CHECK (duration_minutes > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_shift_templates_location_name ON shift_templates(location_id, name) WHERE deleted_at IS NULL;
+
+-- Shifts reference a template
+ALTER TABLE shifts ADD COLUMN template_id BIGINT REFERENCES shift_templates(id);
+
+CREATE INDEX idx_shifts_template_id ON shifts(template_id) WHERE deleted_at IS NULL;
+
+CREATE TRIGGER update_shift_templates_updated_at
+    BEFORE UPDATE ON shift_templates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_doctor_preferences_updated_at ON doctor_preferences;
+DROP TABLE IF EXISTS doctor_preferences;
@@ -0,0 +1,20 @@
+-- Create doctor_preferences table
+CREATE TABLE doctor_preferences (
+    id BIGSERIAL PRIMARY KEY,
+    doctor_id BIGINT NOT NULL REFERENCES doctors(id),
+    year INTEGER NOT NULL,
+    month INTEGER NOT NULL CHECK (month BETWEEN 1 AND 12),
+    preferred_weekdays JSONB NOT NULL DEFAULT '[]',  -- 0: Pazar
+    avoid_dates JSONB NOT NULL DEFAULT '[]',
+    preferred_templates JSONB NOT NULL DEFAULT '[]',
+    weight NUMERIC(4, 2) NOT NULL DEFAULT 1 CHECK (weight > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (doctor_id, year, month)
+);
+
+CREATE TRIGGER update_doctor_preferences_updated_at
+    BEFORE UPDATE ON doctor_preferences
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_shift_swap_requests_acceptor;
+DROP INDEX IF EXISTS idx_shift_swap_requests_requester;
+
+ALTER TABLE shift_swap_requests
+    DROP CONSTRAINT IF EXISTS shift_swap_requests_status_check,
+    DROP COLUMN IF EXISTS resolved_at,
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS reviewed_by;
+
+ALTER TABLE shift_locations DROP COLUMN IF EXISTS swap_requires_approval;
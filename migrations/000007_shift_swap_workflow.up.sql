@@ -0,0 +1,13 @@
+-- Swap approval setting per location
+ALTER TABLE shift_locations ADD COLUMN swap_requires_approval BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Swap review fields and status state machine
+ALTER TABLE shift_swap_requests
+    ADD COLUMN reviewed_by BIGINT REFERENCES users(id),
+    ADD COLUMN reviewed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN resolved_at TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT shift_swap_requests_status_check
+        CHECK (status IN ('pending', 'accepted', 'rejected', 'approved', 'cancelled', 'expired'));
+
+CREATE INDEX idx_shift_swap_requests_requester ON shift_swap_requests(requester_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_shift_swap_requests_acceptor ON shift_swap_requests(acceptor_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS update_shift_offers_updated_at ON shift_offers;
+DROP TABLE IF EXISTS shift_offers;
+
+ALTER TABLE shift_locations DROP COLUMN IF EXISTS offer_requires_approval;
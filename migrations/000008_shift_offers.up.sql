@@ -0,0 +1,32 @@
+-- Offer approval setting per location
+ALTER TABLE shift_locations ADD COLUMN offer_requires_approval BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Create shift_offers table
+CREATE TABLE shift_offers (
+    id BIGSERIAL PRIMARY KEY,
+    shift_id BIGINT NOT NULL REFERENCES shifts(id),
+    location_id BIGINT NOT NULL REFERENCES shift_locations(id),
+    shift_date DATE NOT NULL,
+    offered_by BIGINT NOT NULL REFERENCES doctors(id),
+    claimed_by BIGINT REFERENCES doctors(id),
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    comment TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'claimed', 'transferred', 'cancelled', 'expired')),
+    reviewed_by BIGINT REFERENCES users(id),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Bir nöbet için aynı anda tek açık ilan olabilir
+CREATE UNIQUE INDEX idx_shift_offers_active_shift ON shift_offers(shift_id)
+    WHERE status IN ('open', 'claimed') AND deleted_at IS NULL;
+CREATE INDEX idx_shift_offers_location_status ON shift_offers(location_id, status) WHERE deleted_at IS NULL;
+
+CREATE TRIGGER update_shift_offers_updated_at
+    BEFORE UPDATE ON shift_offers
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_notifications_doctor_created;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS read_at,
+    DROP COLUMN IF EXISTS data,
+    DROP COLUMN IF EXISTS title,
+    DROP COLUMN IF EXISTS event;
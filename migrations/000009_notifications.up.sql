@@ -0,0 +1,8 @@
+-- Notification event details
+ALTER TABLE notifications
+    ADD COLUMN event VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN title TEXT NOT NULL DEFAULT '',
+    ADD COLUMN data JSONB,
+    ADD COLUMN read_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_notifications_doctor_created ON notifications(doctor_id, created_at DESC) WHERE deleted_at IS NULL;
@@ -0,0 +1,6 @@
+ALTER TABLE holidays
+    DROP COLUMN IF EXISTS leave_type,
+    DROP COLUMN IF EXISTS leave_request_id;
+
+DROP TRIGGER IF EXISTS update_leave_requests_updated_at ON leave_requests;
+DROP TABLE IF EXISTS leave_requests;
@@ -0,0 +1,34 @@
+-- Create leave_requests table
+CREATE TABLE leave_requests (
+    id BIGSERIAL PRIMARY KEY,
+    doctor_id BIGINT NOT NULL REFERENCES doctors(id),
+    location_id BIGINT NOT NULL REFERENCES shift_locations(id),
+    leave_type VARCHAR(20) NOT NULL
+        CHECK (leave_type IN ('annual', 'sick', 'conference', 'compensatory')),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')),
+    reviewed_by BIGINT REFERENCES users(id),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    review_comment TEXT,
+    conflicting_shift_ids JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_leave_requests_doctor ON leave_requests(doctor_id, start_date) WHERE deleted_at IS NULL;
+CREATE INDEX idx_leave_requests_location_status ON leave_requests(location_id, status) WHERE deleted_at IS NULL;
+
+CREATE TRIGGER update_leave_requests_updated_at
+    BEFORE UPDATE ON leave_requests
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Holidays created from leave requests
+ALTER TABLE holidays
+    ADD COLUMN leave_request_id BIGINT REFERENCES leave_requests(id),
+    ADD COLUMN leave_type VARCHAR(20);
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_leave_entitlements_updated_at ON leave_entitlements;
+DROP TABLE IF EXISTS leave_entitlements;
@@ -0,0 +1,21 @@
+-- Create leave_entitlements table
+CREATE TABLE leave_entitlements (
+    id BIGSERIAL PRIMARY KEY,
+    doctor_id BIGINT NOT NULL REFERENCES doctors(id),
+    leave_type VARCHAR(20) NOT NULL
+        CHECK (leave_type IN ('annual', 'sick', 'conference', 'compensatory')),
+    annual_days NUMERIC(5, 2) NOT NULL CHECK (annual_days >= 0),
+    accrual VARCHAR(20) NOT NULL DEFAULT 'upfront'
+        CHECK (accrual IN ('upfront', 'monthly')),
+    carry_over_max NUMERIC(5, 2) NOT NULL DEFAULT 0 CHECK (carry_over_max >= 0),
+    start_year INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (doctor_id, leave_type)
+);
+
+CREATE TRIGGER update_leave_entitlements_updated_at
+    BEFORE UPDATE ON leave_entitlements
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
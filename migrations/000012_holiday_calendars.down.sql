@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS update_location_holiday_calendars_updated_at ON location_holiday_calendars;
+DROP TRIGGER IF EXISTS update_public_holidays_updated_at ON public_holidays;
+DROP TRIGGER IF EXISTS update_holiday_calendars_updated_at ON holiday_calendars;
+DROP TABLE IF EXISTS location_holiday_calendars;
+DROP TABLE IF EXISTS public_holidays;
+DROP TABLE IF EXISTS holiday_calendars;
@@ -0,0 +1,75 @@
+-- Create holiday_calendars table
+CREATE TABLE holiday_calendars (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create public_holidays table
+CREATE TABLE public_holidays (
+    id BIGSERIAL PRIMARY KEY,
+    calendar_id BIGINT NOT NULL REFERENCES holiday_calendars(id) ON DELETE CASCADE,
+    uid VARCHAR(255),
+    name VARCHAR(255) NOT NULL,
+    holiday_date DATE NOT NULL,
+    recurring BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (calendar_id, holiday_date)
+);
+
+-- Create location_holiday_calendars table
+CREATE TABLE location_holiday_calendars (
+    id BIGSERIAL PRIMARY KEY,
+    location_id BIGINT NOT NULL REFERENCES shift_locations(id),
+    calendar_id BIGINT NOT NULL REFERENCES holiday_calendars(id) ON DELETE CASCADE,
+    doctors_required INTEGER CHECK (doctors_required >= 0),
+    weight NUMERIC(4, 2) NOT NULL DEFAULT 0 CHECK (weight >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (location_id, calendar_id)
+);
+
+CREATE TRIGGER update_holiday_calendars_updated_at
+    BEFORE UPDATE ON holiday_calendars
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_public_holidays_updated_at
+    BEFORE UPDATE ON public_holidays
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_location_holiday_calendars_updated_at
+    BEFORE UPDATE ON location_holiday_calendars
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Tarihi sabit resmi tatiller, dini bayramlar ICS ile yıllık içe aktarılır
+INSERT INTO holiday_calendars (name, description)
+VALUES ('Türkiye Resmi Tatilleri', 'Tarihi sabit ulusal bayramlar');
+
+INSERT INTO public_holidays (calendar_id, name, holiday_date, recurring)
+SELECT c.id, h.name, h.holiday_date, true
+FROM holiday_calendars c,
+    (VALUES
+        ('Yılbaşı', DATE '2000-01-01'),
+        ('Ulusal Egemenlik ve Çocuk Bayramı', DATE '2000-04-23'),
+        ('Emek ve Dayanışma Günü', DATE '2000-05-01'),
+        ('Atatürk''ü Anma, Gençlik ve Spor Bayramı', DATE '2000-05-19'),
+        ('Demokrasi ve Milli Birlik Günü', DATE '2000-07-15'),
+        ('Zafer Bayramı', DATE '2000-08-30'),
+        ('Cumhuriyet Bayramı', DATE '2000-10-29')
+    ) AS h(name, holiday_date)
+WHERE c.name = 'Türkiye Resmi Tatilleri';
+
+-- Mevcut lokasyonlar önceki davranışı korumak için bu takvime bağlanır
+INSERT INTO location_holiday_calendars (location_id, calendar_id)
+SELECT l.id, c.id
+FROM shift_locations l, holiday_calendars c
+WHERE c.name = 'Türkiye Resmi Tatilleri';
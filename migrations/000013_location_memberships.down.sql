@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_doctor_shift_locations_location;
+
+ALTER TABLE doctor_shift_locations
+    DROP CONSTRAINT IF EXISTS chk_doctor_shift_locations_effective,
+    DROP COLUMN IF EXISTS effective_to,
+    DROP COLUMN IF EXISTS effective_from;
+
+ALTER TABLE shift_locations
+    DROP COLUMN IF EXISTS archived_at;
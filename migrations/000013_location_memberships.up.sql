@@ -0,0 +1,12 @@
+-- Archived locations are hidden and cannot be scheduled
+ALTER TABLE shift_locations
+    ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE;
+
+-- Membership validity, NULL bounds are open
+ALTER TABLE doctor_shift_locations
+    ADD COLUMN effective_from DATE,
+    ADD COLUMN effective_to DATE,
+    ADD CONSTRAINT chk_doctor_shift_locations_effective
+        CHECK (effective_to IS NULL OR effective_from IS NULL OR effective_to >= effective_from);
+
+CREATE INDEX idx_doctor_shift_locations_location ON doctor_shift_locations(location_id, doctor_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,72 @@
+ALTER TABLE shift_locations
+    ADD COLUMN swap_requires_approval BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN offer_requires_approval BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE location_rest_rules (
+    id BIGSERIAL PRIMARY KEY,
+    location_id BIGINT NOT NULL UNIQUE REFERENCES shift_locations(id),
+    min_rest_hours INTEGER NOT NULL DEFAULT 0 CHECK (min_rest_hours >= 0),
+    max_consecutive_days INTEGER NOT NULL DEFAULT 0 CHECK (max_consecutive_days >= 0),
+    max_shifts_per_week INTEGER NOT NULL DEFAULT 0 CHECK (max_shifts_per_week >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TRIGGER update_location_rest_rules_updated_at
+    BEFORE UPDATE ON location_rest_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE coverage_requirements (
+    id BIGSERIAL PRIMARY KEY,
+    location_id BIGINT NOT NULL REFERENCES shift_locations(id),
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6), -- 0: Pazar
+    doctors_required INTEGER NOT NULL CHECK (doctors_required >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (location_id, weekday)
+);
+
+CREATE TRIGGER update_coverage_requirements_updated_at
+    BEFORE UPDATE ON coverage_requirements
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Restore the legacy rules from the latest policy version
+WITH latest AS (
+    SELECT DISTINCT ON (location_id) location_id, document
+    FROM location_policies
+    ORDER BY location_id, version DESC
+)
+UPDATE shift_locations l
+SET swap_requires_approval = COALESCE((latest.document->>'swap_requires_approval')::boolean, FALSE),
+    offer_requires_approval = COALESCE((latest.document->>'offer_requires_approval')::boolean, FALSE)
+FROM latest
+WHERE latest.location_id = l.id;
+
+INSERT INTO location_rest_rules (location_id, min_rest_hours, max_consecutive_days, max_shifts_per_week)
+SELECT DISTINCT ON (location_id)
+    location_id,
+    COALESCE((document->'rest_rules'->>'min_rest_hours')::int, 0),
+    COALESCE((document->'rest_rules'->>'max_consecutive_days')::int, 0),
+    COALESCE((document->'rest_rules'->>'max_shifts_per_week')::int, 0)
+FROM location_policies
+ORDER BY location_id, version DESC;
+
+INSERT INTO coverage_requirements (location_id, weekday, doctors_required)
+SELECT latest.location_id, (w->>'weekday')::smallint, (w->>'doctors_required')::int
+FROM (
+    SELECT DISTINCT ON (location_id) location_id, document
+    FROM location_policies
+    ORDER BY location_id, version DESC
+) latest
+CROSS JOIN LATERAL jsonb_array_elements(
+    CASE WHEN jsonb_typeof(latest.document->'coverage'->'weekdays') = 'array'
+        THEN latest.document->'coverage'->'weekdays'
+        ELSE '[]'::jsonb
+    END
+) w;
+
+DROP TABLE IF EXISTS location_policies;
@@ -0,0 +1,49 @@
+-- Versioned per-location scheduling policy, rows are never updated
+CREATE TABLE location_policies (
+    id BIGSERIAL PRIMARY KEY,
+    location_id BIGINT NOT NULL REFERENCES shift_locations(id),
+    version INTEGER NOT NULL CHECK (version > 0),
+    document JSONB NOT NULL,
+    created_by BIGINT REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (location_id, version)
+);
+
+-- Seed version 1 from the rules that used to live in separate tables and columns
+INSERT INTO location_policies (location_id, version, document)
+SELECT
+    l.id,
+    1,
+    jsonb_build_object(
+        'time_zone', 'Europe/Istanbul',
+        'default_shift', jsonb_build_object('start', '08:00', 'hours', 24),
+        'coverage', jsonb_build_object(
+            'default', 1,
+            'weekdays', COALESCE((
+                SELECT jsonb_agg(jsonb_build_object('weekday', c.weekday, 'doctors_required', c.doctors_required) ORDER BY c.weekday)
+                FROM coverage_requirements c
+                WHERE c.location_id = l.id AND c.deleted_at IS NULL
+            ), '[]'::jsonb)
+        ),
+        'rest_rules', jsonb_build_object(
+            'min_rest_hours', COALESCE(r.min_rest_hours, 0),
+            'max_consecutive_days', COALESCE(r.max_consecutive_days, 0),
+            'max_shifts_per_week', COALESCE(r.max_shifts_per_week, 0)
+        ),
+        'max_shifts_per_month', 0,
+        'weekend_days', jsonb_build_array(6, 0),
+        'swap_requires_approval', l.swap_requires_approval,
+        'offer_requires_approval', l.offer_requires_approval
+    )
+FROM shift_locations l
+LEFT JOIN location_rest_rules r ON r.location_id = l.id AND r.deleted_at IS NULL;
+
+DROP TRIGGER IF EXISTS update_coverage_requirements_updated_at ON coverage_requirements;
+DROP TABLE IF EXISTS coverage_requirements;
+
+DROP TRIGGER IF EXISTS update_location_rest_rules_updated_at ON location_rest_rules;
+DROP TABLE IF EXISTS location_rest_rules;
+
+ALTER TABLE shift_locations
+    DROP COLUMN IF EXISTS swap_requires_approval,
+    DROP COLUMN IF EXISTS offer_requires_approval;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_schedule_transitions_month;
+DROP TABLE IF EXISTS schedule_transitions;
+DROP TABLE IF EXISTS schedule_snapshots;
+
+ALTER TABLE shifts_status
+    DROP COLUMN IF EXISTS locked_at,
+    DROP COLUMN IF EXISTS published_at,
+    DROP COLUMN IF EXISTS version,
+    DROP COLUMN IF EXISTS state;
@@ -0,0 +1,79 @@
+-- Monthly schedule lifecycle: draft -> published -> locked
+ALTER TABLE shifts_status
+    ADD COLUMN state VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (state IN ('draft', 'published', 'locked')),
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN published_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN locked_at TIMESTAMP WITH TIME ZONE;
+
+-- Immutable copies of each published month
+CREATE TABLE schedule_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    location_id BIGINT NOT NULL REFERENCES shift_locations(id),
+    year INTEGER NOT NULL,
+    month INTEGER NOT NULL CHECK (month BETWEEN 1 AND 12),
+    version INTEGER NOT NULL CHECK (version > 0),
+    shifts JSONB NOT NULL DEFAULT '[]',
+    created_by BIGINT REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (location_id, year, month, version)
+);
+
+-- State transitions and overrides on locked months
+CREATE TABLE schedule_transitions (
+    id BIGSERIAL PRIMARY KEY,
+    location_id BIGINT NOT NULL REFERENCES shift_locations(id),
+    year INTEGER NOT NULL,
+    month INTEGER NOT NULL CHECK (month BETWEEN 1 AND 12),
+    action VARCHAR(20) NOT NULL,
+    from_state VARCHAR(20) NOT NULL,
+    to_state VARCHAR(20) NOT NULL,
+    version INTEGER NOT NULL DEFAULT 0,
+    source_version INTEGER,
+    reason TEXT,
+    created_by BIGINT REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_schedule_transitions_month ON schedule_transitions(location_id, year, month, created_at);
+
+-- Months that already have shifts were visible before, they start out published
+INSERT INTO shifts_status (year, month, location_id, done)
+SELECT DISTINCT EXTRACT(YEAR FROM s.shift_date)::int, EXTRACT(MONTH FROM s.shift_date)::int, s.location_id, FALSE
+FROM shifts s
+WHERE s.deleted_at IS NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM shifts_status st
+      WHERE st.location_id = s.location_id
+        AND st.year = EXTRACT(YEAR FROM s.shift_date)
+        AND st.month = EXTRACT(MONTH FROM s.shift_date)
+        AND st.deleted_at IS NULL
+  );
+
+UPDATE shifts_status st
+SET state = 'published', version = 1, published_at = CURRENT_TIMESTAMP
+WHERE st.deleted_at IS NULL
+  AND (st.done OR EXISTS (
+      SELECT 1 FROM shifts s
+      WHERE s.location_id = st.location_id
+        AND EXTRACT(YEAR FROM s.shift_date) = st.year
+        AND EXTRACT(MONTH FROM s.shift_date) = st.month
+        AND s.deleted_at IS NULL
+  ));
+
+INSERT INTO schedule_snapshots (location_id, year, month, version, shifts)
+SELECT st.location_id, st.year, st.month, 1, COALESCE((
+    SELECT jsonb_agg(jsonb_build_object(
+        'doctor_id', s.doctor_id,
+        'shift_date', to_char(s.shift_date, 'YYYY-MM-DD"T00:00:00Z"'),
+        'start_time', s.start_time,
+        'end_time', s.end_time,
+        'template_id', COALESCE(s.template_id, 0)
+    ) ORDER BY s.shift_date, s.start_time, s.doctor_id)
+    FROM shifts s
+    WHERE s.location_id = st.location_id
+      AND EXTRACT(YEAR FROM s.shift_date) = st.year
+      AND EXTRACT(MONTH FROM s.shift_date) = st.month
+      AND s.deleted_at IS NULL
+), '[]'::jsonb)
+FROM shifts_status st
+WHERE st.state = 'published' AND st.deleted_at IS NULL;
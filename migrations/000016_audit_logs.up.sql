@@ -0,0 +1,38 @@
+-- Append-only audit trail for shift, holiday and user mutations
+CREATE TABLE audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id BIGINT, -- no FK: entries must outlive the users they mention
+    action VARCHAR(30) NOT NULL,
+    entity VARCHAR(30) NOT NULL,
+    entity_id BIGINT,
+    before JSONB,
+    after JSONB,
+    ip VARCHAR(45),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_logs_entity ON audit_logs(entity, entity_id, created_at);
+CREATE INDEX idx_audit_logs_actor ON audit_logs(actor_id, created_at);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+
+-- Entries can't be changed. Deletes are only allowed for the retention purge,
+-- which sets audit.purge inside its own transaction.
+CREATE OR REPLACE FUNCTION audit_logs_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND current_setting('audit.purge', true) = 'on' THEN
+        RETURN OLD;
+    END IF;
+    RAISE EXCEPTION 'audit_logs is append-only (% not allowed)', TG_OP;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER audit_logs_append_only
+    BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW
+    EXECUTE FUNCTION audit_logs_append_only();
+
+CREATE TRIGGER audit_logs_no_truncate
+    BEFORE TRUNCATE ON audit_logs
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION audit_logs_append_only();
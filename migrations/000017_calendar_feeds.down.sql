@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS record_shift_removal ON shifts;
+DROP FUNCTION IF EXISTS record_shift_removal();
+DROP TABLE IF EXISTS shift_removals;
+DROP TABLE IF EXISTS calendar_feed_tokens;
@@ -0,0 +1,55 @@
+-- Read-only ICS subscription tokens. Only the SHA-256 of the token is stored.
+CREATE TABLE calendar_feed_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('doctor', 'location')),
+    doctor_id BIGINT REFERENCES doctors(id),
+    location_id BIGINT REFERENCES shift_locations(id),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(100),
+    created_by BIGINT REFERENCES users(id),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((kind = 'doctor' AND doctor_id IS NOT NULL) OR (kind = 'location' AND location_id IS NOT NULL))
+);
+
+CREATE INDEX idx_calendar_feed_tokens_doctor ON calendar_feed_tokens(doctor_id);
+CREATE INDEX idx_calendar_feed_tokens_location ON calendar_feed_tokens(location_id);
+
+-- Shifts that left a feed: deleted, or moved to another doctor or location.
+-- Feeds publish them as cancelled events so subscribed calendars drop them.
+CREATE TABLE shift_removals (
+    id BIGSERIAL PRIMARY KEY,
+    shift_id BIGINT NOT NULL,
+    doctor_id BIGINT NOT NULL,
+    location_id BIGINT NOT NULL,
+    shift_date DATE NOT NULL,
+    start_time VARCHAR(5) NOT NULL,
+    end_time VARCHAR(5) NOT NULL,
+    removed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_shift_removals_doctor ON shift_removals(doctor_id, shift_date);
+CREATE INDEX idx_shift_removals_location ON shift_removals(location_id, shift_date);
+
+CREATE OR REPLACE FUNCTION record_shift_removal()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.deleted_at IS NOT NULL THEN
+        RETURN NULL;
+    END IF;
+    IF TG_OP = 'DELETE'
+        OR NEW.deleted_at IS NOT NULL
+        OR NEW.doctor_id <> OLD.doctor_id
+        OR NEW.location_id <> OLD.location_id THEN
+        INSERT INTO shift_removals (shift_id, doctor_id, location_id, shift_date, start_time, end_time)
+        VALUES (OLD.id, OLD.doctor_id, OLD.location_id, OLD.shift_date, OLD.start_time, OLD.end_time);
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_shift_removal
+    AFTER UPDATE OR DELETE ON shifts
+    FOR EACH ROW
+    EXECUTE FUNCTION record_shift_removal();
@@ -0,0 +1,2 @@
+ALTER TABLE schedule_drafts
+    DROP COLUMN IF EXISTS required;
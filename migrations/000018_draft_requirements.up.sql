@@ -0,0 +1,4 @@
+-- Per-day shift requirement at the time a draft was created (date -> count).
+-- Committing a draft only fails on days whose requirement rose afterwards.
+ALTER TABLE schedule_drafts
+    ADD COLUMN required JSONB NOT NULL DEFAULT '{}';
@@ -85,6 +85,39 @@ func (c *RedisCache) Expire(ctx context.Context, key string, expiration time.Dur
 	return c.client.Expire(ctx, key, expiration).Err()
 }
 
+// Veriyi JSON olarak kanala yayınlar
+func (c *RedisCache) Publish(ctx context.Context, channel string, value interface{}) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.client.Publish(ctx, channel, payload).Err()
+}
+
+// Kanala abone olur ve gelen her mesaj için handler'ı çağırır. ctx iptal edilene kadar bloklar.
+func (c *RedisCache) Subscribe(ctx context.Context, channel string, handler func(payload []byte)) error {
+	pubsub := c.client.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	// Aboneliğin kurulduğunu doğrula
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			handler([]byte(msg.Payload))
+		}
+	}
+}
+
 // Global fonksiyonlar
 func Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	if defaultCache == nil {
@@ -127,3 +160,17 @@ func Expire(ctx context.Context, key string, expiration time.Duration) error {
 	}
 	return defaultCache.Expire(ctx, key, expiration)
 }
+
+func Publish(ctx context.Context, channel string, value interface{}) error {
+	if defaultCache == nil {
+		return errorx.ErrCacheNotInitialized
+	}
+	return defaultCache.Publish(ctx, channel, value)
+}
+
+func Subscribe(ctx context.Context, channel string, handler func(payload []byte)) error {
+	if defaultCache == nil {
+		return errorx.ErrCacheNotInitialized
+	}
+	return defaultCache.Subscribe(ctx, channel, handler)
+}
@@ -17,8 +17,16 @@ const (
 
 // Error yapısı
 type Error struct {
+	Message string   `json:"message"`
+	Code    int      `json:"code"`
+	Details []Detail `json:"details,omitempty"`
+}
+
+// Hatanın alan bazlı detayı (ör. atanamayan bir gün)
+type Detail struct {
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code"`
 	Message string `json:"message"`
-	Code    int    `json:"code"`
 }
 
 // Error interface'ini implement et
@@ -99,13 +107,47 @@ var (
 		Code:    StatusUnprocessableEntity,
 		Message: "Invalid value type",
 	}
+	ErrRuleViolation = &Error{
+		Code:    StatusConflict,
+		Message: "Shift violates scheduling rules",
+	}
+	ErrDeadlinePassed = &Error{
+		Code:    StatusForbidden,
+		Message: "Deadline has passed",
+	}
+	ErrInvalidTransition = &Error{
+		Code:    StatusConflict,
+		Message: "Invalid status transition",
+	}
+	ErrStaleDraft = &Error{
+		Code:    StatusConflict,
+		Message: "Draft conflicts with current schedule",
+	}
+	ErrInsufficientBalance = &Error{
+		Code:    StatusUnprocessableEntity,
+		Message: "Leave balance exceeded",
+	}
+	ErrScheduleLocked = &Error{
+		Code:    StatusConflict,
+		Message: "Schedule is locked",
+	}
 )
 
-// Hata detayı eklemek için yardımcı fonksiyon
+// Hata detayı eklemek için yardımcı fonksiyon. Hatanın detay listesi korunur.
 func WithDetails(err *Error, details string) *Error {
 	return &Error{
 		Code:    err.Code,
 		Message: fmt.Sprintf("%s - %s", err.Message, details),
+		Details: append([]Detail(nil), err.Details...),
+	}
+}
+
+// Hataya yapılandırılmış detaylar eklemek için yardımcı fonksiyon
+func WithDetailList(err *Error, details ...Detail) *Error {
+	return &Error{
+		Code:    err.Code,
+		Message: err.Message,
+		Details: append(append([]Detail(nil), err.Details...), details...),
 	}
 }
 
@@ -0,0 +1,27 @@
+package errorx_test
+
+import (
+	"shift-scheduling-v2/pkg/errorx"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDetailsKeepsDetailList(t *testing.T) {
+	base := errorx.WithDetailList(errorx.ErrValidation, errorx.Detail{Field: "days[3]", Code: "unassigned", Message: "Atanamadı"})
+	err := errorx.WithDetails(base, "Bazı günler atanamadı")
+
+	assert.Equal(t, base.Code, err.Code)
+	assert.Contains(t, err.Message, "Bazı günler atanamadı")
+	require.Len(t, err.Details, 1)
+	assert.Equal(t, "days[3]", err.Details[0].Field)
+
+	// Sarılan hatanın listesi paylaşılmaz
+	err.Details[0].Field = "degisti"
+	assert.Equal(t, "days[3]", base.Details[0].Field)
+}
+
+func TestWithDetailsWithoutDetailList(t *testing.T) {
+	assert.Nil(t, errorx.WithDetails(errorx.ErrNotFound, "Lokasyon bulunamadı").Details)
+}
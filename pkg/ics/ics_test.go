@@ -0,0 +1,122 @@
+package ics_test
+
+import (
+	"bytes"
+	"shift-scheduling-v2/pkg/ics"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const holidayICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:ramazan-2025@example.com\r\n" +
+	"SUMMARY:Ramazan Bayramı\r\n" +
+	"DTSTART;VALUE=DATE:20250330\r\n" +
+	"DTEND;VALUE=DATE:20250402\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:cumhuriyet@example.com\r\n" +
+	"SUMMARY:Cumhuriyet Bay\r\n" +
+	" ramı\r\n" +
+	"DTSTART:20251029T000000Z\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestICSParseHolidays(t *testing.T) {
+	events, err := ics.Parse(strings.NewReader(holidayICS))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	bayram := events[0]
+	assert.Equal(t, "ramazan-2025@example.com", bayram.UID)
+	assert.Equal(t, "Ramazan Bayramı", bayram.Summary)
+	assert.False(t, bayram.Yearly)
+	assert.Equal(t, []time.Time{
+		time.Date(2025, time.March, 30, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+	}, bayram.Days())
+
+	// Katlanmış satır birleştirilir, DTEND yoksa tek gün
+	republic := events[1]
+	assert.Equal(t, "Cumhuriyet Bayramı", republic.Summary)
+	assert.True(t, republic.Yearly)
+	assert.Len(t, republic.Days(), 1)
+}
+
+func TestICSParseRejectsEventWithoutStart(t *testing.T) {
+	_, err := ics.Parse(strings.NewReader("BEGIN:VEVENT\nSUMMARY:Eksik\nEND:VEVENT\n"))
+	assert.Error(t, err)
+}
+
+func TestWriteFeedUsesUTCTimesAndStableUIDs(t *testing.T) {
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	if err != nil {
+		t.Skip("saat dilimi verisi bulunamadı")
+	}
+
+	cal := ics.Calendar{
+		Name:     "Nöbet Listesi - Acil, Merkez",
+		TimeZone: "Europe/Istanbul",
+		Events: []ics.FeedEvent{
+			{
+				UID:      "shift-7@shift-scheduling",
+				Summary:  "Dr. Ayşe Yılmaz (Gece)",
+				Location: "Acil",
+				Start:    time.Date(2025, 3, 3, 16, 0, 0, 0, istanbul),
+				End:      time.Date(2025, 3, 4, 8, 0, 0, 0, istanbul),
+				Modified: time.Date(2025, 2, 20, 10, 0, 0, 0, time.UTC),
+			},
+			{
+				UID:       "shift-8@shift-scheduling",
+				Summary:   "İptal: Nöbet - Acil",
+				Start:     time.Date(2025, 3, 5, 8, 0, 0, 0, istanbul),
+				End:       time.Date(2025, 3, 6, 8, 0, 0, 0, istanbul),
+				Cancelled: true,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, ics.Write(&buf, cal, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)))
+	out := buf.String()
+
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Contains(t, out, "X-WR-CALNAME:Nöbet Listesi - Acil\\, Merkez\r\n")
+	assert.Contains(t, out, "DTSTART:20250303T130000Z\r\n")
+	assert.Contains(t, out, "DTEND:20250304T050000Z\r\n")
+	assert.Contains(t, out, "UID:shift-7@shift-scheduling\r\n")
+	assert.Contains(t, out, "STATUS:CANCELLED\r\n")
+
+	events, err := ics.Parse(strings.NewReader(out))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "shift-8@shift-scheduling", events[1].UID)
+	assert.Equal(t, "İptal: Nöbet - Acil", events[1].Summary)
+}
+
+func TestWriteFeedFoldsLongLines(t *testing.T) {
+	cal := ics.Calendar{Events: []ics.FeedEvent{{
+		UID:         "shift-1@shift-scheduling",
+		Summary:     "Nöbet",
+		Description: strings.Repeat("çok uzun açıklama ", 10),
+		Start:       time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC),
+		End:         time.Date(2025, 3, 3, 16, 0, 0, 0, time.UTC),
+	}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, ics.Write(&buf, cal, time.Now()))
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+		assert.True(t, utf8.ValidString(line), line)
+	}
+	assert.Contains(t, strings.ReplaceAll(buf.String(), "\r\n ", ""), "DESCRIPTION:"+strings.Repeat("çok uzun açıklama ", 10))
+}
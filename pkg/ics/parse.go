@@ -0,0 +1,135 @@
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ICS dosyasındaki tek bir etkinlik. Tarihler UTC gün başlangıcına yuvarlanır.
+type Event struct {
+	UID     string
+	Summary string
+	Start   time.Time
+	End     time.Time // dahil değil, boşsa tek günlük etkinlik
+	Yearly  bool      // RRULE:FREQ=YEARLY ile her yıl tekrar eder
+}
+
+// Etkinliğin kapsadığı günler
+func (e Event) Days() []time.Time {
+	end := e.End
+	if !end.After(e.Start) {
+		end = e.Start.AddDate(0, 0, 1)
+	}
+
+	var days []time.Time
+	for day := e.Start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
+
+// Takvimdeki VEVENT kayıtlarını okur. Yalnızca tatil takvimleri için gereken alanlar desteklenir.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events  []Event
+		current *Event
+	)
+	for i, line := range lines {
+		name, params, value := splitLine(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current = &Event{}
+		case name == "END" && value == "VEVENT":
+			if current == nil || current.Start.IsZero() {
+				return nil, fmt.Errorf("line %d: event without DTSTART", i+1)
+			}
+			events = append(events, *current)
+			current = nil
+		case current == nil:
+			continue
+		case name == "UID":
+			current.UID = value
+		case name == "SUMMARY":
+			current.Summary = unescape(value)
+		case name == "DTSTART", name == "DTEND":
+			day, err := parseDate(params, value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if name == "DTSTART" {
+				current.Start = day
+			} else {
+				current.End = day
+			}
+		case name == "RRULE":
+			current.Yearly = strings.Contains(strings.ToUpper(value), "FREQ=YEARLY")
+		}
+	}
+
+	return events, nil
+}
+
+// Katlanmış (boşluk ya da tab ile devam eden) satırları birleştirir
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// "DTSTART;VALUE=DATE:20250101" satırını ad, parametre ve değere ayırır
+func splitLine(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+
+	params := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = v
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+func parseDate(params map[string]string, value string) (time.Time, error) {
+	layouts := []string{"20060102T150405Z", "20060102T150405", "20060102"}
+	if params["VALUE"] == "DATE" {
+		layouts = []string{"20060102"}
+	}
+
+	loc := time.UTC
+	if tz := params["TZID"]; tz != "" {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+func unescape(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
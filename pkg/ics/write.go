@@ -0,0 +1,108 @@
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Abonelik takviminde yayınlanan zamanlı etkinlik. UID aynı kaldıkça istemciler
+// etkinliği günceller; Cancelled ise etkinlik takvimden kaldırılır.
+type FeedEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	Modified    time.Time // son değişiklik zamanı, SEQUENCE ve LAST-MODIFIED için
+	Cancelled   bool
+}
+
+// Abonelik takvimi. TimeZone istemciye gösterim için önerilen saat dilimidir,
+// etkinlik saatleri her zaman UTC olarak yazılır.
+type Calendar struct {
+	Name     string
+	TimeZone string
+	Events   []FeedEvent
+}
+
+const (
+	productID   = "-//shift-scheduling//Nöbet Takvimi//TR"
+	utcLayout   = "20060102T150405Z"
+	maxLineSize = 75
+)
+
+// Takvimi RFC 5545 biçiminde yazar. Satırlar CRLF ile biter ve 75 bayttan uzunsa katlanır.
+func Write(w io.Writer, cal Calendar, now time.Time) error {
+	out := bufio.NewWriter(w)
+	line := func(name string, value string) {
+		writeFolded(out, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", productID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", escape(cal.Name))
+	}
+	if cal.TimeZone != "" {
+		line("X-WR-TIMEZONE", cal.TimeZone)
+	}
+
+	stamp := now.UTC().Format(utcLayout)
+	for _, e := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", stamp)
+		line("DTSTART", e.Start.UTC().Format(utcLayout))
+		line("DTEND", e.End.UTC().Format(utcLayout))
+		if !e.Modified.IsZero() {
+			line("LAST-MODIFIED", e.Modified.UTC().Format(utcLayout))
+			line("SEQUENCE", fmt.Sprint(e.Modified.Unix()))
+		}
+		line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escape(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION", escape(e.Location))
+		}
+		if e.Cancelled {
+			line("STATUS", "CANCELLED")
+		} else {
+			line("STATUS", "CONFIRMED")
+		}
+		line("TRANSP", "OPAQUE")
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return out.Flush()
+}
+
+// Uzun satırları, çok baytlı karakterleri bölmeden boşlukla devam eden satırlara katlar
+func writeFolded(w *bufio.Writer, line string) {
+	limit := maxLineSize
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineSize - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Bildirimin alıcısı
+type Recipient struct {
+	DoctorID int64  `json:"doctor_id"`
+	UserID   int64  `json:"user_id"`
+	Name     string `json:"name"`
+	Email    string `json:"email,omitempty"`
+}
+
+// Kanallardan bağımsız bildirim içeriği
+type Message struct {
+	To        Recipient      `json:"recipient"`
+	Event     string         `json:"event"`
+	Title     string         `json:"title"`
+	Body      string         `json:"body"`
+	Data      map[string]any `json:"data,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// Bildirimi ileten kanal (uygulama içi, e-posta, webhook...)
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// Tekrar denemenin anlamsız olduğu hatalar (ör. webhook 4xx cevabı)
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
+
+// Bildirimleri tüm kanallara iletir, başarısız gönderimleri artan beklemeyle tekrar dener
+type Dispatcher struct {
+	channels []Channel
+	attempts int
+	backoff  time.Duration
+}
+
+// attempts bir kanal için toplam deneme sayısıdır, backoff her denemede ikiye katlanır
+func NewDispatcher(attempts int, backoff time.Duration, channels ...Channel) *Dispatcher {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &Dispatcher{channels: channels, attempts: attempts, backoff: backoff}
+}
+
+func (d *Dispatcher) Channels() []string {
+	names := make([]string, len(d.channels))
+	for i, ch := range d.channels {
+		names[i] = ch.Name()
+	}
+	return names
+}
+
+// Mesajı tüm kanallara gönderir. Bir kanalın hatası diğerlerini engellemez, hatalar birleştirilerek döner.
+func (d *Dispatcher) Dispatch(ctx context.Context, msg Message) error {
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now()
+	}
+
+	var errs []error
+	for _, ch := range d.channels {
+		if err := d.send(ctx, ch, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (d *Dispatcher) send(ctx context.Context, ch Channel, msg Message) error {
+	wait := d.backoff
+
+	var err error
+	for attempt := 1; attempt <= d.attempts; attempt++ {
+		if err = ch.Send(ctx, msg); err == nil || IsPermanent(err) {
+			return err
+		}
+		if attempt == d.attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+	return fmt.Errorf("%d denemede gönderilemedi: %w", d.attempts, err)
+}
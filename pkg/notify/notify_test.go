@@ -0,0 +1,191 @@
+package notify_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"shift-scheduling-v2/pkg/notify"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMessage() notify.Message {
+	return notify.Message{
+		To:        notify.Recipient{DoctorID: 7, UserID: 3, Name: "Ayşe Yılmaz", Email: "ayse@example.com"},
+		Event:     "shift_changed",
+		Title:     "Nöbet değişikliği",
+		Body:      "12.05.2025 tarihli nöbetiniz güncellendi.",
+		Data:      map[string]any{"shift_id": 42},
+		CreatedAt: time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+// Tek bağlantı kabul eden, gelen mesajı kaydeden basit SMTP sunucusu
+func startSMTPServer(t *testing.T) (string, int, <-chan string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		w := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+		w("220 localhost ESMTP")
+		var data strings.Builder
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				w("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"):
+				w("250 OK")
+			case cmd == "DATA":
+				w("354 End data with <CR><LF>.<CR><LF>")
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				received <- data.String()
+				w("250 OK")
+			case cmd == "QUIT":
+				w("221 Bye")
+				return
+			default:
+				w("250 OK")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return host, p, received
+}
+
+func TestSMTPChannelDeliversToLocalServer(t *testing.T) {
+	host, port, received := startSMTPServer(t)
+
+	ch := notify.NewSMTPChannel(host, port, "", "", "nobet@example.com")
+	require.NoError(t, ch.Send(context.Background(), testMessage()))
+
+	select {
+	case mail := <-received:
+		assert.Contains(t, mail, "To: ayse@example.com")
+		assert.Contains(t, mail, "Subject: =?UTF-8?b?")
+		assert.Contains(t, mail, "12.05.2025 tarihli nöbetiniz güncellendi.")
+	case <-time.After(5 * time.Second):
+		t.Fatal("SMTP sunucusuna mesaj ulaşmadı")
+	}
+}
+
+func TestSMTPChannelSkipsRecipientWithoutEmail(t *testing.T) {
+	msg := testMessage()
+	msg.To.Email = ""
+
+	// Sunucu olmasa da e-postası olmayan alıcı için hata dönmemeli
+	ch := notify.NewSMTPChannel("127.0.0.1", 1, "", "", "nobet@example.com")
+	assert.NoError(t, ch.Send(context.Background(), msg))
+}
+
+func TestWebhookChannelRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	var body notify.Message
+	var signature string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		signature = r.Header.Get(notify.SignatureHeader)
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	d := notify.NewDispatcher(3, time.Millisecond, notify.NewWebhookChannel(srv.URL, "secret", time.Second))
+	require.NoError(t, d.Dispatch(context.Background(), testMessage()))
+
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, "shift_changed", body.Event)
+	assert.Equal(t, int64(7), body.To.DoctorID)
+	assert.True(t, strings.HasPrefix(signature, "sha256="))
+}
+
+func TestWebhookChannelDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	d := notify.NewDispatcher(5, time.Millisecond, notify.NewWebhookChannel(srv.URL, "", time.Second))
+	err := d.Dispatch(context.Background(), testMessage())
+
+	assert.Error(t, err)
+	assert.True(t, notify.IsPermanent(err))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+type failingChannel struct {
+	calls int
+}
+
+func (c *failingChannel) Name() string { return "failing" }
+func (c *failingChannel) Send(context.Context, notify.Message) error {
+	c.calls++
+	return errors.New("unavailable")
+}
+
+type recordingChannel struct {
+	messages []notify.Message
+}
+
+func (c *recordingChannel) Name() string { return "recording" }
+func (c *recordingChannel) Send(_ context.Context, msg notify.Message) error {
+	c.messages = append(c.messages, msg)
+	return nil
+}
+
+func TestDispatcherContinuesAfterChannelFailure(t *testing.T) {
+	failing := &failingChannel{}
+	recording := &recordingChannel{}
+
+	d := notify.NewDispatcher(2, time.Millisecond, failing, recording)
+	err := d.Dispatch(context.Background(), testMessage())
+
+	assert.ErrorContains(t, err, "failing")
+	assert.Equal(t, 2, failing.calls)
+	assert.Len(t, recording.messages, 1)
+	assert.Equal(t, []string{"failing", "recording"}, d.Channels())
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTP üzerinden e-posta gönderen kanal. Username boşsa kimlik doğrulama yapılmaz.
+type SMTPChannel struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+func NewSMTPChannel(host string, port int, username, password, from string) *SMTPChannel {
+	return &SMTPChannel{Host: host, Port: port, Username: username, Password: password, From: from}
+}
+
+func (c *SMTPChannel) Name() string {
+	return "email"
+}
+
+// E-posta adresi olmayan alıcılar atlanır
+func (c *SMTPChannel) Send(ctx context.Context, msg Message) error {
+	if msg.To.Email == "" {
+		return nil
+	}
+
+	var auth smtp.Auth
+	if c.Username != "" {
+		auth = smtp.PlainAuth("", c.Username, c.Password, c.Host)
+	}
+
+	addr := net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+	body := c.build(msg)
+
+	// smtp.SendMail context almadığından iptal edilen istekte sonucu beklemeden döneriz
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, c.From, []string{msg.To.Email}, body)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
+
+func (c *SMTPChannel) build(msg Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", c.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To.Email)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Title))
+	fmt.Fprintf(&b, "Date: %s\r\n", msg.CreatedAt.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
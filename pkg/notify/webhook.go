@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// İmzanın taşındığı header, gövdenin HMAC-SHA256 değeridir
+const SignatureHeader = "X-Signature-256"
+
+// Bildirimi JSON olarak verilen adrese POST eden kanal
+type WebhookChannel struct {
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+func NewWebhookChannel(url, secret string, timeout time.Duration) *WebhookChannel {
+	return &WebhookChannel{URL: url, Secret: secret, Client: &http.Client{Timeout: timeout}}
+}
+
+func (c *WebhookChannel) Name() string {
+	return "webhook"
+}
+
+// 5xx ve 429 cevapları tekrar denenir, diğer 4xx cevapları kalıcı hatadır
+func (c *WebhookChannel) Send(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(payload))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(c.Secret, payload))
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	err = fmt.Errorf("webhook %d döndü", resp.StatusCode)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+	return err
+}
+
+// Gövdenin secret ile HMAC-SHA256 imzası
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
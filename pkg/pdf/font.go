@@ -0,0 +1,146 @@
+package pdf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Standart fontlar WinAnsiEncoding ile ğ, ş, ı, İ, Ğ ve Ş harflerini içermez. Bu glifler
+// Helvetica'da bulunduğundan WinAnsi'de boş ya da nadir kullanılan kodlara Differences ile atanır.
+type encoding struct {
+	differences map[rune]byte
+	glyphs      map[byte]string
+}
+
+var turkishEncoding = encoding{
+	differences: map[rune]byte{'Ğ': 0x81, 'ı': 0x83, 'ğ': 0x8D, 'Ş': 0x8F, 'ş': 0x90, 'İ': 0x9D},
+	glyphs:      map[byte]string{0x81: "Gbreve", 0x83: "dotlessi", 0x8D: "gbreve", 0x8F: "Scedilla", 0x90: "scedilla", 0x9D: "Idotaccent"},
+}
+
+// WinAnsi'de Latin-1 dışında kalan karakterler
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+func (e encoding) dictionary() string {
+	codes := make([]int, 0, len(e.glyphs))
+	for code := range e.glyphs {
+		codes = append(codes, int(code))
+	}
+	sort.Ints(codes)
+
+	var b strings.Builder
+	b.WriteString("<< /Type /Encoding /BaseEncoding /WinAnsiEncoding /Differences [")
+	for _, code := range codes {
+		fmt.Fprintf(&b, " %d /%s", code, e.glyphs[byte(code)])
+	}
+	b.WriteString(" ] >>")
+	return b.String()
+}
+
+func (e encoding) code(r rune) (byte, bool) {
+	if c, ok := e.differences[r]; ok {
+		return c, true
+	}
+	if c, ok := winAnsi[r]; ok {
+		return c, true
+	}
+	if (r >= 0x20 && r < 0x7F) || (r >= 0xA0 && r <= 0xFF) {
+		return byte(r), true
+	}
+	return 0, false
+}
+
+// Metni PDF dizgesine çevirir. Kodlamada olmayan karakterler "?" olarak yazılır.
+func (e encoding) escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		c, ok := e.code(r)
+		switch {
+		case r == '\n' || r == '\t':
+			b.WriteByte(' ')
+		case !ok:
+			b.WriteByte('?')
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c >= 0x80:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// Helvetica ve Helvetica-Bold glif genişlikleri (1000 birimlik em), 32-126 arası ASCII
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// Aksanlı harfler taban harfle aynı genişliktedir
+var baseLetters = strings.NewReplacer(
+	"À", "A", "Á", "A", "Â", "A", "Ã", "A", "Ä", "A", "Å", "A", "Ç", "C", "È", "E", "É", "E", "Ê", "E", "Ë", "E",
+	"Ì", "I", "Í", "I", "Î", "I", "Ï", "I", "İ", "I", "Ñ", "N", "Ò", "O", "Ó", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ù", "U", "Ú", "U", "Û", "U", "Ü", "U", "Ý", "Y", "Ğ", "G", "Ş", "S",
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a", "ç", "c", "è", "e", "é", "e", "ê", "e", "ë", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i", "ñ", "n", "ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u", "ý", "y", "ÿ", "y", "ğ", "g", "ş", "s",
+)
+
+// Metnin verilen punto ve kalınlıkta kapladığı genişlik (pt)
+func TextWidth(text string, size float64, bold bool) float64 {
+	widths := &helveticaWidths
+	if bold {
+		widths = &helveticaBoldWidths
+	}
+
+	total := 0
+	for _, r := range baseLetters.Replace(text) {
+		switch {
+		case r >= 32 && r <= 126:
+			total += widths[r-32]
+		case r == 'ı':
+			total += 278
+		case r == '…' || r == '—':
+			total += 1000
+		case r == '•':
+			total += 350
+		default:
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Metin sığmıyorsa sonunu "..." ile keserek maxWidth genişliğine sığdırır
+func Fit(text string, size float64, bold bool, maxWidth float64) string {
+	if TextWidth(text, size, bold) <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for n := len(runes) - 1; n > 0; n-- {
+		candidate := strings.TrimRight(string(runes[:n]), " ") + "..."
+		if TextWidth(candidate, size, bold) <= maxWidth {
+			return candidate
+		}
+	}
+	return ""
+}
@@ -0,0 +1,164 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sayfa boyutları (pt)
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// RGB renk, bileşenler 0-1 arası
+type Color struct {
+	R, G, B float64
+}
+
+var (
+	Black = Color{0, 0, 0}
+	White = Color{1, 1, 1}
+)
+
+// Gömülü font kullanmayan, standart Helvetica ailesiyle yazan basit PDF belgesi.
+// Koordinatların başlangıcı sayfanın sol üst köşesidir, y aşağı doğru artar.
+type Document struct {
+	Title    string
+	Author   string
+	Created  time.Time
+	width    float64
+	height   float64
+	pages    []*Page
+	encoding encoding
+}
+
+func New(width float64, height float64) *Document {
+	return &Document{width: width, height: height, encoding: turkishEncoding}
+}
+
+func (d *Document) Width() float64  { return d.width }
+func (d *Document) Height() float64 { return d.height }
+
+func (d *Document) AddPage() *Page {
+	p := &Page{doc: d}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+// Metni (x, y) noktasından başlayarak yazar; y metnin taban çizgisidir
+func (p *Page) Text(x float64, y float64, size float64, bold bool, color Color, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT %s rg /%s %s Tf %s %s Td (%s) Tj ET\n",
+		color.operands(), font, num(size), num(x), num(p.doc.height-y), p.doc.encoding.escape(text))
+}
+
+// Metni verilen genişlikte ortalar
+func (p *Page) TextCenter(x float64, y float64, width float64, size float64, bold bool, color Color, text string) {
+	p.Text(x+(width-TextWidth(text, size, bold))/2, y, size, bold, color, text)
+}
+
+// Metni x noktasında biten şekilde sağa yaslar
+func (p *Page) TextRight(x float64, y float64, size float64, bold bool, color Color, text string) {
+	p.Text(x-TextWidth(text, size, bold), y, size, bold, color, text)
+}
+
+func (p *Page) Line(x1 float64, y1 float64, x2 float64, y2 float64, width float64, color Color) {
+	fmt.Fprintf(&p.content, "%s RG %s w %s %s m %s %s l S\n",
+		color.operands(), num(width), num(x1), num(p.doc.height-y1), num(x2), num(p.doc.height-y2))
+}
+
+// Sol üst köşesi (x, y) olan dikdörtgen. fill nil ise içi boyanmaz, borderWidth 0 ise çerçeve çizilmez.
+func (p *Page) Rect(x float64, y float64, w float64, h float64, fill *Color, borderWidth float64, border Color) {
+	op := ""
+	switch {
+	case fill != nil && borderWidth > 0:
+		op = "B"
+	case fill != nil:
+		op = "f"
+	case borderWidth > 0:
+		op = "S"
+	default:
+		return
+	}
+
+	if fill != nil {
+		fmt.Fprintf(&p.content, "%s rg ", fill.operands())
+	}
+	if borderWidth > 0 {
+		fmt.Fprintf(&p.content, "%s RG %s w ", border.operands(), num(borderWidth))
+	}
+	fmt.Fprintf(&p.content, "%s %s %s %s re %s\n", num(x), num(p.doc.height-y-h), num(w), num(h), op)
+}
+
+// Belgeyi PDF 1.4 olarak yazar
+func (d *Document) Write(w io.Writer) error {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var (
+		buf     bytes.Buffer
+		offsets []int
+	)
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+
+	// 1 katalog, 2 sayfa ağacı, 3-4 fontlar, 5 kodlama, 6 bilgi, 7'den itibaren sayfa ve içerik çiftleri
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 7+i*2)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding 5 0 R >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding 5 0 R >>")
+	object(d.encoding.dictionary())
+
+	created := d.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+	object(fmt.Sprintf("<< /Title (%s) /Author (%s) /Producer (shift-scheduling) /CreationDate (D:%s) >>",
+		d.encoding.escape(d.Title), d.encoding.escape(d.Author), created.UTC().Format("20060102150405Z")))
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(d.width), num(d.height), 8+i*2))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (c Color) operands() string {
+	return num(c.R) + " " + num(c.G) + " " + num(c.B)
+}
+
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
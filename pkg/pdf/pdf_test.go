@@ -0,0 +1,78 @@
+package pdf_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"shift-scheduling-v2/pkg/pdf"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPDFWriteHasValidCrossReferenceTable(t *testing.T) {
+	doc := pdf.New(pdf.A4Height, pdf.A4Width)
+	doc.Title = "Acil Servis - Mart 2025"
+	doc.Created = time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	doc.AddPage().Text(28, 44, 15, true, pdf.Black, "Nöbet Çizelgesi")
+	doc.AddPage().Rect(10, 10, 100, 50, &pdf.White, 0.5, pdf.Black)
+
+	var buf bytes.Buffer
+	require.NoError(t, doc.Write(&buf))
+	out := buf.Bytes()
+
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, string(out), "/Count 2")
+	assert.Contains(t, string(out), "/CreationDate (D:20250301090000Z)")
+
+	// startxref xref tablosunu, tablodaki her kayıt kendi nesnesini göstermeli
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	require.NotNil(t, m)
+	xref, err := strconv.Atoi(string(m[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(out[xref:], []byte("xref\n0 ")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	require.Len(t, entries, 10)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(out[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+
+	// Akış uzunlukları içerikle tutarlı olmalı
+	for _, s := range regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)endstream`).FindAllSubmatch(out, -1) {
+		length, _ := strconv.Atoi(string(s[1]))
+		assert.Equal(t, length, len(s[2]))
+	}
+}
+
+func TestPDFEncodesTurkishCharacters(t *testing.T) {
+	doc := pdf.New(200, 100)
+	doc.AddPage().Text(0, 50, 10, false, pdf.Black, "Ağış İŞĞ çö (1) \\ 漢")
+
+	var buf bytes.Buffer
+	require.NoError(t, doc.Write(&buf))
+	out := buf.String()
+
+	assert.Contains(t, out, `(A\215\203\220 \235\217\201 \347\366 \(1\) \\ ?) Tj`)
+	assert.Contains(t, out, "/BaseEncoding /WinAnsiEncoding /Differences [ 129 /Gbreve 131 /dotlessi 141 /gbreve 143 /Scedilla 144 /scedilla 157 /Idotaccent ]")
+	// Sayfa koordinatları sol üstten verilir, PDF'e sol alttan yazılır
+	assert.Contains(t, out, "/F1 10 Tf 0 50 Td")
+}
+
+func TestPDFTextWidthAndFit(t *testing.T) {
+	assert.InDelta(t, 16.12, pdf.TextWidth("abc", 10, false), 0.001)
+	assert.InDelta(t, pdf.TextWidth("Sgo", 10, false), pdf.TextWidth("Şğö", 10, false), 0.001)
+	assert.Greater(t, pdf.TextWidth("Nöbet", 10, true), pdf.TextWidth("Nöbet", 10, false))
+
+	name := "Uzm. Dr. Abdurrahman Karaosmanoğlu"
+	assert.Equal(t, name, pdf.Fit(name, 7, false, 500))
+	fitted := pdf.Fit(name, 7, false, 80)
+	assert.LessOrEqual(t, pdf.TextWidth(fitted, 7, false), 80.0)
+	assert.Regexp(t, `^Uzm\. Dr\. .+\.\.\.$`, fitted)
+}
@@ -1,6 +1,9 @@
 package response
 
 import (
+	"errors"
+	"shift-scheduling-v2/pkg/errorx"
+
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -13,6 +16,7 @@ type Response struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
 	Message interface{} `json:"message,omitempty"`
+	Errors  interface{} `json:"errors,omitempty"`
 }
 
 // Başarılı yanıt oluşturmak için yardımcı fonksiyonlar
@@ -35,3 +39,26 @@ func SuccessNoData(c *fiber.Ctx) error {
 		Success: true,
 	})
 }
+
+// errorx hatalarını kendi HTTP kodlarıyla JSON olarak döner
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	var e *errorx.Error
+	if errors.As(err, &e) {
+		resp := Response{Success: false, Message: e.Message}
+		if len(e.Details) > 0 {
+			resp.Errors = e.Details
+		}
+		return c.Status(e.Code).JSON(resp)
+	}
+
+	code := fiber.StatusInternalServerError
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		code = fe.Code
+	}
+
+	return c.Status(code).JSON(Response{
+		Success: false,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,258 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Hücre biçimi. Fill ARGB renk kodudur (ör. "FFE7E6E6"), boşsa dolgu yapılmaz.
+type Style struct {
+	Bold bool
+	Fill string
+}
+
+// Value string ya da sayı (int, int64, float64) olabilir, nil boş hücredir
+type Cell struct {
+	Value any
+	Style Style
+}
+
+type Sheet struct {
+	Name      string
+	Rows      [][]Cell
+	ColWidths []float64 // sütun genişlikleri karakter cinsinden, 0 ise varsayılan
+	FreezeRow int       // üstte sabit kalacak satır sayısı
+	FreezeCol int       // solda sabit kalacak sütun sayısı
+}
+
+// Sayfaları Office Open XML çalışma kitabı olarak yazar. Metinler satır içi (inlineStr)
+// tutulduğundan paylaşılan metin tablosu oluşturulmaz.
+func Write(w io.Writer, sheets ...Sheet) error {
+	if len(sheets) == 0 {
+		return fmt.Errorf("xlsx: workbook needs at least one sheet")
+	}
+
+	styles := newStyleTable()
+	bodies := make([]string, len(sheets))
+	for i, sheet := range sheets {
+		bodies[i] = sheetXML(sheet, styles)
+	}
+
+	zw := zip.NewWriter(w)
+	files := []struct{ name, body string }{
+		{"[Content_Types].xml", contentTypesXML(len(sheets))},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", workbookXML(sheets)},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML(len(sheets))},
+		{"xl/styles.xml", styles.xml()},
+	}
+	for i, body := range bodies {
+		files = append(files, struct{ name, body string }{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), body})
+	}
+
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(fw, xml.Header+f.body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// 0 tabanlı sütun indeksini harf karşılığına çevirir (0 -> A, 26 -> AA)
+func ColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func sheetXML(sheet Sheet, styles *styleTable) string {
+	var b strings.Builder
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	if sheet.FreezeRow > 0 || sheet.FreezeCol > 0 {
+		b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane`)
+		if sheet.FreezeCol > 0 {
+			fmt.Fprintf(&b, ` xSplit="%d"`, sheet.FreezeCol)
+		}
+		if sheet.FreezeRow > 0 {
+			fmt.Fprintf(&b, ` ySplit="%d"`, sheet.FreezeRow)
+		}
+		fmt.Fprintf(&b, ` topLeftCell="%s%d" activePane="bottomRight" state="frozen"/></sheetView></sheetViews>`, ColumnName(sheet.FreezeCol), sheet.FreezeRow+1)
+	}
+
+	if len(sheet.ColWidths) > 0 {
+		b.WriteString(`<cols>`)
+		for i, width := range sheet.ColWidths {
+			if width > 0 {
+				fmt.Fprintf(&b, `<col min="%d" max="%d" width="%s" customWidth="1"/>`, i+1, i+1, strconv.FormatFloat(width, 'f', -1, 64))
+			}
+		}
+		b.WriteString(`</cols>`)
+	}
+
+	b.WriteString(`<sheetData>`)
+	for r, row := range sheet.Rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			ref := fmt.Sprintf("%s%d", ColumnName(c), r+1)
+			style := styles.index(cell.Style)
+			attr := fmt.Sprintf(`r="%s"`, ref)
+			if style > 0 {
+				attr += fmt.Sprintf(` s="%d"`, style)
+			}
+
+			switch v := cell.Value.(type) {
+			case nil:
+				if style > 0 {
+					fmt.Fprintf(&b, `<c %s/>`, attr)
+				}
+			case string:
+				fmt.Fprintf(&b, `<c %s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, attr, escape(v))
+			case int:
+				fmt.Fprintf(&b, `<c %s><v>%d</v></c>`, attr, v)
+			case int64:
+				fmt.Fprintf(&b, `<c %s><v>%d</v></c>`, attr, v)
+			case float64:
+				fmt.Fprintf(&b, `<c %s><v>%s</v></c>`, attr, strconv.FormatFloat(v, 'f', -1, 64))
+			default:
+				fmt.Fprintf(&b, `<c %s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, attr, escape(fmt.Sprint(v)))
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// Kullanılan biçimlerin tablosu. 0 varsayılan biçimdir; dolgu 0 ve 1 Excel için ayrılmıştır.
+type styleTable struct {
+	styles []Style
+	fills  []string
+}
+
+func newStyleTable() *styleTable {
+	return &styleTable{styles: []Style{{}}}
+}
+
+func (t *styleTable) index(s Style) int {
+	for i, existing := range t.styles {
+		if existing == s {
+			return i
+		}
+	}
+	if s.Fill != "" && !slices.Contains(t.fills, s.Fill) {
+		t.fills = append(t.fills, s.Fill)
+	}
+	t.styles = append(t.styles, s)
+	return len(t.styles) - 1
+}
+
+func (t *styleTable) xml() string {
+	var b strings.Builder
+	b.WriteString(`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>`)
+
+	fmt.Fprintf(&b, `<fills count="%d"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill>`, len(t.fills)+2)
+	for _, fill := range t.fills {
+		fmt.Fprintf(&b, `<fill><patternFill patternType="solid"><fgColor rgb="%s"/><bgColor indexed="64"/></patternFill></fill>`, escape(fill))
+	}
+	b.WriteString(`</fills>`)
+
+	b.WriteString(`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>`)
+	b.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+
+	fmt.Fprintf(&b, `<cellXfs count="%d">`, len(t.styles))
+	for _, s := range t.styles {
+		font, fill := 0, 0
+		if s.Bold {
+			font = 1
+		}
+		if s.Fill != "" {
+			fill = slices.Index(t.fills, s.Fill) + 2
+		}
+		fmt.Fprintf(&b, `<xf numFmtId="0" fontId="%d" fillId="%d" borderId="0" xfId="0"`, font, fill)
+		if font > 0 {
+			b.WriteString(` applyFont="1"`)
+		}
+		if fill > 0 {
+			b.WriteString(` applyFill="1"`)
+		}
+		b.WriteString(`/>`)
+	}
+	b.WriteString(`</cellXfs></styleSheet>`)
+	return b.String()
+}
+
+func contentTypesXML(sheets int) string {
+	var b strings.Builder
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+const rootRelsXML = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+func workbookXML(sheets []Sheet) string {
+	var b strings.Builder
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, sheet := range sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(sheetName(sheet.Name, i)), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func workbookRelsXML(sheets int) string {
+	var b strings.Builder
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheets+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// Excel sayfa adında []:*?/\ karakterlerine izin vermez ve adı 31 karakterle sınırlar
+func sheetName(name string, index int) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", index+1)
+	}
+	return name
+}
+
+func escape(value string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"shift-scheduling-v2/pkg/xlsx"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXLSXColumnName(t *testing.T) {
+	assert.Equal(t, "A", xlsx.ColumnName(0))
+	assert.Equal(t, "Z", xlsx.ColumnName(25))
+	assert.Equal(t, "AA", xlsx.ColumnName(26))
+	assert.Equal(t, "AG", xlsx.ColumnName(32))
+	assert.Equal(t, "ZZ", xlsx.ColumnName(701))
+	assert.Equal(t, "AAA", xlsx.ColumnName(702))
+}
+
+func TestXLSXWriteProducesWellFormedWorkbook(t *testing.T) {
+	weekend := xlsx.Style{Bold: true, Fill: "FFE7E6E6"}
+	sheet := xlsx.Sheet{
+		Name: "Mart/2025",
+		Rows: [][]xlsx.Cell{
+			{{Value: "Doktor", Style: xlsx.Style{Bold: true}}, {Value: "1 Cmt", Style: weekend}},
+			{{Value: "Ayşe <Yılmaz> & Co"}, {Value: 2}, {Value: 16.5}},
+			nil,
+			{{Style: weekend}},
+		},
+		ColWidths: []float64{24, 11},
+		FreezeRow: 1,
+		FreezeCol: 1,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, xlsx.Write(&buf, sheet))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		body, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(body)
+
+		// Her parça geçerli XML olmalı
+		dec := xml.NewDecoder(bytes.NewReader(body))
+		for {
+			if _, err := dec.Token(); err != nil {
+				require.ErrorIs(t, err, io.EOF, f.Name)
+				break
+			}
+		}
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		assert.Contains(t, files, name)
+	}
+
+	assert.Contains(t, files["xl/workbook.xml"], `name="Mart-2025"`)
+
+	ws := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, ws, `Ayşe &lt;Yılmaz&gt; &amp; Co`)
+	assert.Contains(t, ws, `<c r="B2"><v>2</v></c>`)
+	assert.Contains(t, ws, `<c r="C2"><v>16.5</v></c>`)
+	assert.Contains(t, ws, `state="frozen"`)
+	assert.Contains(t, ws, `<c r="A4" s="2"/>`)
+
+	styles := files["xl/styles.xml"]
+	assert.Contains(t, styles, `<fills count="3">`)
+	assert.Contains(t, styles, `rgb="FFE7E6E6"`)
+	assert.Equal(t, 1, strings.Count(styles, `rgb="FFE7E6E6"`))
+	assert.Contains(t, styles, `<cellXfs count="3">`)
+}
+
+func TestXLSXWriteNeedsSheet(t *testing.T) {
+	assert.Error(t, xlsx.Write(io.Discard))
+}